@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+var (
+	// ArticleViewWindow is how long repeated reads by the same user count as one view.
+	ArticleViewWindow = getDurationEnv("ARTICLE_VIEW_WINDOW", 30*time.Minute)
+	// AnalyticsRollupInterval is how often the daily article stats are recomputed.
+	AnalyticsRollupInterval = getDurationEnv("ANALYTICS_ROLLUP_INTERVAL", 15*time.Minute)
+)
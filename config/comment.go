@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+var (
+	// CommentMaxDepth is how many levels of replies a comment thread may have.
+	CommentMaxDepth = getIntEnv("COMMENT_MAX_DEPTH", 3)
+	// CommentEditWindow is how long after posting the author may still edit a comment.
+	CommentEditWindow = getDurationEnv("COMMENT_EDIT_WINDOW", 15*time.Minute)
+)
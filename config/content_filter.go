@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+var (
+	// ContentFilterWordLists names the built-in word lists to load, e.g. "en,id".
+	ContentFilterWordLists = getEnv("CONTENT_FILTER_WORD_LISTS", "en,id")
+	// ContentFilterWordListDir may hold <name>.txt files that replace or add to the built-in lists.
+	ContentFilterWordListDir = getEnv("CONTENT_FILTER_WORD_LIST_DIR", "")
+	// ContentFilterWordVerdict is what happens to text containing a listed word: "hold" or "reject".
+	ContentFilterWordVerdict = getEnv("CONTENT_FILTER_WORD_VERDICT", "hold")
+
+	// ContentFilterMaxCommentLinks and ContentFilterMaxArticleLinks are the most links
+	// a comment or article may carry before it is held for moderation.
+	ContentFilterMaxCommentLinks = getIntEnv("CONTENT_FILTER_MAX_COMMENT_LINKS", 2)
+	ContentFilterMaxArticleLinks = getIntEnv("CONTENT_FILTER_MAX_ARTICLE_LINKS", 20)
+	// ContentFilterMaxRepeatedChars is the longest run of one character allowed, as in "!!!!!!".
+	ContentFilterMaxRepeatedChars = getIntEnv("CONTENT_FILTER_MAX_REPEATED_CHARS", 12)
+
+	// ContentFilterRateLimit is how many comments, and separately articles, a user
+	// may post within ContentFilterRateWindow.
+	ContentFilterRateLimit  = getIntEnv("CONTENT_FILTER_RATE_LIMIT", 5)
+	ContentFilterRateWindow = getDurationEnv("CONTENT_FILTER_RATE_WINDOW", time.Minute)
+)
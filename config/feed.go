@@ -0,0 +1,12 @@
+package config
+
+var (
+	// FeedSiteUrl is where readers open articles, usually the frontend.
+	FeedSiteUrl = getEnv("FEED_SITE_URL", "http://localhost:5173")
+	// FeedBaseUrl is the public address of this API, used for feed self links.
+	FeedBaseUrl = getEnv("FEED_BASE_URL", "http://localhost:3000")
+	FeedTitle   = getEnv("FEED_TITLE", "uaspw2")
+	// FeedContent is "full" to publish the rendered article or "summary" for
+	// the description only.
+	FeedContent = getEnv("FEED_CONTENT", "summary")
+)
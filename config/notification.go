@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+var (
+	// NotificationDedupeWindow is how long a repeated like, reaction or new
+	// article notification from the same actor about the same article is
+	// dropped instead of stored again.
+	NotificationDedupeWindow = getDurationEnv("NOTIFICATION_DEDUPE_WINDOW", 24*time.Hour)
+)
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+var (
+	// RealtimeBroker is "local" for a single replica or "redis" to fan events
+	// out to every replica through Redis pub/sub.
+	RealtimeBroker        = getEnv("REALTIME_BROKER", "local")
+	RealtimeRedisAddr     = getEnv("REALTIME_REDIS_ADDR", "localhost:6379")
+	RealtimeRedisPassword = getEnv("REALTIME_REDIS_PASSWORD", "")
+	RealtimeRedisPrefix   = getEnv("REALTIME_REDIS_PREFIX", "uaspw2:")
+
+	// RealtimeHeartbeat is how often idle streams are pinged. A WebSocket
+	// client silent for two heartbeats is disconnected.
+	RealtimeHeartbeat = getDurationEnv("REALTIME_HEARTBEAT", 25*time.Second)
+	// RealtimeBufferSize is how many events may queue for one client before
+	// it is considered too slow and disconnected.
+	RealtimeBufferSize   = getIntEnv("REALTIME_BUFFER_SIZE", 64)
+	RealtimeWriteTimeout = getDurationEnv("REALTIME_WRITE_TIMEOUT", 10*time.Second)
+	RealtimeMaxArticles  = getIntEnv("REALTIME_MAX_ARTICLES", 20)
+)
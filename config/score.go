@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+var (
+	// ArticleScoreRefreshInterval is how often the feed ranking scores are recomputed.
+	ArticleScoreRefreshInterval = getDurationEnv("ARTICLE_SCORE_REFRESH_INTERVAL", 10*time.Minute)
+	// ArticleTrendingHalfLife is the age at which a like, comment or view counts half.
+	ArticleTrendingHalfLife = getDurationEnv("ARTICLE_TRENDING_HALF_LIFE", 48*time.Hour)
+)
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+var (
+	StorageDriver   = getEnv("STORAGE_DRIVER", "local")
+	StorageLocalDir = getEnv("STORAGE_LOCAL_DIR", "./public")
+	StorageLocalUrl = getEnv("STORAGE_LOCAL_URL", "")
+
+	S3Endpoint     = getEnv("S3_ENDPOINT", "http://localhost:9000")
+	S3Region       = getEnv("S3_REGION", "us-east-1")
+	S3Bucket       = getEnv("S3_BUCKET", "uaspw2")
+	S3AccessKey    = getEnv("S3_ACCESS_KEY", "")
+	S3SecretKey    = getEnv("S3_SECRET_KEY", "")
+	S3PublicUrl    = getEnv("S3_PUBLIC_URL", "")
+	S3UsePathStyle = getEnv("S3_USE_PATH_STYLE", "true") == "true"
+	S3SignUrls     = getEnv("S3_SIGN_URLS", "false") == "true"
+	S3UrlExpiry    = getDurationEnv("S3_URL_EXPIRY", 15*time.Minute)
+)
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+var (
+	// WebhookPollInterval is how often the delivery queue is checked for due
+	// deliveries.
+	WebhookPollInterval = getDurationEnv("WEBHOOK_POLL_INTERVAL", 10*time.Second)
+	WebhookTimeout      = getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second)
+	WebhookBatchSize    = getIntEnv("WEBHOOK_BATCH_SIZE", 20)
+	// A failed delivery is retried after WebhookRetryBase, then twice as long
+	// each time up to WebhookRetryMax, and given up after WebhookMaxAttempts.
+	WebhookRetryBase   = getDurationEnv("WEBHOOK_RETRY_BASE", 30*time.Second)
+	WebhookRetryMax    = getDurationEnv("WEBHOOK_RETRY_MAX", 6*time.Hour)
+	WebhookMaxAttempts = getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8)
+)
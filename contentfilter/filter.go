@@ -0,0 +1,116 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"uaspw2/config"
+)
+
+// Verdict is the outcome of a filter. A higher verdict is more severe.
+type Verdict int
+
+const (
+	Allow Verdict = iota
+	Hold
+	Reject
+)
+
+func (verdict Verdict) String() string {
+	switch verdict {
+	case Hold:
+		return "hold"
+	case Reject:
+		return "reject"
+	default:
+		return "allow"
+	}
+}
+
+// ParseVerdict reads "hold" or "reject", anything else is Allow.
+func ParseVerdict(value string) Verdict {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "hold":
+		return Hold
+	case "reject":
+		return Reject
+	default:
+		return Allow
+	}
+}
+
+// Content is a piece of user text about to be stored.
+type Content struct {
+	UserId int
+	// Kind is "article" or "comment".
+	Kind string
+	Text string
+	// IsEdit is set when existing content is being changed rather than posted.
+	IsEdit bool
+}
+
+type Result struct {
+	Verdict Verdict
+	Reasons []string
+}
+
+type Filter interface {
+	Check(ctx context.Context, content Content) Result
+}
+
+// Recorder is implemented by filters that keep track of accepted content,
+// such as the rate limit. Record is called once the content is stored.
+type Recorder interface {
+	Record(content Content)
+}
+
+// Record tells filter that content was stored, if the filter keeps count.
+func Record(filter Filter, content Content) {
+	if recorder, ok := filter.(Recorder); ok {
+		recorder.Record(content)
+	}
+}
+
+// Pipeline runs filters in order and keeps the most severe verdict. It stops
+// at the first rejection.
+type Pipeline struct {
+	filters []Filter
+}
+
+func NewPipeline(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters}
+}
+
+func (pipeline *Pipeline) Check(ctx context.Context, content Content) Result {
+	result := Result{Verdict: Allow}
+	for _, filter := range pipeline.filters {
+		next := filter.Check(ctx, content)
+		if next.Verdict > result.Verdict {
+			result.Verdict = next.Verdict
+		}
+		result.Reasons = append(result.Reasons, next.Reasons...)
+		if result.Verdict == Reject {
+			break
+		}
+	}
+	return result
+}
+
+func (pipeline *Pipeline) Record(content Content) {
+	for _, filter := range pipeline.filters {
+		Record(filter, content)
+	}
+}
+
+func NewFilter() Filter {
+	return NewPipeline(
+		NewWordListFilter(LoadWordLists(strings.Split(config.ContentFilterWordLists, ","), config.ContentFilterWordListDir), ParseVerdict(config.ContentFilterWordVerdict)),
+		&LinkFilter{
+			MaxLinks: map[string]int{
+				"comment": config.ContentFilterMaxCommentLinks,
+				"article": config.ContentFilterMaxArticleLinks,
+			},
+		},
+		&RepetitionFilter{MaxRepeatedChars: config.ContentFilterMaxRepeatedChars, MaxWordShare: 0.4},
+		NewRateLimitFilter(config.ContentFilterRateLimit, config.ContentFilterRateWindow),
+	)
+}
@@ -0,0 +1,219 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRepetitionFilter(t *testing.T) {
+	filter := &RepetitionFilter{MaxRepeatedChars: 5, MaxWordShare: 0.4}
+
+	tests := []struct {
+		name string
+		text string
+		want Verdict
+	}{
+		{"plain prose", "A short comment about the article.", Allow},
+		{"repeated letters", "Nooooooooo way", Hold},
+		{"repeated punctuation", "Really!!!!!!!!", Hold},
+		{"repeated word", "buy buy buy buy buy now buy cheap buy buy pills", Hold},
+		{"short text with a repeated word", "ha ha ha", Allow},
+		{"horizontal rule", "First part\n\n----------\n\nSecond part", Allow},
+		{"starred rule", "First part\n\n* * * * * * *\n\nSecond part", Allow},
+		{"setext heading", "Title\n==========\n\nSubtitle\n----------", Allow},
+		{"table delimiter row", "| a | b |\n|---------|:--------:|\n| 1 | 2 |", Allow},
+		{"table without outer pipes", "a | b\n---------- | ----------\n1 | 2", Allow},
+		{"fenced code", "Look:\n\n```\n// ==========\nx := \"aaaaaaaaaa\"\n```\n", Allow},
+		{"tilde fence", "~~~go\nfor {}}}}}}}}\n~~~", Allow},
+		{"indented code", "Example:\n\n    ==========\n", Allow},
+		{"inline code", "Use `==========` as a divider", Allow},
+		{"prose after a code block", "```\ncode\n```\nsoooooooo good", Hold},
+		{"repeated word in code", "```\nx x x x x x x x x x x x\n```\nThe loop above is fine really.", Allow},
+		{"dashes inside a sentence", "wait----------what", Hold},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := filter.Check(context.Background(), Content{Kind: "article", Text: test.text})
+			if result.Verdict != test.want {
+				t.Errorf("verdict = %s %v, want %s", result.Verdict, result.Reasons, test.want)
+			}
+		})
+	}
+}
+
+func TestLinkFilter(t *testing.T) {
+	filter := &LinkFilter{MaxLinks: map[string]int{"comment": 1, "article": 3}}
+
+	tests := []struct {
+		name    string
+		kind    string
+		text    string
+		want    Verdict
+		reasons int
+	}{
+		{"no links", "comment", "hello", Allow, 0},
+		{"one link in a comment", "comment", "see https://example.com", Allow, 0},
+		{"two links in a comment", "comment", "https://a.example and www.b.example", Hold, 1},
+		{"two links in an article", "article", "https://a.example and www.b.example", Allow, 0},
+		{"unknown kind", "profile", "https://a.example https://b.example", Allow, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := filter.Check(context.Background(), Content{Kind: test.kind, Text: test.text})
+			if result.Verdict != test.want || len(result.Reasons) != test.reasons {
+				t.Errorf("result = %s %v, want %s with %d reasons", result.Verdict, result.Reasons, test.want, test.reasons)
+			}
+		})
+	}
+}
+
+func TestWordListFilter(t *testing.T) {
+	filter := NewWordListFilter([]string{"spam", "bad phrase", "  "}, Reject)
+
+	tests := []struct {
+		name string
+		text string
+		want Verdict
+	}{
+		{"clean", "a perfectly fine text", Allow},
+		{"word", "this is spam", Reject},
+		{"case and punctuation", "SPAM!!!", Reject},
+		{"letter substitutions", "5p4m", Reject},
+		{"phrase across punctuation", "a bad, phrase here", Reject},
+		{"word inside another word", "spammer", Allow},
+		{"partial phrase", "bad news", Allow},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := filter.Check(context.Background(), Content{Kind: "comment", Text: test.text})
+			if result.Verdict != test.want {
+				t.Errorf("verdict = %s, want %s", result.Verdict, test.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinWordLists(t *testing.T) {
+	for _, name := range []string{"en", "id"} {
+		if len(LoadWordLists([]string{name}, "")) == 0 {
+			t.Errorf("word list %s is empty", name)
+		}
+	}
+	if words := LoadWordLists([]string{"missing", " "}, ""); len(words) != 0 {
+		t.Errorf("unknown lists loaded %v", words)
+	}
+}
+
+type fixedFilter struct {
+	result Result
+	calls  int
+}
+
+func (filter *fixedFilter) Check(ctx context.Context, content Content) Result {
+	filter.calls++
+	return filter.result
+}
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []Result
+		want    Verdict
+		reasons string
+		calls   []int
+	}{
+		{"all allow", []Result{{Verdict: Allow}, {Verdict: Allow}}, Allow, "", []int{1, 1}},
+		{"most severe wins", []Result{{Verdict: Hold, Reasons: []string{"a"}}, {Verdict: Allow}}, Hold, "a", []int{1, 1}},
+		{"reasons are collected", []Result{{Verdict: Hold, Reasons: []string{"a"}}, {Verdict: Hold, Reasons: []string{"b"}}}, Hold, "a,b", []int{1, 1}},
+		{"stops at reject", []Result{{Verdict: Reject, Reasons: []string{"r"}}, {Verdict: Hold, Reasons: []string{"h"}}}, Reject, "r", []int{1, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var filters []Filter
+			var fixed []*fixedFilter
+			for _, result := range test.results {
+				filter := &fixedFilter{result: result}
+				filters = append(filters, filter)
+				fixed = append(fixed, filter)
+			}
+
+			result := NewPipeline(filters...).Check(context.Background(), Content{})
+			if result.Verdict != test.want || strings.Join(result.Reasons, ",") != test.reasons {
+				t.Errorf("result = %s %v, want %s %s", result.Verdict, result.Reasons, test.want, test.reasons)
+			}
+			for i, filter := range fixed {
+				if filter.calls != test.calls[i] {
+					t.Errorf("filter %d called %d times, want %d", i, filter.calls, test.calls[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitFilter(t *testing.T) {
+	filter := NewRateLimitFilter(2, time.Hour)
+	post := Content{UserId: 1, Kind: "comment"}
+
+	for i := 0; i < 2; i++ {
+		if result := filter.Check(context.Background(), post); result.Verdict != Allow {
+			t.Fatalf("post %d: verdict = %s", i+1, result.Verdict)
+		}
+		filter.Record(post)
+	}
+
+	tests := []struct {
+		name    string
+		content Content
+		want    Verdict
+	}{
+		{"over the limit", post, Reject},
+		{"edit", Content{UserId: 1, Kind: "comment", IsEdit: true}, Allow},
+		{"other kind", Content{UserId: 1, Kind: "article"}, Allow},
+		{"other user", Content{UserId: 2, Kind: "comment"}, Allow},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := filter.Check(context.Background(), test.content); result.Verdict != test.want {
+				t.Errorf("verdict = %s, want %s", result.Verdict, test.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitFilterCountsRecordedPostsOnly(t *testing.T) {
+	filter := NewRateLimitFilter(1, time.Hour)
+	post := Content{UserId: 1, Kind: "comment"}
+
+	// checked but never stored, e.g. because the transaction failed
+	for i := 0; i < 3; i++ {
+		if result := filter.Check(context.Background(), post); result.Verdict != Allow {
+			t.Fatalf("check %d: verdict = %s", i+1, result.Verdict)
+		}
+	}
+
+	filter.Record(post)
+	if result := filter.Check(context.Background(), post); result.Verdict != Reject {
+		t.Errorf("verdict after a recorded post = %s, want reject", result.Verdict)
+	}
+}
+
+func TestRateLimitFilterWindow(t *testing.T) {
+	filter := NewRateLimitFilter(1, time.Hour)
+	post := Content{UserId: 1, Kind: "comment"}
+	filter.posts["comment:1"] = []time.Time{time.Now().Add(-2 * time.Hour)}
+
+	if result := filter.Check(context.Background(), post); result.Verdict != Allow {
+		t.Errorf("verdict = %s, want posts outside the window to be forgotten", result.Verdict)
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	tests := map[string]Verdict{"hold": Hold, " Reject ": Reject, "allow": Allow, "": Allow, "other": Allow}
+	for value, want := range tests {
+		if got := ParseVerdict(value); got != want {
+			t.Errorf("ParseVerdict(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
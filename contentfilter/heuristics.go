@@ -0,0 +1,104 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkFilter holds content with more links than MaxLinks allows for its kind.
+type LinkFilter struct {
+	MaxLinks map[string]int
+}
+
+func (filter *LinkFilter) Check(ctx context.Context, content Content) Result {
+	maxLinks, ok := filter.MaxLinks[content.Kind]
+	if !ok {
+		return Result{Verdict: Allow}
+	}
+	if links := len(linkPattern.FindAllString(content.Text, -1)); links > maxLinks {
+		return Result{Verdict: Hold, Reasons: []string{fmt.Sprintf("contains %d links", links)}}
+	}
+	return Result{Verdict: Allow}
+}
+
+// markdownStructurePattern matches lines that only draw Markdown structure:
+// horizontal rules, setext heading underlines and table delimiter rows.
+var markdownStructurePattern = regexp.MustCompile(`^(?:(?:[-*_=][ \t]*){3,}|\|?(?:[ \t]*:?-+:?[ \t]*\|)+(?:[ \t]*:?-+:?[ \t]*)?)$`)
+
+var codeSpanPattern = regexp.MustCompile("`+[^`]*`+")
+
+// RepetitionFilter holds content that repeats one character more than
+// MaxRepeatedChars times in a row, or where a single word makes up more than
+// MaxWordShare of a text of at least ten words. Code and Markdown structure
+// are not counted, only the prose around them.
+type RepetitionFilter struct {
+	MaxRepeatedChars int
+	MaxWordShare     float64
+}
+
+func (filter *RepetitionFilter) Check(ctx context.Context, content Content) Result {
+	var reasons []string
+	text := markdownProse(content.Text)
+
+	run, longest := 0, 0
+	var previous rune
+	for _, r := range text {
+		if r == previous {
+			run++
+		} else {
+			previous, run = r, 1
+		}
+		if run > longest && r != ' ' && r != '\n' {
+			longest = run
+		}
+	}
+	if filter.MaxRepeatedChars > 0 && longest > filter.MaxRepeatedChars {
+		reasons = append(reasons, "repeats a character too many times")
+	}
+
+	words := strings.Fields(normalizeText(text))
+	if filter.MaxWordShare > 0 && len(words) >= 10 {
+		counts := map[string]int{}
+		for _, word := range words {
+			counts[word]++
+			if float64(counts[word]) > filter.MaxWordShare*float64(len(words)) {
+				reasons = append(reasons, "repeats the same word too often")
+				break
+			}
+		}
+	}
+
+	if len(reasons) > 0 {
+		return Result{Verdict: Hold, Reasons: reasons}
+	}
+	return Result{Verdict: Allow}
+}
+
+// markdownProse drops fenced and indented code blocks, inline code and
+// structure-only lines from Markdown text.
+func markdownProse(text string) string {
+	var prose []string
+	fence := ""
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") || markdownStructurePattern.MatchString(trimmed) {
+			continue
+		}
+		prose = append(prose, codeSpanPattern.ReplaceAllString(line, " "))
+	}
+	return strings.Join(prose, "\n")
+}
@@ -0,0 +1,73 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitFilter rejects new content from a user who already posted Limit
+// items of the same kind within Window. Only content passed to Record counts,
+// and edits are not counted. The counts live in memory, so each server
+// instance limits on its own.
+type RateLimitFilter struct {
+	Limit  int
+	Window time.Duration
+
+	mutex sync.Mutex
+	posts map[string][]time.Time
+}
+
+func NewRateLimitFilter(limit int, window time.Duration) *RateLimitFilter {
+	return &RateLimitFilter{
+		Limit:  limit,
+		Window: window,
+		posts:  map[string][]time.Time{},
+	}
+}
+
+func (filter *RateLimitFilter) Check(ctx context.Context, content Content) Result {
+	if content.IsEdit || filter.Limit <= 0 {
+		return Result{Verdict: Allow}
+	}
+
+	filter.mutex.Lock()
+	defer filter.mutex.Unlock()
+
+	key := rateLimitKey(content)
+	filter.posts[key] = filter.recent(key)
+	if len(filter.posts[key]) >= filter.Limit {
+		return Result{Verdict: Reject, Reasons: []string{fmt.Sprintf("posting too fast, at most %d per %s", filter.Limit, filter.Window)}}
+	}
+	return Result{Verdict: Allow}
+}
+
+func (filter *RateLimitFilter) Record(content Content) {
+	if content.IsEdit || filter.Limit <= 0 {
+		return
+	}
+
+	filter.mutex.Lock()
+	defer filter.mutex.Unlock()
+
+	key := rateLimitKey(content)
+	filter.posts[key] = append(filter.recent(key), time.Now())
+}
+
+// recent returns the posts under key that are still inside the window.
+func (filter *RateLimitFilter) recent(key string) []time.Time {
+	now := time.Now()
+	var recent []time.Time
+	for _, postedAt := range filter.posts[key] {
+		if now.Sub(postedAt) < filter.Window {
+			recent = append(recent, postedAt)
+		}
+	}
+	return recent
+}
+
+func rateLimitKey(content Content) string {
+	return content.Kind + ":" + strconv.Itoa(content.UserId)
+}
@@ -0,0 +1,89 @@
+package contentfilter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"github.com/gofiber/fiber/v2/log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+//go:embed wordlists/*.txt
+var builtinWordLists embed.FS
+
+// LoadWordLists reads the named lists, one word or phrase per line with "#"
+// comments. A <name>.txt file in dir takes the place of the built-in list of
+// the same name; unknown names are logged and skipped.
+func LoadWordLists(names []string, dir string) []string {
+	var words []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		var data []byte
+		var err error
+		if dir != "" {
+			data, err = os.ReadFile(filepath.Join(dir, name+".txt"))
+		}
+		if dir == "" || err != nil {
+			data, err = builtinWordLists.ReadFile("wordlists/" + name + ".txt")
+		}
+		if err != nil {
+			log.Warnf("content filter: word list %q not found", name)
+			continue
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			words = append(words, line)
+		}
+	}
+	return words
+}
+
+// WordListFilter matches whole words and phrases, ignoring case, punctuation
+// and common letter substitutions such as "4" for "a".
+type WordListFilter struct {
+	words   []string
+	verdict Verdict
+}
+
+func NewWordListFilter(words []string, verdict Verdict) *WordListFilter {
+	filter := &WordListFilter{verdict: verdict}
+	for _, word := range words {
+		if normalized := normalizeText(word); normalized != "" {
+			filter.words = append(filter.words, " "+normalized+" ")
+		}
+	}
+	return filter
+}
+
+func (filter *WordListFilter) Check(ctx context.Context, content Content) Result {
+	text := " " + normalizeText(content.Text) + " "
+	for _, word := range filter.words {
+		if strings.Contains(text, word) {
+			return Result{Verdict: filter.verdict, Reasons: []string{"contains a blocked word"}}
+		}
+	}
+	return Result{Verdict: Allow}
+}
+
+var substitutions = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// normalizeText lower-cases text and reduces it to words separated by single spaces.
+func normalizeText(text string) string {
+	text = substitutions.Replace(strings.ToLower(text))
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type ArticleAnalyticsController interface {
+	FindByToken(c *fiber.Ctx) error
+	FindByArticleID(c *fiber.Ctx) error
+}
+
+type ArticleAnalyticsControllerImpl struct {
+	services.ArticleAnalyticsService
+}
+
+func NewArticleAnalyticsController(articleAnalyticsService services.ArticleAnalyticsService) ArticleAnalyticsController {
+	return &ArticleAnalyticsControllerImpl{
+		ArticleAnalyticsService: articleAnalyticsService,
+	}
+}
+
+func (controller *ArticleAnalyticsControllerImpl) FindByToken(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ArticleAnalyticsRequest{
+		UserId: user.Id,
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
+
+	data := controller.ArticleAnalyticsService.FindByUserID(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article analytics retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ArticleAnalyticsControllerImpl) FindByArticleID(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ArticleAnalyticsRequest{
+		ArticleId: helper.ToIntFromParams(c.Params("articleId")),
+		UserId:    user.Id,
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+	}
+
+	data := controller.ArticleAnalyticsService.FindByArticleID(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article analytics retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -3,43 +3,83 @@ package controllers
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
+	"strings"
+	"uaspw2/exception"
 	"uaspw2/helper"
 	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
 	"uaspw2/services"
 )
 
 type ArticleController interface {
 	CreateByToken(c *fiber.Ctx) error
 	UpdateByID(c *fiber.Ctx) error
+	PatchByID(c *fiber.Ctx) error
 	DeleteByID(c *fiber.Ctx) error
 	FindByID(c *fiber.Ctx) error
+	FindBySlug(c *fiber.Ctx) error
 	FindAllPublished(c *fiber.Ctx) error
+	FindFeed(c *fiber.Ctx) error
+	FindTimeline(c *fiber.Ctx) error
 	FindAllPublishedByUserID(c *fiber.Ctx) error
 	FindAllUnpublished(c *fiber.Ctx) error
 	FindAllUnpublishedByUserID(c *fiber.Ctx) error
 	PublishArticle(c *fiber.Ctx) error
 	UnpublishArticle(c *fiber.Ctx) error
+	CreateMedia(c *fiber.Ctx) error
+	DeleteMedia(c *fiber.Ctx) error
 }
 
 type ArticleControllerImpl struct {
 	services.ArticleService
+	ArticleAnalyticsService services.ArticleAnalyticsService
+	BookmarkService         services.BookmarkService
+	LikeService             services.LikeService
 }
 
-func NewArticleController(articleService services.ArticleService) ArticleController {
+func NewArticleController(articleService services.ArticleService, articleAnalyticsService services.ArticleAnalyticsService, bookmarkService services.BookmarkService, likeService services.LikeService) ArticleController {
 	return &ArticleControllerImpl{
-		ArticleService: articleService,
+		ArticleService:          articleService,
+		ArticleAnalyticsService: articleAnalyticsService,
+		BookmarkService:         bookmarkService,
+		LikeService:             likeService,
 	}
 }
 
+// markForUser flags the articles the current user has bookmarked or liked.
+func (controller *ArticleControllerImpl) markForUser(c *fiber.Ctx, articles []response.ArticleResponse) {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	controller.BookmarkService.MarkBookmarked(c.Context(), user.Id, articles)
+	controller.LikeService.MarkLiked(c.Context(), user.Id, articles)
+}
+
+// recordView counts a read of article for analytics. Answers with 304 Not
+// Modified are not counted, the client had already read that version.
+func (controller *ArticleControllerImpl) recordView(c *fiber.Ctx, userId int, article response.ArticleResponse) {
+	controller.ArticleAnalyticsService.RecordView(c.Context(), request.ArticleViewCreateRequest{
+		ArticleId: article.Id,
+		AuthorId:  article.UserId,
+		UserId:    userId,
+	})
+}
+
 func (controller *ArticleControllerImpl) PublishArticle(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
 	articleId := helper.ToIntFromParams(c.Params("articleId"))
-	controller.ArticleService.UpdateStatusArticleByID(c.Context(), articleId, true)
+	controller.ArticleService.UpdateStatusArticleByID(c.Context(), articleId, true, user.Id)
 	return c.SendStatus(fiber.StatusOK)
 }
 
 func (controller *ArticleControllerImpl) UnpublishArticle(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
 	articleId := helper.ToIntFromParams(c.Params("articleId"))
-	controller.ArticleService.UpdateStatusArticleByID(c.Context(), articleId, false)
+	controller.ArticleService.UpdateStatusArticleByID(c.Context(), articleId, false, user.Id)
 	return c.SendStatus(fiber.StatusOK)
 }
 
@@ -54,9 +94,7 @@ func (controller *ArticleControllerImpl) CreateByToken(c *fiber.Ctx) error {
 	req.UserId = user.Id
 	req.IsPublished = false
 
-	mediaRequests := req.Media
-
-	article := controller.ArticleService.Create(c.Context(), req, mediaRequests)
+	article := controller.ArticleService.Create(c.Context(), req)
 
 	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "article created successfully", article)
 	return c.Status(webResponse.Code).JSON(webResponse)
@@ -74,6 +112,7 @@ func (controller *ArticleControllerImpl) UpdateByID(c *fiber.Ctx) error {
 
 	req.UserId = user.Id
 	req.Id = articleId
+	req.Version = helper.IfMatchVersion(c)
 
 	if user.Role != "admin" && req.IsPublished == true {
 		webResponse := helper.CreateErrorResponse(fiber.StatusUnauthorized, "unauthorized", "you are not allowed to publish this article")
@@ -83,6 +122,42 @@ func (controller *ArticleControllerImpl) UpdateByID(c *fiber.Ctx) error {
 	log.Info(req)
 
 	article := controller.ArticleService.Update(c.Context(), req)
+	c.Set(fiber.HeaderETag, helper.ETag(article.Version))
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article updated successfully", article)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+// PatchByID takes an application/merge-patch+json body, or a multipart form
+// with the patch in the "patch" field and new files in "media".
+func (controller *ArticleControllerImpl) PatchByID(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ArticlePatchRequest{
+		Id:      helper.ToIntFromParams(c.Params("articleId")),
+		UserId:  user.Id,
+		Version: helper.IfMatchVersion(c),
+	}
+
+	switch {
+	case c.Is("multipart"):
+		form, err := c.MultipartForm()
+		helper.PanicIfErr(err)
+		if patch := form.Value["patch"]; len(patch) > 0 {
+			req.Patch = []byte(patch[0])
+		} else {
+			req.Patch = []byte("{}")
+		}
+		req.Media = form.File["media"]
+	case strings.HasPrefix(c.Get(fiber.HeaderContentType), "application/merge-patch+json"), c.Is("json"):
+		req.Patch = c.Body()
+	default:
+		panic(exception.NewInvalidParameter("unsupported content type (application/merge-patch+json or multipart/form-data)"))
+	}
+
+	article := controller.ArticleService.Patch(c.Context(), req)
+	c.Set(fiber.HeaderETag, helper.ETag(article.Version))
 
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article updated successfully", article)
 	return c.Status(webResponse.Code).JSON(webResponse)
@@ -98,30 +173,95 @@ func (controller *ArticleControllerImpl) DeleteByID(c *fiber.Ctx) error {
 }
 
 func (controller *ArticleControllerImpl) FindByID(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
 	articleId := helper.ToIntFromParams(c.Params("articleId"))
 
 	articles := controller.ArticleService.FindByID(c.Context(), articleId)
+	marked := []response.ArticleResponse{articles}
+	controller.markForUser(c, marked)
+	articles = marked[0]
+	if helper.SetContentETag(c, articles.Version, articles) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	controller.recordView(c, user.Id, articles)
+
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article found", articles)
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
 
+func (controller *ArticleControllerImpl) FindBySlug(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+
+	article := controller.ArticleService.FindBySlug(c.Context(), slug)
+	if article.Slug != slug {
+		return c.Redirect("/api/articles/slug/"+article.Slug, fiber.StatusMovedPermanently)
+	}
+	marked := []response.ArticleResponse{article}
+	controller.markForUser(c, marked)
+	article = marked[0]
+	if helper.SetContentETag(c, article.Version, article) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+	controller.recordView(c, user.Id, article)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article found", article)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
 func (controller *ArticleControllerImpl) FindAllPublished(c *fiber.Ctx) error {
 	articles := controller.ArticleService.FindAllPublished(c.Context())
+	controller.markForUser(c, articles)
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "published articles list retrieved successfully", articles)
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
 
+func (controller *ArticleControllerImpl) FindFeed(c *fiber.Ctx) error {
+	req := request.ArticleFeedRequest{
+		Sort:    c.Query("sort", "trending"),
+		Period:  c.Query("period", "week"),
+		Page:    c.QueryInt("page", 1),
+		PerPage: c.QueryInt("per_page", 20),
+	}
+
+	feed := controller.ArticleService.FindFeed(c.Context(), req)
+	controller.markForUser(c, feed.Articles)
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article feed retrieved successfully", feed)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ArticleControllerImpl) FindTimeline(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.TimelineRequest{
+		UserId: user.Id,
+		Cursor: c.Query("cursor"),
+		Limit:  c.QueryInt("limit", 20),
+	}
+
+	timeline := controller.ArticleService.FindTimeline(c.Context(), req)
+	controller.markForUser(c, timeline.Articles)
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "timeline retrieved successfully", timeline)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
 func (controller *ArticleControllerImpl) FindAllPublishedByUserID(c *fiber.Ctx) error {
 	user, err := helper.GetUserByToken(c)
 	helper.PanicIfErr(err)
 
 	articles := controller.ArticleService.FindAllPublishedByUserID(c.Context(), user.Id)
+	controller.markForUser(c, articles)
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "published articles list by user retrieved successfully", articles)
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
 
 func (controller *ArticleControllerImpl) FindAllUnpublished(c *fiber.Ctx) error {
 	articles := controller.ArticleService.FindAllUnpublished(c.Context())
+	controller.markForUser(c, articles)
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "unpublished articles list retrieved successfully", articles)
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
@@ -131,6 +271,41 @@ func (controller *ArticleControllerImpl) FindAllUnpublishedByUserID(c *fiber.Ctx
 	helper.PanicIfErr(err)
 
 	articles := controller.ArticleService.FindAllUnpublishedByUserID(c.Context(), user.Id)
+	controller.markForUser(c, articles)
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "unpublished articles list by user retrieved successfully", articles)
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
+
+func (controller *ArticleControllerImpl) CreateMedia(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	articleId := helper.ToIntFromParams(c.Params("articleId"))
+
+	file, err := c.FormFile("media")
+	helper.PanicIfErr(err)
+
+	req := request.ArticleMediaCreateRequest{
+		ArticleId: articleId,
+		UserId:    user.Id,
+		File:      file,
+	}
+
+	media := controller.ArticleService.CreateMedia(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "article media uploaded successfully", media)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ArticleControllerImpl) DeleteMedia(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	articleId := helper.ToIntFromParams(c.Params("articleId"))
+	mediaId := helper.ToIntFromParams(c.Params("mediaId"))
+
+	controller.ArticleService.DeleteMedia(c.Context(), articleId, mediaId, user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article media deleted successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type BlockController interface {
+	Create(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindByToken(c *fiber.Ctx) error
+}
+
+type BlockControllerImpl struct {
+	services.BlockService
+}
+
+func NewBlockController(blockService services.BlockService) BlockController {
+	return &BlockControllerImpl{
+		BlockService: blockService,
+	}
+}
+
+func (controller *BlockControllerImpl) Create(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.BlockRequest{
+		BlockerId: user.Id,
+		BlockedId: helper.ToIntFromParams(c.Params("userId")),
+	}
+	data := controller.BlockService.Create(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "user blocked successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *BlockControllerImpl) Delete(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.BlockRequest{
+		BlockerId: user.Id,
+		BlockedId: helper.ToIntFromParams(c.Params("userId")),
+	}
+	controller.BlockService.Delete(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "user unblocked successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *BlockControllerImpl) FindByToken(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	data := controller.BlockService.FindBlockedUsers(c.Context(), user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "blocked user list retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type BookmarkController interface {
+	Create(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindByToken(c *fiber.Ctx) error
+}
+
+type BookmarkControllerImpl struct {
+	services.BookmarkService
+	LikeService services.LikeService
+}
+
+func NewBookmarkController(bookmarkService services.BookmarkService, likeService services.LikeService) BookmarkController {
+	return &BookmarkControllerImpl{
+		BookmarkService: bookmarkService,
+		LikeService:     likeService,
+	}
+}
+
+func (controller *BookmarkControllerImpl) Create(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.BookmarkRequest{
+		UserId:    user.Id,
+		ArticleId: helper.ToIntFromParams(c.Params("articleId")),
+	}
+	data := controller.BookmarkService.Create(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "article bookmarked successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *BookmarkControllerImpl) Delete(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.BookmarkRequest{
+		UserId:    user.Id,
+		ArticleId: helper.ToIntFromParams(c.Params("articleId")),
+	}
+	controller.BookmarkService.Delete(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "bookmark removed successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *BookmarkControllerImpl) FindByToken(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	data := controller.BookmarkService.FindArticlesByUserID(c.Context(), user.Id)
+	controller.LikeService.MarkLiked(c.Context(), user.Id, data)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "bookmarked articles retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
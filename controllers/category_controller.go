@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type CategoryController interface {
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	FindArticlesByID(c *fiber.Ctx) error
+}
+
+type CategoryControllerImpl struct {
+	services.CategoryService
+	BookmarkService services.BookmarkService
+	LikeService     services.LikeService
+}
+
+func NewCategoryController(categoryService services.CategoryService, bookmarkService services.BookmarkService, likeService services.LikeService) CategoryController {
+	return &CategoryControllerImpl{
+		CategoryService: categoryService,
+		BookmarkService: bookmarkService,
+		LikeService:     likeService,
+	}
+}
+
+func (controller *CategoryControllerImpl) Create(c *fiber.Ctx) error {
+	req := request.CategoryCreateRequest{}
+	err := c.BodyParser(&req)
+	helper.PanicIfErr(err)
+
+	category := controller.CategoryService.Create(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "category created successfully", category)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *CategoryControllerImpl) Update(c *fiber.Ctx) error {
+	req := request.CategoryUpdateRequest{}
+	err := c.BodyParser(&req)
+	helper.PanicIfErr(err)
+
+	req.Id = helper.ToIntFromParams(c.Params("categoryId"))
+
+	category := controller.CategoryService.Update(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "category updated successfully", category)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *CategoryControllerImpl) Delete(c *fiber.Ctx) error {
+	categoryId := helper.ToIntFromParams(c.Params("categoryId"))
+
+	controller.CategoryService.Delete(c.Context(), categoryId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "category deleted successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *CategoryControllerImpl) FindAll(c *fiber.Ctx) error {
+	categories := controller.CategoryService.FindAll(c.Context())
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "category list retrieved successfully", categories)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *CategoryControllerImpl) FindArticlesByID(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.CategoryArticlesRequest{
+		CategoryId: helper.ToIntFromParams(c.Params("categoryId")),
+		Cursor:     c.Query("cursor"),
+		Limit:      c.QueryInt("limit", 20),
+	}
+
+	data := controller.CategoryService.FindArticlesByID(c.Context(), req)
+	controller.BookmarkService.MarkBookmarked(c.Context(), user.Id, data.Articles)
+	controller.LikeService.MarkLiked(c.Context(), user.Id, data.Articles)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article list by category retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -9,8 +9,10 @@ import (
 
 type CommentController interface {
 	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
 	FindByArticleId(c *fiber.Ctx) error
+	FindRevisionsById(c *fiber.Ctx) error
 }
 
 type CommentControllerImpl struct {
@@ -43,6 +45,24 @@ func (controller *CommentControllerImpl) Create(c *fiber.Ctx) error {
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
 
+func (controller *CommentControllerImpl) Update(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	var commentRequest request.CommentUpdateRequest
+	err = c.BodyParser(&commentRequest)
+	helper.PanicIfErr(err)
+
+	commentRequest.Id = helper.ToIntFromParams(c.Params("commentId"))
+	commentRequest.UserId = user.Id
+
+	data := controller.CommentService.Update(c.Context(), commentRequest)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "comment updated successfully", data)
+
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
 func (controller *CommentControllerImpl) Delete(c *fiber.Ctx) error {
 	user, err := helper.GetUserByToken(c)
 	helper.PanicIfErr(err)
@@ -57,8 +77,18 @@ func (controller *CommentControllerImpl) Delete(c *fiber.Ctx) error {
 }
 
 func (controller *CommentControllerImpl) FindByArticleId(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
 	articleId := helper.ToIntFromParams(c.Params("articleId"))
-	comments := controller.CommentService.FindByArticleID(c.Context(), articleId)
+	comments := controller.CommentService.FindByArticleID(c.Context(), articleId, user.Role == "admin")
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "comment list by article retrieved successfully", comments)
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
+
+func (controller *CommentControllerImpl) FindRevisionsById(c *fiber.Ctx) error {
+	commentId := helper.ToIntFromParams(c.Params("commentId"))
+	revisions := controller.CommentService.FindRevisionsByID(c.Context(), commentId)
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "comment revisions retrieved successfully", revisions)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"uaspw2/config"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type FeedController interface {
+	FindArticles(c *fiber.Ctx) error
+	FindArticlesByAuthor(c *fiber.Ctx) error
+	FindArticlesByTag(c *fiber.Ctx) error
+}
+
+type FeedControllerImpl struct {
+	services.FeedService
+}
+
+func NewFeedController(feedService services.FeedService) FeedController {
+	return &FeedControllerImpl{
+		FeedService: feedService,
+	}
+}
+
+func (controller *FeedControllerImpl) FindArticles(c *fiber.Ctx) error {
+	return controller.sendFeed(c, request.ArticleFeedSourceRequest{})
+}
+
+func (controller *FeedControllerImpl) FindArticlesByAuthor(c *fiber.Ctx) error {
+	return controller.sendFeed(c, request.ArticleFeedSourceRequest{
+		UserId: helper.ToIntFromParams(c.Params("userId")),
+	})
+}
+
+func (controller *FeedControllerImpl) FindArticlesByTag(c *fiber.Ctx) error {
+	return controller.sendFeed(c, request.ArticleFeedSourceRequest{
+		TagSlug: c.Params("slug"),
+	})
+}
+
+// sendFeed renders the feed in the format named by the file extension. The
+// ETag is a hash of the rendered body, Last-Modified the newest article update.
+func (controller *FeedControllerImpl) sendFeed(c *fiber.Ctx, req request.ArticleFeedSourceRequest) error {
+	render, contentType := feedRenderer(c.Params("format"))
+
+	feed := controller.FeedService.FindArticles(c.Context(), req, config.FeedBaseUrl+c.Path())
+	body, err := render(feed)
+	helper.PanicIfErr(err)
+
+	sum := sha256.Sum256(body)
+	c.Set(fiber.HeaderETag, `"`+hex.EncodeToString(sum[:16])+`"`)
+	if !feed.Updated.IsZero() {
+		c.Set(fiber.HeaderLastModified, feed.Updated.UTC().Format(http.TimeFormat))
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(body)
+}
+
+func feedRenderer(format string) (func(helper.Feed) ([]byte, error), string) {
+	switch format {
+	case "rss":
+		return helper.RenderRSS, "application/rss+xml; charset=utf-8"
+	case "atom":
+		return helper.RenderAtom, "application/atom+xml; charset=utf-8"
+	case "json":
+		return helper.RenderJsonFeed, "application/feed+json; charset=utf-8"
+	default:
+		panic(exception.NewNotFoundError("unknown feed format, use rss, atom or json"))
+	}
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type FollowController interface {
+	Create(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindFollowers(c *fiber.Ctx) error
+	FindFollowing(c *fiber.Ctx) error
+}
+
+type FollowControllerImpl struct {
+	services.FollowService
+}
+
+func NewFollowController(followService services.FollowService) FollowController {
+	return &FollowControllerImpl{
+		FollowService: followService,
+	}
+}
+
+func (controller *FollowControllerImpl) Create(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.FollowRequest{
+		FollowerId: user.Id,
+		FolloweeId: helper.ToIntFromParams(c.Params("userId")),
+	}
+	data := controller.FollowService.Create(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "user followed successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *FollowControllerImpl) Delete(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.FollowRequest{
+		FollowerId: user.Id,
+		FolloweeId: helper.ToIntFromParams(c.Params("userId")),
+	}
+	controller.FollowService.Delete(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "user unfollowed successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *FollowControllerImpl) FindFollowers(c *fiber.Ctx) error {
+	userId := helper.ToIntFromParams(c.Params("userId"))
+
+	data := controller.FollowService.FindFollowers(c.Context(), userId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "follower list retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *FollowControllerImpl) FindFollowing(c *fiber.Ctx) error {
+	userId := helper.ToIntFromParams(c.Params("userId"))
+
+	data := controller.FollowService.FindFollowing(c.Context(), userId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "following list retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
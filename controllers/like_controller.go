@@ -12,6 +12,9 @@ type LikeController interface {
 	Delete(c *fiber.Ctx) error
 	FindByArticleId(c *fiber.Ctx) error
 	FindByUserId(c *fiber.Ctx) error
+	CreateReaction(c *fiber.Ctx) error
+	DeleteReaction(c *fiber.Ctx) error
+	FindReactions(c *fiber.Ctx) error
 }
 
 type likeControllerImpl struct {
@@ -59,9 +62,12 @@ func (controller *likeControllerImpl) Delete(c *fiber.Ctx) error {
 }
 
 func (controller *likeControllerImpl) FindByArticleId(c *fiber.Ctx) error {
-	articleId := helper.ToIntFromParams(c.Params("articleId"))
-
-	data := controller.LikeService.FindByArticleID(c.Context(), articleId)
+	req := request.LikeListRequest{
+		ArticleId: helper.ToIntFromParams(c.Params("articleId")),
+		Cursor:    c.Query("cursor"),
+		Limit:     c.QueryInt("limit", 20),
+	}
+	data := controller.LikeService.FindByArticleID(c.Context(), req)
 
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "like list by article retrieved successfully", data)
 
@@ -69,11 +75,59 @@ func (controller *likeControllerImpl) FindByArticleId(c *fiber.Ctx) error {
 }
 
 func (controller *likeControllerImpl) FindByUserId(c *fiber.Ctx) error {
-	userId := helper.ToIntFromParams(c.Params("userId"))
-
-	data := controller.LikeService.FindByUserID(c.Context(), userId)
+	req := request.LikeListRequest{
+		UserId: helper.ToIntFromParams(c.Params("userId")),
+		Cursor: c.Query("cursor"),
+		Limit:  c.QueryInt("limit", 20),
+	}
+	data := controller.LikeService.FindByUserID(c.Context(), req)
 
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "like list by user retrieved successfully", data)
 
 	return c.Status(webResponse.Code).JSON(webResponse)
 }
+
+func (controller *likeControllerImpl) CreateReaction(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReactionRequest{
+		ArticleId: helper.ToIntFromParams(c.Params("articleId")),
+		UserId:    user.Id,
+		Reaction:  c.Params("reaction"),
+	}
+	data := controller.LikeService.CreateReaction(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "reaction added successfully", data)
+
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *likeControllerImpl) DeleteReaction(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReactionRequest{
+		ArticleId: helper.ToIntFromParams(c.Params("articleId")),
+		UserId:    user.Id,
+		Reaction:  c.Params("reaction"),
+	}
+	controller.LikeService.DeleteReaction(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reaction removed successfully", nil)
+
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *likeControllerImpl) FindReactions(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	articleId := helper.ToIntFromParams(c.Params("articleId"))
+
+	data := controller.LikeService.FindReactions(c.Context(), articleId, user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reactions retrieved successfully", data)
+
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type ModerationController interface {
+	CreateReport(c *fiber.Ctx) error
+	FindQueue(c *fiber.Ctx) error
+	CreateAction(c *fiber.Ctx) error
+	FindActions(c *fiber.Ctx) error
+}
+
+type ModerationControllerImpl struct {
+	services.ModerationService
+}
+
+func NewModerationController(moderationService services.ModerationService) ModerationController {
+	return &ModerationControllerImpl{
+		ModerationService: moderationService,
+	}
+}
+
+func (controller *ModerationControllerImpl) CreateReport(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	var reportRequest request.ReportCreateRequest
+	err = c.BodyParser(&reportRequest)
+	helper.PanicIfErr(err)
+
+	reportRequest.ReporterId = user.Id
+
+	data := controller.ModerationService.CreateReport(c.Context(), reportRequest)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "report created successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ModerationControllerImpl) FindQueue(c *fiber.Ctx) error {
+	data := controller.ModerationService.FindQueue(c.Context())
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "moderation queue retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ModerationControllerImpl) CreateAction(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	var actionRequest request.ModerationActionRequest
+	err = c.BodyParser(&actionRequest)
+	helper.PanicIfErr(err)
+
+	actionRequest.ModeratorId = user.Id
+	actionRequest.TargetType = c.Params("targetType")
+	actionRequest.TargetId = helper.ToIntFromParams(c.Params("targetId"))
+
+	data := controller.ModerationService.CreateAction(c.Context(), actionRequest)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "moderation action recorded successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ModerationControllerImpl) FindActions(c *fiber.Ctx) error {
+	userId := c.QueryInt("user_id")
+	data := controller.ModerationService.FindActions(c.Context(), userId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "moderation actions retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type NotificationController interface {
+	FindByToken(c *fiber.Ctx) error
+	MarkRead(c *fiber.Ctx) error
+	MarkUnread(c *fiber.Ctx) error
+	MarkAllRead(c *fiber.Ctx) error
+	CountUnread(c *fiber.Ctx) error
+	FindPreferences(c *fiber.Ctx) error
+	UpdatePreference(c *fiber.Ctx) error
+}
+
+type NotificationControllerImpl struct {
+	services.NotificationService
+}
+
+func NewNotificationController(notificationService services.NotificationService) NotificationController {
+	return &NotificationControllerImpl{
+		NotificationService: notificationService,
+	}
+}
+
+func (controller *NotificationControllerImpl) FindByToken(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.NotificationListRequest{
+		UserId:     user.Id,
+		UnreadOnly: c.QueryBool("unread", false),
+		Cursor:     c.Query("cursor"),
+		Limit:      c.QueryInt("limit", 20),
+	}
+	data := controller.NotificationService.FindByUserID(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "notifications retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *NotificationControllerImpl) MarkRead(c *fiber.Ctx) error {
+	return controller.markRead(c, true)
+}
+
+func (controller *NotificationControllerImpl) MarkUnread(c *fiber.Ctx) error {
+	return controller.markRead(c, false)
+}
+
+func (controller *NotificationControllerImpl) markRead(c *fiber.Ctx, read bool) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.NotificationReadRequest{
+		Id:     helper.ToIntFromParams(c.Params("notificationId")),
+		UserId: user.Id,
+		IsRead: read,
+	}
+	data := controller.NotificationService.MarkRead(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "notification updated successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *NotificationControllerImpl) MarkAllRead(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	controller.NotificationService.MarkAllRead(c.Context(), user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "notifications marked as read successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *NotificationControllerImpl) CountUnread(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	data := controller.NotificationService.CountUnread(c.Context(), user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "unread notifications counted successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *NotificationControllerImpl) FindPreferences(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	data := controller.NotificationService.FindPreferences(c.Context(), user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "notification preferences retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *NotificationControllerImpl) UpdatePreference(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.NotificationPreferenceRequest{}
+	err = c.BodyParser(&req)
+	helper.PanicIfErr(err)
+
+	req.UserId = user.Id
+	req.Type = c.Params("type")
+	data := controller.NotificationService.UpdatePreference(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "notification preference updated successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/services"
+)
+
+type ReadingListController interface {
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindByID(c *fiber.Ctx) error
+	FindByToken(c *fiber.Ctx) error
+	FindByUserID(c *fiber.Ctx) error
+	CreateItem(c *fiber.Ctx) error
+	UpdateItem(c *fiber.Ctx) error
+	DeleteItem(c *fiber.Ctx) error
+	Reorder(c *fiber.Ctx) error
+}
+
+type ReadingListControllerImpl struct {
+	services.ReadingListService
+	BookmarkService services.BookmarkService
+	LikeService     services.LikeService
+}
+
+func NewReadingListController(readingListService services.ReadingListService, bookmarkService services.BookmarkService, likeService services.LikeService) ReadingListController {
+	return &ReadingListControllerImpl{
+		ReadingListService: readingListService,
+		BookmarkService:    bookmarkService,
+		LikeService:        likeService,
+	}
+}
+
+// markItems sets the per-user flags on the articles of the reading list items.
+func (controller *ReadingListControllerImpl) markItems(c *fiber.Ctx, userId int, readingList *response.ReadingListResponse) {
+	articles := make([]response.ArticleResponse, len(readingList.Items))
+	for i, item := range readingList.Items {
+		articles[i] = item.Article
+	}
+	controller.BookmarkService.MarkBookmarked(c.Context(), userId, articles)
+	controller.LikeService.MarkLiked(c.Context(), userId, articles)
+	for i := range readingList.Items {
+		readingList.Items[i].Article = articles[i]
+	}
+}
+
+func (controller *ReadingListControllerImpl) Create(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReadingListCreateRequest{}
+	err = c.BodyParser(&req)
+	helper.PanicIfErr(err)
+	req.UserId = user.Id
+
+	data := controller.ReadingListService.Create(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "reading list created successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) Update(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReadingListUpdateRequest{}
+	err = c.BodyParser(&req)
+	helper.PanicIfErr(err)
+	req.Id = helper.ToIntFromParams(c.Params("readingListId"))
+	req.UserId = user.Id
+
+	data := controller.ReadingListService.Update(c.Context(), req)
+	controller.markItems(c, user.Id, &data)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading list updated successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) Delete(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	readingListId := helper.ToIntFromParams(c.Params("readingListId"))
+	controller.ReadingListService.Delete(c.Context(), readingListId, user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading list deleted successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) FindByID(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	readingListId := helper.ToIntFromParams(c.Params("readingListId"))
+	data := controller.ReadingListService.FindByID(c.Context(), readingListId, user.Id)
+	controller.markItems(c, user.Id, &data)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading list found", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) FindByToken(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	data := controller.ReadingListService.FindAllByUserID(c.Context(), user.Id, user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading lists retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) FindByUserID(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	userId := helper.ToIntFromParams(c.Params("userId"))
+	data := controller.ReadingListService.FindAllByUserID(c.Context(), userId, user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading lists by user retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) CreateItem(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReadingListItemRequest{}
+	err = c.BodyParser(&req)
+	helper.PanicIfErr(err)
+	req.ReadingListId = helper.ToIntFromParams(c.Params("readingListId"))
+	req.UserId = user.Id
+
+	data := controller.ReadingListService.CreateItem(c.Context(), req)
+	controller.markItems(c, user.Id, &data)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "article added to reading list successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) UpdateItem(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReadingListItemRequest{}
+	err = c.BodyParser(&req)
+	helper.PanicIfErr(err)
+	req.ReadingListId = helper.ToIntFromParams(c.Params("readingListId"))
+	req.ArticleId = helper.ToIntFromParams(c.Params("articleId"))
+	req.UserId = user.Id
+
+	data := controller.ReadingListService.UpdateItem(c.Context(), req)
+	controller.markItems(c, user.Id, &data)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading list item updated successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) DeleteItem(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	readingListId := helper.ToIntFromParams(c.Params("readingListId"))
+	articleId := helper.ToIntFromParams(c.Params("articleId"))
+	controller.ReadingListService.DeleteItem(c.Context(), readingListId, articleId, user.Id)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article removed from reading list successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *ReadingListControllerImpl) Reorder(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.ReadingListReorderRequest{}
+	err = c.BodyParser(&req)
+	helper.PanicIfErr(err)
+	req.ReadingListId = helper.ToIntFromParams(c.Params("readingListId"))
+	req.UserId = user.Id
+
+	data := controller.ReadingListService.Reorder(c.Context(), req)
+	controller.markItems(c, user.Id, &data)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "reading list reordered successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
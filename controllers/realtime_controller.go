@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/config"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/realtime"
+	"uaspw2/services"
+)
+
+type RealtimeController interface {
+	Events(c *fiber.Ctx) error
+	WebSocket(c *fiber.Ctx) error
+}
+
+type RealtimeControllerImpl struct {
+	services.RealtimeService
+}
+
+func NewRealtimeController(realtimeService services.RealtimeService) RealtimeController {
+	return &RealtimeControllerImpl{
+		RealtimeService: realtimeService,
+	}
+}
+
+// subscribe opens a subscription for the current user and the articles
+// listed in the "articles" query, e.g. ?articles=1,2.
+func (controller *RealtimeControllerImpl) subscribe(c *fiber.Ctx) (config.UserClaims, *realtime.Subscription) {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.RealtimeSubscribeRequest{
+		UserId:     user.Id,
+		ArticleIds: helper.ToIntsFromQuery(c.Query("articles")),
+	}
+	return user, controller.RealtimeService.Subscribe(c.Context(), req)
+}
+
+func (controller *RealtimeControllerImpl) Events(c *fiber.Ctx) error {
+	_, subscription := controller.subscribe(c)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// keeps nginx from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		realtime.StreamEvents(w, subscription, config.RealtimeHeartbeat)
+	})
+	return nil
+}
+
+func (controller *RealtimeControllerImpl) WebSocket(c *fiber.Ctx) error {
+	if !realtime.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	user, subscription := controller.subscribe(c)
+
+	return realtime.UpgradeWebSocket(c, func(ws *realtime.WebSocketConn) {
+		ws.WriteTimeout = config.RealtimeWriteTimeout
+		realtime.ServeWebSocket(ws, subscription, config.RealtimeHeartbeat, func(command realtime.Command) error {
+			return controller.handleCommand(user.Id, subscription, command)
+		})
+	})
+}
+
+// handleCommand runs outside the request, where panics are not turned into
+// error responses, so they are returned to the client instead.
+func (controller *RealtimeControllerImpl) handleCommand(userId int, subscription *realtime.Subscription, command realtime.Command) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if recoveredErr, ok := recovered.(error); ok {
+				err = recoveredErr
+			} else {
+				err = fmt.Errorf("%v", recovered)
+			}
+		}
+	}()
+
+	switch command.Action {
+	case "subscribe":
+		controller.RealtimeService.FollowArticle(context.Background(), subscription, userId, command.ArticleId)
+	case "unsubscribe":
+		controller.RealtimeService.UnfollowArticle(subscription, command.ArticleId)
+	default:
+		return errors.New("unknown action " + command.Action)
+	}
+	return nil
+}
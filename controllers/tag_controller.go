@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type TagController interface {
+	FindAll(c *fiber.Ctx) error
+	FindArticlesBySlug(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Merge(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type TagControllerImpl struct {
+	services.TagService
+	BookmarkService services.BookmarkService
+	LikeService     services.LikeService
+}
+
+func NewTagController(tagService services.TagService, bookmarkService services.BookmarkService, likeService services.LikeService) TagController {
+	return &TagControllerImpl{
+		TagService:      tagService,
+		BookmarkService: bookmarkService,
+		LikeService:     likeService,
+	}
+}
+
+func (controller *TagControllerImpl) FindAll(c *fiber.Ctx) error {
+	tags := controller.TagService.FindAll(c.Context(), c.Query("q"))
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "tag list retrieved successfully", tags)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *TagControllerImpl) FindArticlesBySlug(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	req := request.TagArticlesRequest{
+		Slug:   c.Params("slug"),
+		Cursor: c.Query("cursor"),
+		Limit:  c.QueryInt("limit", 20),
+	}
+
+	data := controller.TagService.FindArticlesBySlug(c.Context(), req)
+	controller.BookmarkService.MarkBookmarked(c.Context(), user.Id, data.Articles)
+	controller.LikeService.MarkLiked(c.Context(), user.Id, data.Articles)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "article list by tag retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *TagControllerImpl) Update(c *fiber.Ctx) error {
+	req := request.TagUpdateRequest{}
+	err := c.BodyParser(&req)
+	helper.PanicIfErr(err)
+
+	req.Id = helper.ToIntFromParams(c.Params("tagId"))
+
+	tag := controller.TagService.Update(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "tag updated successfully", tag)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *TagControllerImpl) Merge(c *fiber.Ctx) error {
+	req := request.TagMergeRequest{}
+	err := c.BodyParser(&req)
+	helper.PanicIfErr(err)
+
+	req.SourceId = helper.ToIntFromParams(c.Params("tagId"))
+
+	tag := controller.TagService.Merge(c.Context(), req)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "tags merged successfully", tag)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *TagControllerImpl) Delete(c *fiber.Ctx) error {
+	tagId := helper.ToIntFromParams(c.Params("tagId"))
+
+	controller.TagService.Delete(c.Context(), tagId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "tag deleted successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
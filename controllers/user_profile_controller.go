@@ -37,6 +37,9 @@ func (controller *UserProfileControllerImpl) FindByToken(c *fiber.Ctx) error {
 	helper.PanicIfErr(err)
 
 	data := controller.UserProfileService.FindByUserID(c.Context(), user.Id)
+	if helper.SetContentETag(c, data.Version, data) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "User profile found", data)
 
@@ -47,6 +50,10 @@ func (controller *UserProfileControllerImpl) FindByPath(c *fiber.Ctx) error {
 	userId := helper.ToIntFromParams(c.Params("userId"))
 
 	data := controller.UserProfileService.FindByUserID(c.Context(), userId)
+	if helper.SetContentETag(c, data.Version, data) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "User profile found", data)
 
 	return c.Status(webResponse.Code).JSON(webResponse)
@@ -61,8 +68,10 @@ func (controller *UserProfileControllerImpl) UpdateByToken(c *fiber.Ctx) error {
 	helper.PanicIfErr(err)
 
 	req.UserId = user.Id
+	req.Version = helper.IfMatchVersion(c)
 
 	data := controller.UserProfileService.Update(c.Context(), req)
+	c.Set(fiber.HeaderETag, helper.ETag(data.Version))
 	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "User profile updated successfully", data)
 
 	return c.Status(webResponse.Code).JSON(webResponse)
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/services"
+)
+
+type WebhookController interface {
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	FindAll(c *fiber.Ctx) error
+	FindByID(c *fiber.Ctx) error
+	FindDeliveries(c *fiber.Ctx) error
+	FindDelivery(c *fiber.Ctx) error
+	Redeliver(c *fiber.Ctx) error
+}
+
+type WebhookControllerImpl struct {
+	services.WebhookService
+}
+
+func NewWebhookController(webhookService services.WebhookService) WebhookController {
+	return &WebhookControllerImpl{
+		WebhookService: webhookService,
+	}
+}
+
+func (controller *WebhookControllerImpl) Create(c *fiber.Ctx) error {
+	user, err := helper.GetUserByToken(c)
+	helper.PanicIfErr(err)
+
+	var webhookRequest request.WebhookCreateRequest
+	err = c.BodyParser(&webhookRequest)
+	helper.PanicIfErr(err)
+
+	webhookRequest.CreatedBy = user.Id
+
+	data := controller.WebhookService.Create(c.Context(), webhookRequest)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusCreated, "webhook created successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) Update(c *fiber.Ctx) error {
+	var webhookRequest request.WebhookUpdateRequest
+	err := c.BodyParser(&webhookRequest)
+	helper.PanicIfErr(err)
+
+	webhookRequest.Id = helper.ToIntFromParams(c.Params("webhookId"))
+
+	data := controller.WebhookService.Update(c.Context(), webhookRequest)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "webhook updated successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) Delete(c *fiber.Ctx) error {
+	webhookId := helper.ToIntFromParams(c.Params("webhookId"))
+	controller.WebhookService.Delete(c.Context(), webhookId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "webhook deleted successfully", nil)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) FindAll(c *fiber.Ctx) error {
+	data := controller.WebhookService.FindAll(c.Context())
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "webhooks retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) FindByID(c *fiber.Ctx) error {
+	webhookId := helper.ToIntFromParams(c.Params("webhookId"))
+	data := controller.WebhookService.FindByID(c.Context(), webhookId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "webhook retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) FindDeliveries(c *fiber.Ctx) error {
+	webhookId := helper.ToIntFromParams(c.Params("webhookId"))
+	data := controller.WebhookService.FindDeliveries(c.Context(), webhookId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "webhook deliveries retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) FindDelivery(c *fiber.Ctx) error {
+	webhookId := helper.ToIntFromParams(c.Params("webhookId"))
+	deliveryId := helper.ToIntFromParams(c.Params("deliveryId"))
+	data := controller.WebhookService.FindDelivery(c.Context(), webhookId, deliveryId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusOK, "webhook delivery retrieved successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
+
+func (controller *WebhookControllerImpl) Redeliver(c *fiber.Ctx) error {
+	webhookId := helper.ToIntFromParams(c.Params("webhookId"))
+	deliveryId := helper.ToIntFromParams(c.Params("deliveryId"))
+	data := controller.WebhookService.Redeliver(c.Context(), webhookId, deliveryId)
+
+	webResponse := helper.CreateSuccessResponse(fiber.StatusAccepted, "webhook delivery queued successfully", data)
+	return c.Status(webResponse.Code).JSON(webResponse)
+}
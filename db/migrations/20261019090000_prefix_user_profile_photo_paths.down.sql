@@ -0,0 +1,3 @@
+UPDATE user_profile_photos
+SET path = SUBSTRING(path, LENGTH('profile_photos/') + 1)
+WHERE path LIKE 'profile_photos/%';
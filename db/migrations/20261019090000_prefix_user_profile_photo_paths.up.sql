@@ -0,0 +1,4 @@
+UPDATE user_profile_photos
+SET path = CONCAT('profile_photos/', path)
+WHERE path <> 'default_profile_photo.svg'
+  AND path NOT LIKE '%/%';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_profile_photo_variants;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS article_categories;
+DROP TABLE IF EXISTS categories;
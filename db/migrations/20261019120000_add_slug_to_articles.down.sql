@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS article_slug_redirects;
+ALTER TABLE articles DROP COLUMN slug;
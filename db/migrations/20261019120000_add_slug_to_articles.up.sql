@@ -0,0 +1,26 @@
+ALTER TABLE articles ADD COLUMN slug VARCHAR(255) NULL AFTER title;
+
+-- REGEXP_REPLACE needs MySQL 8.0 or MariaDB 10.0.5 or newer.
+UPDATE articles
+SET slug = TRIM(BOTH '-' FROM LEFT(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(title), '[^a-z0-9]+', '-')), 240));
+
+-- titles without letters or digits
+UPDATE articles SET slug = CONCAT('article-', id) WHERE slug = '';
+
+-- the oldest article keeps a shared slug, the others get their id appended
+UPDATE articles a
+JOIN (SELECT slug, MIN(id) AS id FROM articles GROUP BY slug HAVING COUNT(*) > 1) oldest
+    ON oldest.slug = a.slug AND a.id > oldest.id
+SET a.slug = CONCAT(LEFT(a.slug, 255 - 1 - LENGTH(a.id)), '-', a.id);
+
+ALTER TABLE articles
+    MODIFY slug VARCHAR(255) NOT NULL,
+    ADD UNIQUE KEY slug (slug);
+
+CREATE TABLE article_slug_redirects (
+    slug VARCHAR(255) PRIMARY KEY,
+    article_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY article_id (article_id),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
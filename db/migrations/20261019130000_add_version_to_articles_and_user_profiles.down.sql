@@ -0,0 +1,2 @@
+ALTER TABLE user_profiles DROP COLUMN version;
+ALTER TABLE articles DROP COLUMN version;
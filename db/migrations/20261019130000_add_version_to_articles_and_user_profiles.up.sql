@@ -0,0 +1,2 @@
+ALTER TABLE articles ADD COLUMN version INT NOT NULL DEFAULT 1 AFTER is_published;
+ALTER TABLE user_profiles ADD COLUMN version INT NOT NULL DEFAULT 1 AFTER address;
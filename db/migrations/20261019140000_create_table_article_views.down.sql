@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS article_stats_daily;
+DROP TABLE IF EXISTS article_views;
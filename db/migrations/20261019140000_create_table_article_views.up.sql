@@ -0,0 +1,22 @@
+CREATE TABLE article_views (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    KEY article_user_created (article_id, user_id, created_at),
+    KEY created_at (created_at),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE article_stats_daily (
+    article_id INT NOT NULL,
+    day DATE NOT NULL,
+    views INT NOT NULL DEFAULT 0,
+    unique_readers INT NOT NULL DEFAULT 0,
+    likes INT NOT NULL DEFAULT 0,
+    comments INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (article_id, day),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE article_scores (
+    article_id INT PRIMARY KEY,
+    trending_score DOUBLE NOT NULL DEFAULT 0,
+    likes_week INT NOT NULL DEFAULT 0,
+    likes_month INT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY trending_score (trending_score),
+    KEY likes_week (likes_week),
+    KEY likes_month (likes_month),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
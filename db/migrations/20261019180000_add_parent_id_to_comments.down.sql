@@ -0,0 +1,6 @@
+ALTER TABLE `comments`
+    DROP FOREIGN KEY `comments_ibfk_3`,
+    DROP KEY `parent_id`,
+    DROP COLUMN `deleted_at`,
+    DROP COLUMN `depth`,
+    DROP COLUMN `parent_id`;
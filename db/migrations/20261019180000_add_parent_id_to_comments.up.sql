@@ -0,0 +1,6 @@
+ALTER TABLE `comments`
+    ADD COLUMN `parent_id` int(11) DEFAULT NULL AFTER `article_id`,
+    ADD COLUMN `depth` int(11) NOT NULL DEFAULT 0 AFTER `parent_id`,
+    ADD COLUMN `deleted_at` timestamp NULL DEFAULT NULL AFTER `updated_at`,
+    ADD KEY `parent_id` (`parent_id`),
+    ADD CONSTRAINT `comments_ibfk_3` FOREIGN KEY (`parent_id`) REFERENCES `comments` (`id`) ON DELETE CASCADE;
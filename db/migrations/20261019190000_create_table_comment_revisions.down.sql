@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `comment_revisions`;
+
+ALTER TABLE `comments` DROP COLUMN `edited_at`;
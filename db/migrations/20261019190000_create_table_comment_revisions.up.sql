@@ -0,0 +1,11 @@
+ALTER TABLE `comments` ADD COLUMN `edited_at` timestamp NULL DEFAULT NULL AFTER `updated_at`;
+
+CREATE TABLE `comment_revisions` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `comment_id` int(11) NOT NULL,
+    `comment` text NOT NULL,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `comment_id` (`comment_id`),
+    CONSTRAINT `comment_revisions_ibfk_1` FOREIGN KEY (`comment_id`) REFERENCES `comments` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS `moderation_actions`;
+DROP TABLE IF EXISTS `reports`;
+
+ALTER TABLE `comments` DROP COLUMN `is_hidden`;
+
+ALTER TABLE `users` DROP COLUMN `suspended_until`;
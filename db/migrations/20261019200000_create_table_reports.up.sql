@@ -0,0 +1,36 @@
+ALTER TABLE `users` ADD COLUMN `suspended_until` timestamp NULL DEFAULT NULL AFTER `role`;
+
+ALTER TABLE `comments` ADD COLUMN `is_hidden` boolean NOT NULL DEFAULT false AFTER `comment`;
+
+CREATE TABLE `reports` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `reporter_id` int(11) NOT NULL,
+    `target_type` enum('article','comment') NOT NULL,
+    `target_id` int(11) NOT NULL,
+    `reason` enum('spam','harassment','hate_speech','misinformation','plagiarism','other') NOT NULL,
+    `details` text DEFAULT NULL,
+    `status` enum('open','dismissed','actioned') NOT NULL DEFAULT 'open',
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    `updated_at` timestamp NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `reporter_id` (`reporter_id`),
+    KEY `target` (`target_type`, `target_id`, `status`),
+    CONSTRAINT `reports_ibfk_1` FOREIGN KEY (`reporter_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `moderation_actions` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `moderator_id` int(11) NOT NULL,
+    `target_type` enum('article','comment') NOT NULL,
+    `target_id` int(11) NOT NULL,
+    `user_id` int(11) DEFAULT NULL,
+    `action` enum('dismiss','hide','delete','warn','suspend') NOT NULL,
+    `note` text DEFAULT NULL,
+    `suspend_days` int(11) NOT NULL DEFAULT 0,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `moderator_id` (`moderator_id`),
+    KEY `user_id` (`user_id`),
+    CONSTRAINT `moderation_actions_ibfk_1` FOREIGN KEY (`moderator_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `moderation_actions_ibfk_2` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL
+);
@@ -0,0 +1,5 @@
+DELETE FROM `reports` WHERE `reporter_id` IS NULL OR `reason` = 'filter';
+
+ALTER TABLE `reports`
+    MODIFY `reporter_id` int(11) NOT NULL,
+    MODIFY `reason` enum('spam','harassment','hate_speech','misinformation','plagiarism','other') NOT NULL;
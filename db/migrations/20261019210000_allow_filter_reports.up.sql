@@ -0,0 +1,3 @@
+ALTER TABLE `reports`
+    MODIFY `reporter_id` int(11) DEFAULT NULL,
+    MODIFY `reason` enum('spam','harassment','hate_speech','misinformation','plagiarism','other','filter') NOT NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE `user_blocks` (
+    `blocker_id` int(11) NOT NULL,
+    `blocked_id` int(11) NOT NULL,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`blocker_id`, `blocked_id`),
+    KEY `blocked_id` (`blocked_id`),
+    CONSTRAINT `user_blocks_ibfk_1` FOREIGN KEY (`blocker_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `user_blocks_ibfk_2` FOREIGN KEY (`blocked_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+CREATE TABLE `notifications` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `user_id` int(11) NOT NULL,
+    `actor_id` int(11) DEFAULT NULL,
+    `type` varchar(32) NOT NULL,
+    `target_type` varchar(32) NOT NULL,
+    `target_id` int(11) NOT NULL,
+    `article_id` int(11) DEFAULT NULL,
+    `read_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `user_id` (`user_id`, `created_at`),
+    KEY `actor_id` (`actor_id`),
+    CONSTRAINT `notifications_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+    CONSTRAINT `notifications_ibfk_2` FOREIGN KEY (`actor_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+CREATE TABLE `mentions` (
+    `target_type` enum('article','comment') NOT NULL,
+    `target_id` int(11) NOT NULL,
+    `user_id` int(11) NOT NULL,
+    `notified_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`target_type`, `target_id`, `user_id`),
+    KEY `user_id` (`user_id`),
+    CONSTRAINT `mentions_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
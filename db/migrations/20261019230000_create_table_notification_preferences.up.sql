@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS `notification_preferences`;
+CREATE TABLE `notification_preferences` (
+    `user_id` int(11) NOT NULL,
+    `type` varchar(32) NOT NULL,
+    `is_muted` tinyint(1) NOT NULL DEFAULT 0,
+    `updated_at` timestamp NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`user_id`, `type`),
+    CONSTRAINT `notification_preferences_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,43 @@
+CREATE TABLE `webhooks` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `url` varchar(2048) NOT NULL,
+    `secret` varchar(64) NOT NULL,
+    `events` set('article.published','article.deleted','user.registered','comment.created') NOT NULL,
+    `is_active` boolean NOT NULL DEFAULT true,
+    `created_by` int(11) DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    `updated_at` timestamp NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `created_by` (`created_by`),
+    CONSTRAINT `webhooks_ibfk_1` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`) ON DELETE SET NULL
+);
+
+CREATE TABLE `webhook_deliveries` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `webhook_id` int(11) NOT NULL,
+    `event` varchar(32) NOT NULL,
+    `payload` mediumtext NOT NULL,
+    `status` enum('pending','succeeded','failed') NOT NULL DEFAULT 'pending',
+    `attempt_count` int(11) NOT NULL DEFAULT 0,
+    `response_code` int(11) DEFAULT NULL,
+    `next_attempt_at` timestamp NULL DEFAULT current_timestamp(),
+    `last_attempt_at` timestamp NULL DEFAULT NULL,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `webhook_id` (`webhook_id`, `created_at`),
+    KEY `due` (`status`, `next_attempt_at`),
+    CONSTRAINT `webhook_deliveries_ibfk_1` FOREIGN KEY (`webhook_id`) REFERENCES `webhooks` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `webhook_delivery_attempts` (
+    `id` int(11) NOT NULL AUTO_INCREMENT,
+    `delivery_id` int(11) NOT NULL,
+    `response_code` int(11) DEFAULT NULL,
+    `response_body` text DEFAULT NULL,
+    `error` varchar(512) DEFAULT NULL,
+    `duration_ms` int(11) NOT NULL DEFAULT 0,
+    `created_at` timestamp NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `delivery_id` (`delivery_id`),
+    CONSTRAINT `webhook_delivery_attempts_ibfk_1` FOREIGN KEY (`delivery_id`) REFERENCES `webhook_deliveries` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+DELETE FROM likes WHERE reaction <> 'like';
+
+ALTER TABLE likes
+    DROP INDEX article_reaction,
+    DROP INDEX user_article_reaction,
+    ADD UNIQUE KEY user_article (user_id, article_id),
+    DROP COLUMN reaction;
@@ -0,0 +1,5 @@
+ALTER TABLE likes
+    ADD COLUMN reaction VARCHAR(16) NOT NULL DEFAULT 'like' AFTER article_id,
+    DROP INDEX user_article,
+    ADD UNIQUE KEY user_article_reaction (user_id, article_id, reaction),
+    ADD KEY article_reaction (article_id, reaction);
@@ -0,0 +1,3 @@
+ALTER TABLE article_views
+    DROP INDEX article_user_window,
+    DROP COLUMN view_window;
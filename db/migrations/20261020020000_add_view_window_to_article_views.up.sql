@@ -0,0 +1,6 @@
+-- view_window numbers the de-duplication window a view falls in; the unique
+-- key then counts one view per user and window. Older views keep NULL, which
+-- never collides.
+ALTER TABLE article_views
+    ADD COLUMN view_window BIGINT NULL AFTER user_id,
+    ADD UNIQUE KEY article_user_window (article_id, user_id, view_window);
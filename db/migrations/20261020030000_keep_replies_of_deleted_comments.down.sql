@@ -0,0 +1,3 @@
+ALTER TABLE `comments`
+    DROP FOREIGN KEY `comments_ibfk_3`,
+    ADD CONSTRAINT `comments_ibfk_3` FOREIGN KEY (`parent_id`) REFERENCES `comments` (`id`) ON DELETE CASCADE;
@@ -0,0 +1,6 @@
+-- Deleting a comment row, e.g. along with its author, used to delete every
+-- reply below it, including those of other users. The replies now stay and
+-- are shown under a "[deleted]" placeholder.
+ALTER TABLE `comments`
+    DROP FOREIGN KEY `comments_ibfk_3`,
+    ADD CONSTRAINT `comments_ibfk_3` FOREIGN KEY (`parent_id`) REFERENCES `comments` (`id`) ON DELETE SET NULL;
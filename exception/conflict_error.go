@@ -0,0 +1,13 @@
+package exception
+
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func NewConflictError(message string) *ConflictError {
+	return &ConflictError{message}
+}
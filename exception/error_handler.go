@@ -25,6 +25,18 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 		return nil
 	}
 
+	if preconditionFailedError(c, err) {
+		return nil
+	}
+
+	if preconditionRequiredError(c, err) {
+		return nil
+	}
+
+	if conflictError(c, err) {
+		return nil
+	}
+
 	return internalServerError(c, err)
 }
 
@@ -70,6 +82,48 @@ func invalidParameterError(c *fiber.Ctx, err error) bool {
 	}
 }
 
+func preconditionFailedError(c *fiber.Ctx, err error) bool {
+	var exception *PreconditionFailedError
+	if errors.As(err, &exception) {
+		errorResponse := response.ErrorResponse{
+			Code:    fiber.StatusPreconditionFailed,
+			Message: "PRECONDITION FAILED",
+			Error:   exception.Error(),
+		}
+		return c.Status(fiber.StatusPreconditionFailed).JSON(errorResponse) == nil
+	} else {
+		return false
+	}
+}
+
+func preconditionRequiredError(c *fiber.Ctx, err error) bool {
+	var exception *PreconditionRequiredError
+	if errors.As(err, &exception) {
+		errorResponse := response.ErrorResponse{
+			Code:    fiber.StatusPreconditionRequired,
+			Message: "PRECONDITION REQUIRED",
+			Error:   exception.Error(),
+		}
+		return c.Status(fiber.StatusPreconditionRequired).JSON(errorResponse) == nil
+	} else {
+		return false
+	}
+}
+
+func conflictError(c *fiber.Ctx, err error) bool {
+	var exception *ConflictError
+	if errors.As(err, &exception) {
+		errorResponse := response.ErrorResponse{
+			Code:    fiber.StatusConflict,
+			Message: "CONFLICT",
+			Error:   exception.Error(),
+		}
+		return c.Status(fiber.StatusConflict).JSON(errorResponse) == nil
+	} else {
+		return false
+	}
+}
+
 func validatorError(c *fiber.Ctx, err any) bool {
 
 	exception, ok := err.(validator.ValidationErrors)
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"uaspw2/exception"
+)
+
+// EncodeCursor returns an opaque cursor pointing after the row with the given
+// creation time and id, for lists ordered by created_at DESC, id DESC.
+func EncodeCursor(createdAt string, id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt + "|" + strconv.Itoa(id)))
+}
+
+// DecodeCursor reverses EncodeCursor. An empty cursor starts at the top and is
+// returned as ("", 0).
+func DecodeCursor(cursor string) (string, int) {
+	if cursor == "" {
+		return "", 0
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		createdAt, idText, found := strings.Cut(string(decoded), "|")
+		if id, err := strconv.Atoi(idText); found && err == nil && createdAt != "" {
+			return createdAt, id
+		}
+	}
+	panic(exception.NewInvalidParameter("invalid cursor"))
+}
@@ -0,0 +1,41 @@
+package helper
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"uaspw2/exception"
+)
+
+// IsDuplicateKey reports whether err is a unique key violation.
+func IsDuplicateKey(err error) bool {
+	var mysqlError *mysql.MySQLError
+	return errors.As(err, &mysqlError) && mysqlError.Number == 1062
+}
+
+// RetryOnDuplicateKey runs fn until it gets through without a unique key
+// violation, at most attempts times, passing the number of failed attempts.
+// InnoDB only rolls back the failing statement, so fn can retry in the same
+// transaction. When every attempt collides a ConflictError with message is
+// raised; any other panic is passed on untouched.
+func RetryOnDuplicateKey(attempts int, message string, fn func(attempt int)) {
+	for attempt := 0; attempt < attempts; attempt++ {
+		if !duplicateKeyPanics(fn, attempt) {
+			return
+		}
+	}
+	panic(exception.NewConflictError(message))
+}
+
+func duplicateKeyPanics(fn func(attempt int), attempt int) (duplicate bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok || !IsDuplicateKey(err) {
+				panic(r)
+			}
+			duplicate = true
+		}
+	}()
+	fn(attempt)
+	return false
+}
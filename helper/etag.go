@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+	"uaspw2/exception"
+)
+
+// ETag formats a row version as a strong entity tag.
+func ETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// SetETag sets the ETag header for version and reports whether the request's
+// If-None-Match already matches it, in which case a 304 should be sent.
+func SetETag(c *fiber.Ctx, version int) bool {
+	c.Set(fiber.HeaderETag, ETag(version))
+	return c.Fresh()
+}
+
+// ContentETag tags a representation that also holds data the row version does
+// not cover, such as counts or the viewer's own flags: the version, followed
+// by a hash of body.
+func ContentETag(version int, body any) string {
+	encoded, err := json.Marshal(body)
+	PanicIfErr(err)
+	sum := sha256.Sum256(encoded)
+	return fmt.Sprintf(`"%d-%s"`, version, hex.EncodeToString(sum[:8]))
+}
+
+// SetContentETag is SetETag for ContentETag. The body differs per viewer, so
+// shared caches must not keep it.
+func SetContentETag(c *fiber.Ctx, version int, body any) bool {
+	c.Set(fiber.HeaderETag, ContentETag(version, body))
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return c.Fresh()
+}
+
+// IfMatchVersion returns the version named by the If-Match header. The header
+// is required; "*" matches any version and is returned as 0. A tag from
+// ContentETag names the version before its hash.
+func IfMatchVersion(c *fiber.Ctx) int {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		panic(exception.NewPreconditionRequiredError("If-Match header is required"))
+	}
+	if header == "*" {
+		return 0
+	}
+
+	// If-Match uses strong comparison, so weak tags never match
+	tag, found := strings.CutPrefix(header, `"`)
+	tag, closed := strings.CutSuffix(tag, `"`)
+	tag, _, _ = strings.Cut(tag, "-")
+	version, err := strconv.Atoi(tag)
+	if !found || !closed || err != nil || version <= 0 {
+		panic(exception.NewPreconditionFailedError("If-Match does not match the current version"))
+	}
+	return version
+}
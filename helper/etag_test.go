@@ -0,0 +1,119 @@
+package helper
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"uaspw2/exception"
+)
+
+func TestContentETag(t *testing.T) {
+	body := map[string]any{"like_count": 1, "liked_by_me": false}
+	tag := ContentETag(3, body)
+	if tag != ContentETag(3, map[string]any{"like_count": 1, "liked_by_me": false}) {
+		t.Error("the tag of an equal body changed")
+	}
+	if tag == ContentETag(3, map[string]any{"like_count": 1, "liked_by_me": true}) {
+		t.Error("the tag ignores a per-viewer flag")
+	}
+	if tag == ContentETag(4, body) {
+		t.Error("the tag ignores the version")
+	}
+}
+
+func TestSetContentETag(t *testing.T) {
+	body := map[string]int{"like_count": 1}
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		if SetContentETag(c, 3, body) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		return c.JSON(body)
+	})
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{"no validator", "", fiber.StatusOK},
+		{"current tag", ContentETag(3, body), fiber.StatusNotModified},
+		{"bare version", `"3"`, fiber.StatusOK},
+		{"other counts", ContentETag(3, map[string]int{"like_count": 2}), fiber.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if test.ifNoneMatch != "" {
+				req.Header.Set(fiber.HeaderIfNoneMatch, test.ifNoneMatch)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, test.status)
+			}
+			if got := resp.Header.Get(fiber.HeaderCacheControl); got != "private, no-cache" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+		})
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) (err error) {
+		defer func() {
+			switch r := recover().(type) {
+			case nil:
+			case *exception.PreconditionFailedError:
+				err = c.SendStatus(fiber.StatusPreconditionFailed)
+			case *exception.PreconditionRequiredError:
+				err = c.SendStatus(fiber.StatusPreconditionRequired)
+			default:
+				panic(r)
+			}
+		}()
+		return c.SendString(strconv.Itoa(IfMatchVersion(c)))
+	})
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		version string
+	}{
+		{"version", `"7"`, fiber.StatusOK, "7"},
+		{"content tag", ContentETag(7, "body"), fiber.StatusOK, "7"},
+		{"any", "*", fiber.StatusOK, "0"},
+		{"missing", "", fiber.StatusPreconditionRequired, ""},
+		{"weak", `W/"7"`, fiber.StatusPreconditionFailed, ""},
+		{"unquoted", "7", fiber.StatusPreconditionFailed, ""},
+		{"not a version", `"abc"`, fiber.StatusPreconditionFailed, ""},
+		{"hash only", `"-abc"`, fiber.StatusPreconditionFailed, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if test.ifMatch != "" {
+				req.Header.Set(fiber.HeaderIfMatch, test.ifMatch)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, test.status)
+			}
+			if test.status == fiber.StatusOK {
+				var version [8]byte
+				n, _ := resp.Body.Read(version[:])
+				if string(version[:n]) != test.version {
+					t.Errorf("version = %q, want %q", version[:n], test.version)
+				}
+			}
+		})
+	}
+}
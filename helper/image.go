@@ -0,0 +1,238 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// ProfilePhotoSizes are the square edge lengths generated for every avatar.
+var ProfilePhotoSizes = []int{64, 256, 512}
+
+// maxImagePixels guards against decompression bombs before decoding.
+const maxImagePixels = 40_000_000
+
+type ImageVariant struct {
+	Name        string
+	Width       int
+	Height      int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// ProcessProfilePhoto decodes a JPEG, PNG or GIF, center-crops it to a square
+// and re-encodes it at every ProfilePhotoSizes plus a WebP copy of the largest.
+// Re-encoding drops EXIF (GPS included) and any other metadata.
+func ProcessProfilePhoto(reader io.Reader) ([]ImageVariant, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image (JPEG, PNG or GIF only)")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.New("invalid image (too many pixels)")
+	}
+
+	var src image.Image
+	switch format {
+	case "jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+		if err == nil {
+			src = applyExifOrientation(src, jpegOrientation(data))
+		}
+	case "png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		src, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, errors.New("invalid image (JPEG, PNG or GIF only)")
+	}
+	if err != nil {
+		return nil, errors.New("invalid image (corrupted file)")
+	}
+
+	square := cropSquare(src)
+
+	var variants []ImageVariant
+	var largest image.Image
+	for _, size := range ProfilePhotoSizes {
+		resized := resize(square, size)
+		largest = resized
+
+		variant := ImageVariant{
+			Name:   fmt.Sprintf("%d", size),
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+		}
+
+		var buffer bytes.Buffer
+		if format == "jpeg" {
+			err = jpeg.Encode(&buffer, resized, &jpeg.Options{Quality: 85})
+			variant.ContentType = "image/jpeg"
+			variant.Extension = ".jpg"
+		} else {
+			// PNG and GIF sources may carry transparency
+			err = png.Encode(&buffer, resized)
+			variant.ContentType = "image/png"
+			variant.Extension = ".png"
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		variant.Data = buffer.Bytes()
+		variants = append(variants, variant)
+	}
+
+	var buffer bytes.Buffer
+	if err := nativewebp.Encode(&buffer, largest, nil); err != nil {
+		return nil, err
+	}
+	variants = append(variants, ImageVariant{
+		Name:        "webp",
+		Width:       largest.Bounds().Dx(),
+		Height:      largest.Bounds().Dy(),
+		ContentType: "image/webp",
+		Extension:   ".webp",
+		Data:        buffer.Bytes(),
+	})
+
+	return variants, nil
+}
+
+func cropSquare(src image.Image) image.Image {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, image.Point{X: x, Y: y}, draw.Src)
+	return dst
+}
+
+// resize never upscales: a 100px source stays 100px for the 256 and 512 variants.
+func resize(src image.Image, size int) image.Image {
+	if src.Bounds().Dx() <= size {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation tag (1-8), or 1 when missing.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for offset := 2; offset+4 <= len(data); {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if marker == 0xDA || length < 2 || offset+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// applyExifOrientation bakes the orientation into the pixels, since the tag
+// itself is lost when the image is re-encoded.
+func applyExifOrientation(src image.Image, orientation int) image.Image {
+	if orientation == 1 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			dst.Set(dx, dy, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,182 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+// twoToneImage is red in its top half and blue in its bottom half.
+func twoToneImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if y < height/2 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+// exifSegment is an APP1 segment holding only the orientation tag.
+func exifSegment(order binary.AppendByteOrder, orientation uint16) []byte {
+	tiff := []byte("MM")
+	if order == binary.AppendByteOrder(binary.LittleEndian) {
+		tiff = []byte("II")
+	}
+	tiff = order.AppendUint16(tiff, 42)
+	tiff = order.AppendUint32(tiff, 8)
+	tiff = order.AppendUint16(tiff, 1)
+	tiff = order.AppendUint16(tiff, 0x0112)
+	tiff = order.AppendUint16(tiff, 3)
+	tiff = order.AppendUint32(tiff, 1)
+	tiff = order.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func encodeJPEG(t *testing.T, img image.Image, exif []byte) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+	// the segment goes right after the SOI marker
+	return append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+}
+
+func TestJpegOrientation(t *testing.T) {
+	plain := encodeJPEG(t, twoToneImage(4, 4), nil)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"no exif", plain, 1},
+		{"big endian", encodeJPEG(t, twoToneImage(4, 4), exifSegment(binary.BigEndian, 6)), 6},
+		{"little endian", encodeJPEG(t, twoToneImage(4, 4), exifSegment(binary.LittleEndian, 8)), 8},
+		{"out of range", encodeJPEG(t, twoToneImage(4, 4), exifSegment(binary.BigEndian, 9)), 1},
+		{"not a jpeg", []byte("GIF89a"), 1},
+		{"truncated", plain[:3], 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := jpegOrientation(test.data); got != test.want {
+				t.Errorf("orientation = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func isNear(got color.Color, want color.RGBA) bool {
+	r, g, b, _ := got.RGBA()
+	near := func(value uint32, want uint8) bool {
+		diff := int(value>>8) - int(want)
+		return diff > -48 && diff < 48
+	}
+	return near(r, want.R) && near(g, want.G) && near(b, want.B)
+}
+
+func TestProcessProfilePhotoAppliesExifOrientation(t *testing.T) {
+	// orientation 6 turns the landscape source a quarter clockwise, so the
+	// red top half ends up on the right of the square crop
+	data := encodeJPEG(t, twoToneImage(40, 20), exifSegment(binary.BigEndian, 6))
+
+	variants, err := ProcessProfilePhoto(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(variants[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 20 || img.Bounds().Dy() != 20 {
+		t.Fatalf("size = %v, want 20x20", img.Bounds().Size())
+	}
+	if left, right := img.At(3, 10), img.At(16, 10); !isNear(left, blue) || !isNear(right, red) {
+		t.Errorf("left = %v right = %v, want blue on the left and red on the right", left, right)
+	}
+}
+
+func TestProcessProfilePhotoSizes(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, twoToneImage(600, 300)); err != nil {
+		t.Fatal(err)
+	}
+
+	variants, err := ProcessProfilePhoto(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the 300px square crop is scaled down but never up
+	want := []struct {
+		name        string
+		size        int
+		contentType string
+	}{
+		{"64", 64, "image/png"},
+		{"256", 256, "image/png"},
+		{"512", 300, "image/png"},
+		{"webp", 300, "image/webp"},
+	}
+	if len(variants) != len(want) {
+		t.Fatalf("got %d variants, want %d", len(variants), len(want))
+	}
+	for i, variant := range variants {
+		if variant.Name != want[i].name || variant.Width != want[i].size || variant.Height != want[i].size || variant.ContentType != want[i].contentType {
+			t.Errorf("variant %d = %s %dx%d %s, want %s %dx%d %s", i, variant.Name, variant.Width, variant.Height, variant.ContentType,
+				want[i].name, want[i].size, want[i].size, want[i].contentType)
+		}
+	}
+
+	img, err := png.Decode(bytes.NewReader(variants[1].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 256 || img.Bounds().Dy() != 256 {
+		t.Errorf("encoded size = %v, want 256x256", img.Bounds().Size())
+	}
+}
+
+func TestProcessProfilePhotoRejects(t *testing.T) {
+	// the headers are intact, the scan data is cut short
+	truncated := encodeJPEG(t, twoToneImage(16, 16), nil)
+	truncated = truncated[:len(truncated)-20]
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"not an image", []byte("hello"), "invalid image (JPEG, PNG or GIF only)"},
+		// a 65535x65535 logical screen
+		{"too many pixels", []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00"), "invalid image (too many pixels)"},
+		{"corrupted", truncated, "invalid image (corrupted file)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ProcessProfilePhoto(bytes.NewReader(test.data)); err == nil || err.Error() != test.err {
+				t.Errorf("err = %v, want %q", err, test.err)
+			}
+		})
+	}
+}
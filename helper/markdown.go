@@ -0,0 +1,179 @@
+package helper
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"strings"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/response"
+)
+
+// wordsPerMinute is the reading speed used for ReadingTime.
+const wordsPerMinute = 200
+
+type RenderedMarkdown struct {
+	Html            string
+	TableOfContents []response.TableOfContentsEntry
+	WordCount       int
+	ReadingTime     int
+}
+
+// markdown renders CommonMark plus the GFM extensions. Raw HTML inside the
+// source is omitted by goldmark; htmlPolicy sanitises the output regardless.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+var htmlPolicy = newHtmlPolicy()
+
+var plainTextPolicy = bluemonday.StrictPolicy()
+
+func newHtmlPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	// heading anchors for the table of contents
+	policy.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	// task list checkboxes
+	policy.AllowAttrs("type").Matching(bluemonday.SpaceSeparatedTokens).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	policy.RequireNoFollowOnLinks(true)
+	return policy
+}
+
+// RenderMarkdown converts article content to sanitised HTML and collects the
+// headings, word count and estimated reading time in minutes. Mentions of the
+// given users become links to their profiles.
+func RenderMarkdown(source string, mentions []entity.Mention) RenderedMarkdown {
+	if strings.TrimSpace(source) == "" {
+		return RenderedMarkdown{}
+	}
+
+	src := []byte(source)
+	document := markdown.Parser().Parse(text.NewReader(src))
+	linkMentions(document, src, mentions)
+
+	var toc []response.TableOfContentsEntry
+	err := ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		anchor, _ := heading.AttributeString("id")
+		id, _ := anchor.([]byte)
+		toc = append(toc, response.TableOfContentsEntry{
+			Level:  heading.Level,
+			Text:   nodeText(heading, src),
+			Anchor: string(id),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	PanicIfErr(err)
+
+	var output bytes.Buffer
+	err = markdown.Renderer().Render(&output, src, document)
+	PanicIfErr(err)
+
+	html := htmlPolicy.SanitizeBytes(output.Bytes())
+	wordCount := len(strings.Fields(plainTextPolicy.Sanitize(string(html))))
+
+	return RenderedMarkdown{
+		Html:            string(html),
+		TableOfContents: toc,
+		WordCount:       wordCount,
+		ReadingTime:     (wordCount + wordsPerMinute - 1) / wordsPerMinute,
+	}
+}
+
+// linkMentions wraps the known @username mentions found in the text of the
+// document in links. Text inside links and code is left alone.
+func linkMentions(document ast.Node, source []byte, mentions []entity.Mention) {
+	known := mentionIndex(mentions)
+	if len(known) == 0 {
+		return
+	}
+
+	var texts []*ast.Text
+	err := ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := node.(type) {
+		case *ast.Link, *ast.AutoLink, *ast.Image, *ast.CodeSpan:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, node)
+		}
+		return ast.WalkContinue, nil
+	})
+	PanicIfErr(err)
+
+	merged := map[*ast.Text]bool{}
+	for _, node := range texts {
+		if merged[node] {
+			continue
+		}
+		// the parser splits text at characters such as "_", join the pieces
+		// again so "@john_doe" is seen whole
+		for !node.SoftLineBreak() && !node.HardLineBreak() {
+			next, ok := node.NextSibling().(*ast.Text)
+			if !ok || next.Segment.Start != node.Segment.Stop {
+				break
+			}
+			node.Segment = node.Segment.WithStop(next.Segment.Stop)
+			node.SetSoftLineBreak(next.SoftLineBreak())
+			node.SetHardLineBreak(next.HardLineBreak())
+			node.Parent().RemoveChild(node.Parent(), next)
+			merged[next] = true
+		}
+
+		segment := node.Segment
+		value := segment.Value(source)
+		parent := node.Parent()
+		start := 0
+		for _, match := range mentionPattern.FindAllSubmatchIndex(value, -1) {
+			username, ok := known[strings.ToLower(string(value[match[2]:match[3]]))]
+			if !ok {
+				continue
+			}
+			at := match[2] - 1
+			if at > start {
+				parent.InsertBefore(parent, node, ast.NewTextSegment(text.NewSegment(segment.Start+start, segment.Start+at)))
+			}
+			link := ast.NewLink()
+			link.Destination = []byte(ProfileUrl(username))
+			link.AppendChild(link, ast.NewTextSegment(text.NewSegment(segment.Start+at, segment.Start+match[3])))
+			parent.InsertBefore(parent, node, link)
+			start = match[3]
+		}
+		// the original node keeps the rest of the text and its line break
+		node.Segment = text.NewSegment(segment.Start+start, segment.Stop)
+	}
+}
+
+func nodeText(node ast.Node, source []byte) string {
+	var builder strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch t := child.(type) {
+		case *ast.Text:
+			builder.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() {
+				builder.WriteByte(' ')
+			}
+		case *ast.String:
+			builder.Write(t.Value)
+		default:
+			builder.WriteString(nodeText(child, source))
+		}
+	}
+	return builder.String()
+}
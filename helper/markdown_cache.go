@@ -0,0 +1,101 @@
+package helper
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"uaspw2/models/entity"
+)
+
+// renderCacheSize is the number of rendered articles kept in memory.
+const renderCacheSize = 1024
+
+type renderKey struct {
+	articleId int
+	version   int
+}
+
+type renderEntry struct {
+	key      renderKey
+	source   string
+	rendered RenderedMarkdown
+}
+
+// renderCache keeps the most recently read renderings. Every edit bumps the
+// article version, mentions included, so an entry never has to be invalidated.
+// A rolled back edit can leave an entry for a version that is later reused,
+// which is why the source is kept and compared as well.
+type renderCache struct {
+	mutex   sync.Mutex
+	size    int
+	order   *list.List
+	entries map[renderKey]*list.Element
+}
+
+var articleRenders = newRenderCache(renderCacheSize)
+
+func newRenderCache(size int) *renderCache {
+	return &renderCache{
+		size:    size,
+		order:   list.New(),
+		entries: map[renderKey]*list.Element{},
+	}
+}
+
+func (cache *renderCache) get(key renderKey, source string) (RenderedMarkdown, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok || element.Value.(*renderEntry).source != source {
+		return RenderedMarkdown{}, false
+	}
+	cache.order.MoveToFront(element)
+	return element.Value.(*renderEntry).rendered, true
+}
+
+func (cache *renderCache) put(key renderKey, source string, rendered RenderedMarkdown) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		element.Value = &renderEntry{key: key, source: source, rendered: rendered}
+		cache.order.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.order.PushFront(&renderEntry{key: key, source: source, rendered: rendered})
+	if cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*renderEntry).key)
+	}
+}
+
+// RenderArticle renders the content of a stored article once per version.
+// The article must carry its mentions.
+func RenderArticle(article entity.Article) RenderedMarkdown {
+	if article.Id == 0 {
+		return RenderMarkdown(article.Content, article.Mentions)
+	}
+
+	key := renderKey{articleId: article.Id, version: article.Version}
+	source := renderSource(article)
+	if rendered, ok := articleRenders.get(key, source); ok {
+		return rendered
+	}
+	rendered := RenderMarkdown(article.Content, article.Mentions)
+	articleRenders.put(key, source, rendered)
+	return rendered
+}
+
+// renderSource is everything the rendering of an article depends on.
+func renderSource(article entity.Article) string {
+	var source strings.Builder
+	for _, mention := range article.Mentions {
+		source.WriteString(mention.Username)
+		source.WriteByte(' ')
+	}
+	source.WriteByte('\n')
+	source.WriteString(article.Content)
+	return source.String()
+}
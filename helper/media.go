@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// ArticleMediaMaxSizes follows the article_medias.type ENUM.
+var ArticleMediaMaxSizes = map[string]int64{
+	"pdf":   10 * 1024 * 1024,
+	"docx":  10 * 1024 * 1024,
+	"mp3":   20 * 1024 * 1024,
+	"video": 100 * 1024 * 1024,
+}
+
+type DetectedMedia struct {
+	Type        string
+	ContentType string
+	Extension   string
+}
+
+// DetectArticleMedia sniffs the file content instead of trusting the
+// Content-Type header or file name sent by the client.
+func DetectArticleMedia(file multipart.File, size int64) (DetectedMedia, error) {
+	header := make([]byte, 512)
+	n, err := file.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return DetectedMedia{}, err
+	}
+	header = header[:n]
+
+	var media DetectedMedia
+	switch contentType := http.DetectContentType(header); contentType {
+	case "application/pdf":
+		media = DetectedMedia{Type: "pdf", ContentType: contentType, Extension: ".pdf"}
+	case "application/zip":
+		if !isDocx(file, size) {
+			return DetectedMedia{}, errors.New("invalid file type (PDF, DOCX, MP3 or video only)")
+		}
+		media = DetectedMedia{Type: "docx", ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Extension: ".docx"}
+	case "audio/mpeg":
+		media = DetectedMedia{Type: "mp3", ContentType: contentType, Extension: ".mp3"}
+	case "video/mp4":
+		media = DetectedMedia{Type: "video", ContentType: contentType, Extension: ".mp4"}
+	case "video/webm":
+		media = DetectedMedia{Type: "video", ContentType: contentType, Extension: ".webm"}
+	default:
+		// http.DetectContentType only recognises MP3 files with an ID3 tag,
+		// so fall back to an MPEG audio frame sync check.
+		if len(header) >= 2 && header[0] == 0xFF && header[1]&0xE0 == 0xE0 {
+			media = DetectedMedia{Type: "mp3", ContentType: "audio/mpeg", Extension: ".mp3"}
+		} else {
+			return DetectedMedia{}, errors.New("invalid file type (PDF, DOCX, MP3 or video only)")
+		}
+	}
+
+	if maxSize := ArticleMediaMaxSizes[media.Type]; size > maxSize {
+		return DetectedMedia{}, fmt.Errorf("invalid file size (%s max %d MB)", media.Type, maxSize/1024/1024)
+	}
+
+	return media, nil
+}
+
+func isDocx(file multipart.File, size int64) bool {
+	reader, err := zip.NewReader(file, size)
+	if err != nil {
+		return false
+	}
+	for _, f := range reader.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
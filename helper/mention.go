@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"uaspw2/config"
+	"uaspw2/models/entity"
+)
+
+// mentionPattern matches @username when the @ does not follow a word
+// character, so e-mail addresses are not taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@(\w+(?:\.\w+)*)`)
+
+// ParseMentions returns the distinct usernames mentioned in text, in order of
+// first appearance.
+func ParseMentions(text string) []string {
+	var usernames []string
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		key := strings.ToLower(match[1])
+		if !seen[key] {
+			seen[key] = true
+			usernames = append(usernames, match[1])
+		}
+	}
+	return usernames
+}
+
+func ProfileUrl(username string) string {
+	return config.FeedSiteUrl + "/users/" + url.PathEscape(username)
+}
+
+// LinkifyMentions escapes plain text for HTML and turns the mentions of the
+// given users into links to their profiles. Other @words are left as text.
+func LinkifyMentions(text string, mentions []entity.Mention) string {
+	known := mentionIndex(mentions)
+
+	var builder strings.Builder
+	start := 0
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		username, ok := known[strings.ToLower(text[match[2]:match[3]])]
+		if !ok {
+			continue
+		}
+		at := match[2] - 1
+		builder.WriteString(html.EscapeString(text[start:at]))
+		builder.WriteString(`<a href="` + html.EscapeString(ProfileUrl(username)) + `" rel="nofollow">@` + html.EscapeString(text[match[2]:match[3]]) + `</a>`)
+		start = match[3]
+	}
+	builder.WriteString(html.EscapeString(text[start:]))
+	return builder.String()
+}
+
+// mentionIndex maps the lower-cased username of each mention to its stored form.
+func mentionIndex(mentions []entity.Mention) map[string]string {
+	known := map[string]string{}
+	for _, mention := range mentions {
+		known[strings.ToLower(mention.Username)] = mention.Username
+	}
+	return known
+}
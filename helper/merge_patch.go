@@ -0,0 +1,25 @@
+package helper
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to target. Both values are
+// the result of json.Unmarshal into an any: null members are removed, objects
+// are merged recursively and everything else, arrays included, is replaced.
+func MergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = MergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
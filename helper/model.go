@@ -2,7 +2,9 @@ package helper
 
 import (
 	"database/sql"
+	"encoding/json"
 	"strconv"
+	"strings"
 	"uaspw2/exception"
 	"uaspw2/models/entity"
 	"uaspw2/models/web/response"
@@ -47,14 +49,17 @@ func ToUserWithProfileResponses(users []entity.UserWithProfile) []response.UserW
 
 func ToUserProfileResponse(userProfile entity.UserProfile) response.UserProfileResponse {
 	return response.UserProfileResponse{
-		UserId:      userProfile.UserId,
-		FullName:    userProfile.FullName,
-		Gender:      userProfile.Gender,
-		BirthDate:   userProfile.BirthDate,
-		PhoneNumber: userProfile.PhoneNumber,
-		Address:     userProfile.Address,
-		CreatedAt:   userProfile.CreatedAt,
-		UpdatedAt:   userProfile.UpdatedAt,
+		UserId:         userProfile.UserId,
+		FullName:       userProfile.FullName,
+		Gender:         userProfile.Gender,
+		BirthDate:      userProfile.BirthDate,
+		PhoneNumber:    userProfile.PhoneNumber,
+		Address:        userProfile.Address,
+		Version:        userProfile.Version,
+		FollowersCount: userProfile.FollowersCount,
+		FollowingCount: userProfile.FollowingCount,
+		CreatedAt:      userProfile.CreatedAt,
+		UpdatedAt:      userProfile.UpdatedAt,
 	}
 }
 
@@ -70,23 +75,59 @@ func ToUserPhotoProfileResponse(userPhotoProfile entity.UserProfilePhoto) respon
 	return response.UserProfilePhotoResponse{
 		UserId:    userPhotoProfile.UserId,
 		Path:      userPhotoProfile.Path,
+		Variants:  ToUserPhotoProfileVariantResponses(userPhotoProfile.Variants),
 		CreatedAt: userPhotoProfile.CreatedAt,
 		UpdatedAt: userPhotoProfile.UpdatedAt,
 	}
 }
 
+func ToUserPhotoProfileVariantResponse(variant entity.UserProfilePhotoVariant) response.UserProfilePhotoVariantResponse {
+	return response.UserProfilePhotoVariantResponse{
+		Name:        variant.Name,
+		Path:        variant.Path,
+		Width:       variant.Width,
+		Height:      variant.Height,
+		ContentType: variant.ContentType,
+	}
+}
+
+func ToUserPhotoProfileVariantResponses(variants []entity.UserProfilePhotoVariant) []response.UserProfilePhotoVariantResponse {
+	var variantResponses []response.UserProfilePhotoVariantResponse
+	for _, variant := range variants {
+		variantResponses = append(variantResponses, ToUserPhotoProfileVariantResponse(variant))
+	}
+	return variantResponses
+}
+
 func ToArticleResponse(article entity.Article) response.ArticleResponse {
+	content := RenderArticle(article)
+	reactions := article.Reactions
+	if reactions == nil {
+		reactions = map[string]int{}
+	}
 	return response.ArticleResponse{
-		Id:          article.Id,
-		UserId:      article.UserId,
-		Title:       article.Title,
-		Description: article.Description,
-		Content:     article.Content,
-		Author:      article.Author,
-		Media:       article.Media,
-		IsPublished: article.IsPublished,
-		CreatedAt:   article.CreatedAt,
-		UpdatedAt:   article.UpdatedAt,
+		Id:              article.Id,
+		UserId:          article.UserId,
+		Title:           article.Title,
+		Slug:            article.Slug,
+		Description:     article.Description,
+		Content:         article.Content,
+		ContentHtml:     content.Html,
+		TableOfContents: content.TableOfContents,
+		WordCount:       content.WordCount,
+		ReadingTime:     content.ReadingTime,
+		Author:          article.Author,
+		Media:           ToArticleMediaResponses(article.Media),
+		Tags:            ToTagResponses(article.Tags),
+		Categories:      ToCategoryResponses(article.Categories),
+		Mentions:        ToMentionResponses(article.Mentions),
+		Reactions:       reactions,
+		LikeCount:       reactions["like"],
+		CommentCount:    article.CommentCount,
+		IsPublished:     article.IsPublished,
+		Version:         article.Version,
+		CreatedAt:       article.CreatedAt,
+		UpdatedAt:       article.UpdatedAt,
 	}
 }
 
@@ -98,14 +139,88 @@ func ToArticleResponses(articles []entity.Article) []response.ArticleResponse {
 	return articleResponses
 }
 
+func ToArticleMediaResponse(media entity.ArticleMedia) response.ArticleMediaResponse {
+	return response.ArticleMediaResponse{
+		Id:        media.Id,
+		ArticleId: media.ArticleId,
+		Type:      media.Type,
+		Path:      media.Path,
+		CreatedAt: media.CreatedAt,
+		UpdatedAt: media.UpdatedAt,
+	}
+}
+
+func ToArticleMediaResponses(medias []entity.ArticleMedia) []response.ArticleMediaResponse {
+	var mediaResponses []response.ArticleMediaResponse
+	for _, media := range medias {
+		mediaResponses = append(mediaResponses, ToArticleMediaResponse(media))
+	}
+	return mediaResponses
+}
+
+func ToTagResponse(tag entity.Tag) response.TagResponse {
+	return response.TagResponse{
+		Id:           tag.Id,
+		Name:         tag.Name,
+		Slug:         tag.Slug,
+		ArticleCount: tag.ArticleCount,
+		CreatedAt:    tag.CreatedAt,
+		UpdatedAt:    tag.UpdatedAt,
+	}
+}
+
+func ToTagResponses(tags []entity.Tag) []response.TagResponse {
+	var tagResponses []response.TagResponse
+	for _, tag := range tags {
+		tagResponses = append(tagResponses, ToTagResponse(tag))
+	}
+	return tagResponses
+}
+
+func ToCategoryResponse(category entity.Category) response.CategoryResponse {
+	return response.CategoryResponse{
+		Id:           category.Id,
+		ParentId:     category.ParentId,
+		Name:         category.Name,
+		Slug:         category.Slug,
+		ArticleCount: category.ArticleCount,
+		CreatedAt:    category.CreatedAt,
+		UpdatedAt:    category.UpdatedAt,
+	}
+}
+
+func ToCategoryResponses(categories []entity.Category) []response.CategoryResponse {
+	var categoryResponses []response.CategoryResponse
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, ToCategoryResponse(category))
+	}
+	return categoryResponses
+}
+
 func ToLikeResponse(like entity.Like) response.LikeResponse {
-	return response.LikeResponse{
+	likeResponse := response.LikeResponse{
 		Id:        like.Id,
 		UserId:    like.UserId,
 		ArticleId: like.ArticleId,
+		Reaction:  like.Reaction,
 		CreatedAt: like.CreatedAt,
 		UpdatedAt: like.UpdatedAt,
 	}
+	if like.User != nil {
+		likeResponse.User = &response.LikeUserResponse{
+			Username:  like.User.Username,
+			FullName:  like.User.FullName,
+			PhotoPath: like.User.PhotoPath,
+		}
+	}
+	if like.Article != nil {
+		likeResponse.Article = &response.LikeArticleResponse{
+			Title:  like.Article.Title,
+			Slug:   like.Article.Slug,
+			Author: like.Article.Author,
+		}
+	}
+	return likeResponse
 }
 
 func ToLikeResponses(likes []entity.Like) []response.LikeResponse {
@@ -116,16 +231,107 @@ func ToLikeResponses(likes []entity.Like) []response.LikeResponse {
 	return likeResponses
 }
 
+// ToCommentResponse hides the author and text of a deleted comment that is
+// kept as a placeholder for its replies.
 func ToCommentResponse(comment entity.Comment) response.CommentResponse {
-	return response.CommentResponse{
-		Id:        comment.Id,
-		UserId:    comment.UserId,
-		ArticleId: comment.ArticleId,
-		Comment:   comment.Comment,
-		Author:    comment.Author,
-		CreatedAt: comment.CreatedAt,
-		UpdatedAt: comment.UpdatedAt,
+	commentResponse := response.CommentResponse{
+		Id:          comment.Id,
+		UserId:      comment.UserId,
+		ArticleId:   comment.ArticleId,
+		ParentId:    comment.ParentId,
+		Depth:       comment.Depth,
+		Comment:     comment.Comment,
+		CommentHtml: LinkifyMentions(comment.Comment, comment.Mentions),
+		Mentions:    ToMentionResponses(comment.Mentions),
+		Author:      comment.Author,
+		ReplyCount:  comment.ReplyCount,
+		IsHidden:    comment.IsHidden,
+		IsDeleted:   comment.IsDeleted,
+		IsEdited:    comment.EditedAt != "",
+		CreatedAt:   comment.CreatedAt,
+		UpdatedAt:   comment.UpdatedAt,
+		EditedAt:    comment.EditedAt,
+	}
+	if comment.IsDeleted {
+		commentResponse.UserId = 0
+		commentResponse.Author = ""
+		commentResponse.Comment = "[deleted]"
+		commentResponse.CommentHtml = "[deleted]"
+		commentResponse.Mentions = nil
 	}
+	return commentResponse
+}
+
+func ToMentionResponses(mentions []entity.Mention) []response.MentionResponse {
+	var mentionResponses []response.MentionResponse
+	for _, mention := range mentions {
+		mentionResponses = append(mentionResponses, response.MentionResponse{
+			UserId:   mention.UserId,
+			Username: mention.Username,
+		})
+	}
+	return mentionResponses
+}
+
+func ToNotificationResponse(notification entity.Notification) response.NotificationResponse {
+	return response.NotificationResponse{
+		Id:         notification.Id,
+		ActorId:    notification.ActorId,
+		Actor:      notification.Actor,
+		Type:       notification.Type,
+		TargetType: notification.TargetType,
+		TargetId:   notification.TargetId,
+		ArticleId:  notification.ArticleId,
+		IsRead:     notification.ReadAt != "",
+		ReadAt:     notification.ReadAt,
+		CreatedAt:  notification.CreatedAt,
+	}
+}
+
+func ToNotificationResponses(notifications []entity.Notification) []response.NotificationResponse {
+	var notificationResponses []response.NotificationResponse
+	for _, notification := range notifications {
+		notificationResponses = append(notificationResponses, ToNotificationResponse(notification))
+	}
+	return notificationResponses
+}
+
+func ToBlockResponse(block entity.Block) response.BlockResponse {
+	return response.BlockResponse{
+		BlockerId: block.BlockerId,
+		BlockedId: block.BlockedId,
+		CreatedAt: block.CreatedAt,
+	}
+}
+
+func ToBlockedUserResponses(users []entity.BlockedUser) []response.BlockedUserResponse {
+	var userResponses []response.BlockedUserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, response.BlockedUserResponse{
+			UserId:    user.UserId,
+			Username:  user.Username,
+			FullName:  user.FullName,
+			BlockedAt: user.BlockedAt,
+		})
+	}
+	return userResponses
+}
+
+func ToCommentRevisionResponse(revision entity.CommentRevision) response.CommentRevisionResponse {
+	return response.CommentRevisionResponse{
+		Id:        revision.Id,
+		CommentId: revision.CommentId,
+		Comment:   revision.Comment,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+func ToCommentRevisionResponses(revisions []entity.CommentRevision) []response.CommentRevisionResponse {
+	var revisionResponses []response.CommentRevisionResponse
+	for _, revision := range revisions {
+		revisionResponses = append(revisionResponses, ToCommentRevisionResponse(revision))
+	}
+	return revisionResponses
 }
 
 func ToCommentResponses(comments []entity.Comment) []response.CommentResponse {
@@ -136,6 +342,202 @@ func ToCommentResponses(comments []entity.Comment) []response.CommentResponse {
 	return commentResponses
 }
 
+func ToArticleAnalyticsResponse(stats entity.ArticleStats, from string, to string) response.ArticleAnalyticsResponse {
+	return response.ArticleAnalyticsResponse{
+		ArticleId:     stats.ArticleId,
+		Title:         stats.Title,
+		Slug:          stats.Slug,
+		From:          from,
+		To:            to,
+		Views:         stats.Views,
+		UniqueReaders: stats.UniqueReaders,
+		Likes:         stats.Likes,
+		Comments:      stats.Comments,
+	}
+}
+
+func ToArticleAnalyticsResponses(stats []entity.ArticleStats, from string, to string) []response.ArticleAnalyticsResponse {
+	var analyticsResponses []response.ArticleAnalyticsResponse
+	for _, stat := range stats {
+		analyticsResponses = append(analyticsResponses, ToArticleAnalyticsResponse(stat, from, to))
+	}
+	return analyticsResponses
+}
+
+func ToArticleStatsDailyResponse(stat entity.ArticleStatsDaily) response.ArticleStatsDailyResponse {
+	return response.ArticleStatsDailyResponse{
+		Day:           stat.Day,
+		Views:         stat.Views,
+		UniqueReaders: stat.UniqueReaders,
+		Likes:         stat.Likes,
+		Comments:      stat.Comments,
+	}
+}
+
+func ToFollowResponse(follow entity.Follow) response.FollowResponse {
+	return response.FollowResponse{
+		FollowerId: follow.FollowerId,
+		FolloweeId: follow.FolloweeId,
+		CreatedAt:  follow.CreatedAt,
+	}
+}
+
+func ToFollowUserResponse(user entity.FollowUser) response.FollowUserResponse {
+	return response.FollowUserResponse{
+		UserId:     user.UserId,
+		Username:   user.Username,
+		FullName:   user.FullName,
+		FollowedAt: user.FollowedAt,
+	}
+}
+
+func ToFollowUserResponses(users []entity.FollowUser) []response.FollowUserResponse {
+	var userResponses []response.FollowUserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, ToFollowUserResponse(user))
+	}
+	return userResponses
+}
+
+func ToBookmarkResponse(bookmark entity.Bookmark) response.BookmarkResponse {
+	return response.BookmarkResponse{
+		UserId:    bookmark.UserId,
+		ArticleId: bookmark.ArticleId,
+		CreatedAt: bookmark.CreatedAt,
+	}
+}
+
+func ToReadingListResponse(readingList entity.ReadingList) response.ReadingListResponse {
+	return response.ReadingListResponse{
+		Id:          readingList.Id,
+		UserId:      readingList.UserId,
+		Name:        readingList.Name,
+		Description: readingList.Description,
+		IsPublic:    readingList.IsPublic,
+		ItemCount:   readingList.ItemCount,
+		Items:       ToReadingListItemResponses(readingList.Items),
+		CreatedAt:   readingList.CreatedAt,
+		UpdatedAt:   readingList.UpdatedAt,
+	}
+}
+
+func ToReadingListResponses(readingLists []entity.ReadingList) []response.ReadingListResponse {
+	var readingListResponses []response.ReadingListResponse
+	for _, readingList := range readingLists {
+		readingListResponses = append(readingListResponses, ToReadingListResponse(readingList))
+	}
+	return readingListResponses
+}
+
+func ToReadingListItemResponse(item entity.ReadingListItem) response.ReadingListItemResponse {
+	return response.ReadingListItemResponse{
+		Position:  item.Position,
+		Note:      item.Note,
+		Article:   ToArticleResponse(item.Article),
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func ToReadingListItemResponses(items []entity.ReadingListItem) []response.ReadingListItemResponse {
+	var itemResponses []response.ReadingListItemResponse
+	for _, item := range items {
+		itemResponses = append(itemResponses, ToReadingListItemResponse(item))
+	}
+	return itemResponses
+}
+
+func ToReportResponse(report entity.Report) response.ReportResponse {
+	return response.ReportResponse{
+		Id:         report.Id,
+		ReporterId: report.ReporterId,
+		Reporter:   report.Reporter,
+		TargetType: report.TargetType,
+		TargetId:   report.TargetId,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		CreatedAt:  report.CreatedAt,
+		UpdatedAt:  report.UpdatedAt,
+	}
+}
+
+func ToModerationActionResponse(action entity.ModerationAction) response.ModerationActionResponse {
+	return response.ModerationActionResponse{
+		Id:          action.Id,
+		ModeratorId: action.ModeratorId,
+		TargetType:  action.TargetType,
+		TargetId:    action.TargetId,
+		UserId:      action.UserId,
+		Action:      action.Action,
+		Note:        action.Note,
+		SuspendDays: action.SuspendDays,
+		CreatedAt:   action.CreatedAt,
+	}
+}
+
+func ToModerationActionResponses(actions []entity.ModerationAction) []response.ModerationActionResponse {
+	var actionResponses []response.ModerationActionResponse
+	for _, action := range actions {
+		actionResponses = append(actionResponses, ToModerationActionResponse(action))
+	}
+	return actionResponses
+}
+
+func ToWebhookResponse(webhook entity.Webhook) response.WebhookResponse {
+	return response.WebhookResponse{
+		Id:        webhook.Id,
+		Url:       webhook.Url,
+		Events:    webhook.Events,
+		IsActive:  webhook.IsActive,
+		CreatedBy: webhook.CreatedBy,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func ToWebhookResponses(webhooks []entity.Webhook) []response.WebhookResponse {
+	var webhookResponses []response.WebhookResponse
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, ToWebhookResponse(webhook))
+	}
+	return webhookResponses
+}
+
+func ToWebhookDeliveryResponse(delivery entity.WebhookDelivery) response.WebhookDeliveryResponse {
+	deliveryResponse := response.WebhookDeliveryResponse{
+		Id:            delivery.Id,
+		WebhookId:     delivery.WebhookId,
+		Event:         delivery.Event,
+		Payload:       json.RawMessage(delivery.Payload),
+		Status:        delivery.Status,
+		AttemptCount:  delivery.AttemptCount,
+		ResponseCode:  delivery.ResponseCode,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastAttemptAt: delivery.LastAttemptAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+	for _, attempt := range delivery.Attempts {
+		deliveryResponse.Attempts = append(deliveryResponse.Attempts, response.WebhookDeliveryAttemptResponse{
+			Id:           attempt.Id,
+			ResponseCode: attempt.ResponseCode,
+			ResponseBody: attempt.ResponseBody,
+			Error:        attempt.Error,
+			DurationMs:   attempt.DurationMs,
+			CreatedAt:    attempt.CreatedAt,
+		})
+	}
+	return deliveryResponse
+}
+
+func ToWebhookDeliveryResponses(deliveries []entity.WebhookDelivery) []response.WebhookDeliveryResponse {
+	var deliveryResponses []response.WebhookDeliveryResponse
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, ToWebhookDeliveryResponse(delivery))
+	}
+	return deliveryResponses
+}
+
 func ToIntFromParams(params string) int {
 	id, err := strconv.Atoi(params)
 	if err != nil {
@@ -144,6 +546,17 @@ func ToIntFromParams(params string) int {
 	return id
 }
 
+// ToIntsFromQuery parses a comma separated list of ids such as "1,2,3".
+func ToIntsFromQuery(query string) []int {
+	var ids []int
+	for _, part := range strings.Split(query, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			ids = append(ids, ToIntFromParams(part))
+		}
+	}
+	return ids
+}
+
 func NullStringToString(ns sql.NullString) string {
 	if ns.Valid {
 		return ns.String
@@ -0,0 +1,24 @@
+package helper
+
+import (
+	"github.com/gosimple/slug"
+	"strings"
+)
+
+// Slugify transliterates s to ASCII and joins its words with dashes,
+// e.g. "Belajar Go: Dasar" becomes "belajar-go-dasar" and "Привет мир" "privet-mir".
+func Slugify(s string) string {
+	return slug.Make(s)
+}
+
+// TruncateSlug shortens s to at most max bytes without cutting a word in half.
+func TruncateSlug(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	if i := strings.LastIndex(s, "-"); i > 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "-")
+}
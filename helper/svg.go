@@ -0,0 +1,133 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// svgElements is the allow-list of elements kept by SanitizeSvg. Anything
+// else (script, foreignObject, style, iframe, editor metadata, ...) is dropped
+// together with its children.
+var svgElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "symbol": true, "use": true,
+	"title": true, "desc": true,
+	"path": true, "rect": true, "circle": true, "ellipse": true,
+	"line": true, "polyline": true, "polygon": true,
+	"text": true, "tspan": true,
+	"linearGradient": true, "radialGradient": true, "stop": true,
+	"clipPath": true, "mask": true, "pattern": true,
+}
+
+// SanitizeSvg rewrites an SVG document keeping only allow-listed elements and
+// dropping event handlers, external references and script URLs, so the file
+// is safe to serve from our own origin.
+func SanitizeSvg(reader io.Reader) ([]byte, error) {
+	decoder := xml.NewDecoder(io.LimitReader(reader, 2*1024*1024))
+	decoder.Strict = true
+
+	var output bytes.Buffer
+	output.WriteString(xml.Header)
+
+	skipDepth := 0
+	rootSeen := false
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("invalid svg (malformed XML)")
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skipDepth > 0 || t.Name.Space != "" || !svgElements[t.Name.Local] {
+				skipDepth++
+				continue
+			}
+			if !rootSeen && t.Name.Local != "svg" {
+				return nil, errors.New("invalid svg (root element must be svg)")
+			}
+			rootSeen = true
+
+			output.WriteString("<" + t.Name.Local)
+			for _, attr := range t.Attr {
+				if name, ok := sanitizeSvgAttr(attr); ok {
+					output.WriteString(" " + name + `="`)
+					xml.EscapeText(&output, []byte(attr.Value))
+					output.WriteString(`"`)
+				}
+			}
+			output.WriteString(">")
+		case xml.EndElement:
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			output.WriteString("</" + t.Name.Local + ">")
+		case xml.CharData:
+			if skipDepth == 0 && rootSeen {
+				xml.EscapeText(&output, t)
+			}
+		}
+		// comments, processing instructions and directives (DOCTYPE, ENTITY) are dropped
+	}
+
+	if !rootSeen {
+		return nil, errors.New("invalid svg (root element must be svg)")
+	}
+	return output.Bytes(), nil
+}
+
+func sanitizeSvgAttr(attr xml.Attr) (string, bool) {
+	name := attr.Name.Local
+	value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+
+	switch attr.Name.Space {
+	case "":
+	case "xmlns":
+		return "xmlns:" + name, true
+	case "xml":
+		// xml:base would resolve the fragment-only references against
+		// another document
+		if name != "lang" && name != "space" {
+			return "", false
+		}
+		return "xml:" + name, true
+	case "xlink":
+		if name != "href" || !strings.HasPrefix(value, "#") {
+			return "", false
+		}
+		return "xlink:href", true
+	default:
+		return "", false
+	}
+
+	if strings.HasPrefix(strings.ToLower(name), "on") {
+		return "", false
+	}
+	if name == "href" && !strings.HasPrefix(value, "#") {
+		return "", false
+	}
+	if strings.Contains(value, "javascript:") || strings.Contains(value, "data:") || strings.Contains(value, "expression(") {
+		return "", false
+	}
+	// url(...) may only point at fragments inside the same document
+	if strings.Contains(value, "url(") && !onlyLocalUrls(value) {
+		return "", false
+	}
+	return name, true
+}
+
+func onlyLocalUrls(value string) bool {
+	for _, part := range strings.Split(value, "url(")[1:] {
+		part = strings.TrimLeft(part, `'"`)
+		if !strings.HasPrefix(part, "#") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func FuzzSanitizeSvg(f *testing.F) {
+	f.Add([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><circle r="1"/></svg>`))
+	f.Add([]byte(`<svg onload="alert(1)"><script>alert(1)</script></svg>`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sanitized, err := SanitizeSvg(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		checkSanitizedSvg(t, sanitized)
+
+		// sanitizing is idempotent
+		again, err := SanitizeSvg(bytes.NewReader(sanitized))
+		if err != nil {
+			t.Fatalf("sanitized output rejected: %v\n%s", err, sanitized)
+		}
+		if !bytes.Equal(again, sanitized) {
+			t.Fatalf("sanitizing twice changed the output:\n%s\n%s", sanitized, again)
+		}
+	})
+}
+
+// checkSanitizedSvg fails unless svg only contains allow-listed elements and
+// attributes that cannot run script or load other documents.
+func checkSanitizedSvg(t *testing.T, svg []byte) {
+	t.Helper()
+
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	decoder.Strict = true
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("sanitized output is not well-formed: %v\n%s", err, svg)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Space != "" || !svgElements[start.Name.Local] {
+			t.Fatalf("element %s:%s kept", start.Name.Space, start.Name.Local)
+		}
+		for _, attr := range start.Attr {
+			name := strings.ToLower(attr.Name.Local)
+			value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+			switch {
+			case attr.Name.Space == "" && strings.HasPrefix(name, "on"):
+				t.Fatalf("event handler %s kept", attr.Name.Local)
+			case attr.Name.Space == "xml" && name == "base":
+				t.Fatalf("xml:base kept")
+			case (name == "href") && !strings.HasPrefix(value, "#"):
+				t.Fatalf("external reference %q kept", attr.Value)
+			case strings.Contains(value, "javascript:"), strings.Contains(value, "data:"):
+				t.Fatalf("script URL %q kept", attr.Value)
+			case strings.Contains(value, "url(") && !onlyLocalUrls(value):
+				t.Fatalf("external url() %q kept", attr.Value)
+			}
+		}
+	}
+}
+
+func TestSanitizeSvgDropsXmlBase(t *testing.T) {
+	sanitized, err := SanitizeSvg(strings.NewReader(`<svg xml:base="https://evil.example/" xml:space="preserve"><use href="#a"/></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sanitized, []byte("xml:base")) || !bytes.Contains(sanitized, []byte(`xml:space="preserve"`)) {
+		t.Fatalf("sanitized = %s", sanitized)
+	}
+}
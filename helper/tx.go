@@ -1,8 +1,33 @@
 package helper
 
-import "database/sql"
+import (
+	"database/sql"
+	"sync"
+)
+
+var (
+	afterCommitMutex sync.Mutex
+	afterCommit      = map[*sql.Tx][]func(){}
+)
+
+// AfterCommit queues fn to run once CommitOrRollback has committed tx. The
+// queue is dropped when the transaction rolls back or fails to commit.
+func AfterCommit(tx *sql.Tx, fn func()) {
+	afterCommitMutex.Lock()
+	defer afterCommitMutex.Unlock()
+	afterCommit[tx] = append(afterCommit[tx], fn)
+}
+
+func takeAfterCommit(tx *sql.Tx) []func() {
+	afterCommitMutex.Lock()
+	defer afterCommitMutex.Unlock()
+	fns := afterCommit[tx]
+	delete(afterCommit, tx)
+	return fns
+}
 
 func CommitOrRollback(tx *sql.Tx) {
+	fns := takeAfterCommit(tx)
 	err := recover()
 	if err != nil {
 		errorRollback := tx.Rollback()
@@ -11,5 +36,8 @@ func CommitOrRollback(tx *sql.Tx) {
 	} else {
 		errorCommit := tx.Commit()
 		PanicIfErr(errorCommit)
+		for _, fn := range fns {
+			fn()
+		}
 	}
 }
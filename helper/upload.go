@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const MaxProfilePhotoSize = 2 * 1024 * 1024
+
+// profilePhotoExtensions lists the file extensions accepted for each sniffed type.
+var profilePhotoExtensions = map[string][]string{
+	"image/jpeg":    {".jpg", ".jpeg"},
+	"image/png":     {".png"},
+	"image/gif":     {".gif"},
+	"image/svg+xml": {".svg"},
+}
+
+// DetectProfilePhoto returns the real content type of an uploaded profile
+// photo. The client supplied Content-Type header is ignored; the type comes
+// from the file's magic bytes, must agree with the file extension, and the
+// image has to be decodable.
+func DetectProfilePhoto(file multipart.File, header *multipart.FileHeader) (string, error) {
+	if header.Size > MaxProfilePhotoSize {
+		return "", errors.New("invalid file size (max 2 MB)")
+	}
+
+	head := make([]byte, 512)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		if _, _, err := image.DecodeConfig(io.NewSectionReader(file, 0, header.Size)); err != nil {
+			return "", errors.New("invalid image (corrupted file)")
+		}
+	default:
+		if !isSvg(io.NewSectionReader(file, 0, header.Size)) {
+			return "", errors.New("invalid file type (JPEG, PNG, GIF or SVG only)")
+		}
+		contentType = "image/svg+xml"
+	}
+
+	extension := strings.ToLower(filepath.Ext(header.Filename))
+	for _, allowed := range profilePhotoExtensions[contentType] {
+		if extension == allowed {
+			return contentType, nil
+		}
+	}
+	return "", errors.New("invalid file extension (does not match the file content)")
+}
+
+// isSvg reports whether the first element of the document is <svg>.
+func isSvg(reader io.Reader) bool {
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return false
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return t.Name.Local == "svg"
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return false
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"bytes"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (file memoryFile) Close() error {
+	return nil
+}
+
+func FuzzDetectProfilePhoto(f *testing.F) {
+	f.Add([]byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), "photo.svg")
+	f.Add([]byte("\x89PNG\r\n\x1a\n"), "photo.png")
+	f.Add([]byte("GIF89a"), "photo.gif")
+
+	f.Fuzz(func(t *testing.T, data []byte, filename string) {
+		var file multipart.File = memoryFile{bytes.NewReader(data)}
+		header := &multipart.FileHeader{Filename: filename, Size: int64(len(data))}
+
+		contentType, err := DetectProfilePhoto(file, header)
+		if err != nil {
+			if contentType != "" {
+				t.Fatalf("content type %q returned with error %v", contentType, err)
+			}
+			return
+		}
+
+		extensions, ok := profilePhotoExtensions[contentType]
+		if !ok {
+			t.Fatalf("unexpected content type %q", contentType)
+		}
+		extension := strings.ToLower(filepath.Ext(filename))
+		matched := false
+		for _, allowed := range extensions {
+			matched = matched || allowed == extension
+		}
+		if !matched {
+			t.Fatalf("%q accepted as %s", filename, contentType)
+		}
+		if contentType == "image/svg+xml" && !isSvg(bytes.NewReader(data)) {
+			t.Fatalf("non-svg data accepted as svg")
+		}
+	})
+}
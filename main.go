@@ -15,17 +15,24 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"time"
+	"uaspw2/config"
+	"uaspw2/contentfilter"
 	"uaspw2/controllers"
 	"uaspw2/exception"
+	"uaspw2/middlewares"
+	"uaspw2/realtime"
 	"uaspw2/repositories"
 	"uaspw2/routes"
 	"uaspw2/services"
+	"uaspw2/storage"
+	"uaspw2/webhook"
 )
 
 func main() {
 
 	app := fiber.New(fiber.Config{
 		ErrorHandler: exception.ErrorHandler,
+		BodyLimit:    110 * 1024 * 1024, // largest article media (video) plus multipart overhead
 	})
 
 	db, err := sql.Open("mysql", "root:root@tcp(localhost:3306)/uaspw2")
@@ -39,35 +46,86 @@ func main() {
 	db.SetConnMaxIdleTime(10 * time.Minute)
 
 	validate := validator.New()
+	fileStorage := storage.NewStorage()
 
 	userRepository := repositories.NewUserRepository()
 	userService := services.NewUserService(userRepository, db, validate)
+	middlewares.IsSuspended = userService.IsSuspended
 	userController := controllers.NewUserController(userService)
 
 	userProfileRepository := repositories.NewUserProfileRepository()
 	userProfileService := services.NewUserProfileService(userProfileRepository, db, validate)
 	userProfileController := controllers.NewUserProfileController(userProfileService)
 
+	webhookRepository := repositories.NewWebhookRepository()
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepository)
+	webhookService := services.NewWebhookService(webhookRepository, webhook.NewClient(config.WebhookTimeout), db, validate)
+	webhookController := controllers.NewWebhookController(webhookService)
+
 	authRepository := repositories.NewAuthenticationRepository()
-	authService := services.NewAuthenticationServices(authRepository, db, validate)
+	authService := services.NewAuthenticationServices(authRepository, webhookDispatcher, db, validate)
 	authController := controllers.NewAuthenticationController(authService)
 
 	userProfilePhotoRepository := repositories.NewUserProfilePhotoRepository()
-	userProfilePhotoService := services.NewUserProfilePhotoService(userProfilePhotoRepository, db, validate)
+	userProfilePhotoService := services.NewUserProfilePhotoService(userProfilePhotoRepository, db, validate, fileStorage)
 	userProfilePhotoController := controllers.NewUserProfilePhotoController(userProfilePhotoService)
 
 	articleRepository := repositories.NewArticleRepository()
-	articleService := services.NewArticleService(articleRepository, db, validate)
-	articleController := controllers.NewArticleController(articleService)
-
+	tagRepository := repositories.NewTagRepository()
+	categoryRepository := repositories.NewCategoryRepository()
+	moderationRepository := repositories.NewModerationRepository()
+	contentFilter := contentfilter.NewFilter()
+	notificationRepository := repositories.NewNotificationRepository()
+	blockRepository := repositories.NewBlockRepository()
+	followRepository := repositories.NewFollowRepository()
+	hub := realtime.NewHub(realtime.NewBroker(), config.RealtimeBufferSize)
+	notifier := services.NewNotifier(notificationRepository, blockRepository, followRepository, hub)
+	mentionRepository := repositories.NewMentionRepository()
+	mentionTracker := services.NewMentionTracker(mentionRepository, notifier)
+	articleService := services.NewArticleService(articleRepository, tagRepository, categoryRepository, moderationRepository, contentFilter, mentionTracker, notifier, webhookDispatcher, db, validate, fileStorage)
+	articleAnalyticsRepository := repositories.NewArticleAnalyticsRepository()
+	articleAnalyticsService := services.NewArticleAnalyticsService(articleAnalyticsRepository, articleRepository, db, validate)
+	bookmarkRepository := repositories.NewBookmarkRepository()
+	bookmarkService := services.NewBookmarkService(bookmarkRepository, articleRepository, mentionRepository, db, validate)
 	likeRepository := repositories.NewLikeRepository()
-	likeService := services.NewLikeService(likeRepository, db, validate)
+	likeService := services.NewLikeService(likeRepository, articleRepository, notifier, hub, db, validate, fileStorage)
 	likeController := controllers.NewLikeController(likeService)
+	bookmarkController := controllers.NewBookmarkController(bookmarkService, likeService)
+	articleController := controllers.NewArticleController(articleService, articleAnalyticsService, bookmarkService, likeService)
+	articleAnalyticsController := controllers.NewArticleAnalyticsController(articleAnalyticsService)
+
+	tagService := services.NewTagService(tagRepository, articleRepository, mentionRepository, db, validate)
+	tagController := controllers.NewTagController(tagService, bookmarkService, likeService)
+
+	categoryService := services.NewCategoryService(categoryRepository, articleRepository, mentionRepository, db, validate)
+	categoryController := controllers.NewCategoryController(categoryService, bookmarkService, likeService)
+
+	feedService := services.NewFeedService(articleRepository, tagRepository, userProfileRepository, mentionRepository, db, validate)
+	feedController := controllers.NewFeedController(feedService)
+
+	readingListRepository := repositories.NewReadingListRepository()
+	readingListService := services.NewReadingListService(readingListRepository, articleRepository, mentionRepository, db, validate)
+	readingListController := controllers.NewReadingListController(readingListService, bookmarkService, likeService)
+
+	followService := services.NewFollowService(followRepository, userRepository, notifier, db, validate)
+	followController := controllers.NewFollowController(followService)
+
+	blockService := services.NewBlockService(blockRepository, userRepository, db, validate)
+	blockController := controllers.NewBlockController(blockService)
+
+	notificationService := services.NewNotificationService(notificationRepository, db, validate)
+	notificationController := controllers.NewNotificationController(notificationService)
+
+	realtimeService := services.NewRealtimeService(articleRepository, hub, db, validate)
+	realtimeController := controllers.NewRealtimeController(realtimeService)
 
 	commentRepository := repositories.NewCommentRepository()
-	commentService := services.NewCommentService(commentRepository, db, validate)
+	commentService := services.NewCommentService(commentRepository, articleRepository, moderationRepository, contentFilter, mentionTracker, notifier, hub, webhookDispatcher, db, validate)
 	commentController := controllers.NewCommentController(commentService)
 
+	moderationService := services.NewModerationService(moderationRepository, articleRepository, commentRepository, userRepository, articleService, commentService, mentionTracker, notifier, db, validate)
+	moderationController := controllers.NewModerationController(moderationService)
+
 	app.Use(recover.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:5173",
@@ -82,8 +140,24 @@ func main() {
 	routes.SetupUserProfilePhotoRoutes(app, userProfilePhotoController)
 	routes.SetupAuthRoutes(app, authController)
 	routes.SetupArticlePhotoRoutes(app, articleController)
+	routes.SetupArticleAnalyticsRoutes(app, articleAnalyticsController)
 	routes.SetupLikeRoutes(app, likeController)
+	routes.SetupFollowRoutes(app, followController)
+	routes.SetupBlockRoutes(app, blockController)
+	routes.SetupNotificationRoutes(app, notificationController)
+	routes.SetupRealtimeRoutes(app, realtimeController)
+	routes.SetupWebhookRoutes(app, webhookController)
 	routes.SetupCommentRoutes(app, commentController)
+	routes.SetupModerationRoutes(app, moderationController)
+	routes.SetupBookmarkRoutes(app, bookmarkController)
+	routes.SetupReadingListRoutes(app, readingListController)
+	routes.SetupTagRoutes(app, tagController)
+	routes.SetupCategoryRoutes(app, categoryController)
+	routes.SetupFeedRoutes(app, feedController)
+
+	go services.RunArticleAnalyticsRollup(articleAnalyticsService, config.AnalyticsRollupInterval)
+	go services.RunPeriodically("article score refresh", config.ArticleScoreRefreshInterval, articleService.RefreshScores)
+	go services.RunPeriodically("webhook delivery", config.WebhookPollInterval, webhookService.DeliverDue)
 
 	go func() {
 		if err := app.Listen(":3000"); err != nil {
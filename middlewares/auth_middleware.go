@@ -1,20 +1,31 @@
 package middlewares
 
 import (
+	"context"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"uaspw2/config"
 	"uaspw2/helper"
 	"uaspw2/models/web/response"
 )
 
+// IsSuspended reports whether a user is suspended. Tokens outlive a
+// suspension, so it is checked on every authenticated request; main sets it.
+var IsSuspended = func(ctx context.Context, userId int) bool {
+	return false
+}
+
 func AuthRequired(c *fiber.Ctx) error {
-	_, err := helper.VerifyToken(c, jwt.MapClaims{})
+	userClaims := &config.UserClaims{}
+	token, err := helper.VerifyToken(c, userClaims)
 
 	if err != nil {
 		return helper.HandleTokenError(c)
 	}
 
+	if claims, ok := token.(*config.UserClaims); ok && IsSuspended(c.Context(), claims.Id) {
+		return handleSuspended(c)
+	}
+
 	return c.Next()
 }
 
@@ -27,6 +38,9 @@ func AdminOnly(c *fiber.Ctx) error {
 	}
 
 	if claims, ok := token.(*config.UserClaims); ok && claims.Role == "admin" {
+		if IsSuspended(c.Context(), claims.Id) {
+			return handleSuspended(c)
+		}
 		return c.Next()
 	}
 
@@ -42,6 +56,9 @@ func UserOnly(c *fiber.Ctx) error {
 	}
 
 	if claims, ok := token.(*config.UserClaims); ok && claims.Role == "user" {
+		if IsSuspended(c.Context(), claims.Id) {
+			return handleSuspended(c)
+		}
 		return c.Next()
 	}
 	return helper.HandleTokenError(c)
@@ -58,3 +75,12 @@ func GuestOnly(c *fiber.Ctx) error {
 	}
 	return c.Next()
 }
+
+func handleSuspended(c *fiber.Ctx) error {
+	errorResponse := response.ErrorResponse{
+		Code:    fiber.StatusForbidden,
+		Message: "FORBIDDEN",
+		Error:   "your account is suspended",
+	}
+	return c.Status(fiber.StatusForbidden).JSON(errorResponse)
+}
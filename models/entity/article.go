@@ -1,14 +1,21 @@
 package entity
 
 type Article struct {
-	Id          int            `json:"id"`
-	UserId      int            `json:"user_id"`
-	Title       string         `json:"title"`
-	Description string         `json:"description"`
-	Content     string         `json:"content"`
-	Author      string         `json:"author"`
-	IsPublished bool           `json:"is_published"`
-	Media       []ArticleMedia `json:"media"`
-	CreatedAt   string         `json:"created_at"`
-	UpdatedAt   string         `json:"updated_at"`
+	Id           int            `json:"id"`
+	UserId       int            `json:"user_id"`
+	Title        string         `json:"title"`
+	Slug         string         `json:"slug"`
+	Description  string         `json:"description"`
+	Content      string         `json:"content"`
+	Author       string         `json:"author"`
+	IsPublished  bool           `json:"is_published"`
+	Version      int            `json:"version"`
+	Media        []ArticleMedia `json:"media"`
+	Tags         []Tag          `json:"tags"`
+	Categories   []Category     `json:"categories"`
+	Mentions     []Mention      `json:"mentions"`
+	Reactions    map[string]int `json:"reactions"`
+	CommentCount int            `json:"comment_count"`
+	CreatedAt    string         `json:"created_at"`
+	UpdatedAt    string         `json:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package entity
+
+type ArticleView struct {
+	Id        int    `json:"id"`
+	ArticleId int    `json:"article_id"`
+	UserId    int    `json:"user_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ArticleStats struct {
+	ArticleId     int    `json:"article_id"`
+	Title         string `json:"title"`
+	Slug          string `json:"slug"`
+	Views         int    `json:"views"`
+	UniqueReaders int    `json:"unique_readers"`
+	Likes         int    `json:"likes"`
+	Comments      int    `json:"comments"`
+}
+
+type ArticleStatsDaily struct {
+	ArticleId     int    `json:"article_id"`
+	Day           string `json:"day"`
+	Views         int    `json:"views"`
+	UniqueReaders int    `json:"unique_readers"`
+	Likes         int    `json:"likes"`
+	Comments      int    `json:"comments"`
+}
@@ -0,0 +1,7 @@
+package entity
+
+type Bookmark struct {
+	UserId    int    `json:"user_id"`
+	ArticleId int    `json:"article_id"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+type Category struct {
+	Id           int    `json:"id"`
+	ParentId     int    `json:"parent_id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	ArticleCount int    `json:"article_count"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
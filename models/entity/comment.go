@@ -1,11 +1,18 @@
 package entity
 
 type Comment struct {
-	Id        int    `json:"id"`
-	UserId    int    `json:"user_id"`
-	ArticleId int    `json:"article_id"`
-	Comment   string `json:"comment"`
-	Author    string `json:"author"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Id         int       `json:"id"`
+	UserId     int       `json:"user_id"`
+	ArticleId  int       `json:"article_id"`
+	ParentId   int       `json:"parent_id"`
+	Depth      int       `json:"depth"`
+	Comment    string    `json:"comment"`
+	Author     string    `json:"author"`
+	ReplyCount int       `json:"reply_count"`
+	IsHidden   bool      `json:"is_hidden"`
+	IsDeleted  bool      `json:"is_deleted"`
+	CreatedAt  string    `json:"created_at"`
+	UpdatedAt  string    `json:"updated_at"`
+	EditedAt   string    `json:"edited_at"`
+	Mentions   []Mention `json:"mentions"`
 }
@@ -0,0 +1,8 @@
+package entity
+
+type CommentRevision struct {
+	Id        int    `json:"id"`
+	CommentId int    `json:"comment_id"`
+	Comment   string `json:"comment"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+type Follow struct {
+	FollowerId int    `json:"follower_id"`
+	FolloweeId int    `json:"followee_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// FollowUser is one entry of a follower or following list.
+type FollowUser struct {
+	UserId     int    `json:"user_id"`
+	Username   string `json:"username"`
+	FullName   string `json:"full_name"`
+	FollowedAt string `json:"followed_at"`
+}
@@ -1,9 +1,26 @@
 package entity
 
 type Like struct {
-	Id        int    `json:"id"`
-	UserId    int    `json:"user_id"`
-	ArticleId int    `json:"article_id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Id        int          `json:"id"`
+	UserId    int          `json:"user_id"`
+	ArticleId int          `json:"article_id"`
+	Reaction  string       `json:"reaction"`
+	User      *LikeUser    `json:"user,omitempty"`
+	Article   *LikeArticle `json:"article,omitempty"`
+	CreatedAt string       `json:"created_at"`
+	UpdatedAt string       `json:"updated_at"`
+}
+
+// LikeUser is the user behind a like in the likes of an article.
+type LikeUser struct {
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	PhotoPath string `json:"photo_path"`
+}
+
+// LikeArticle is the liked article in the likes of a user.
+type LikeArticle struct {
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Author string `json:"author"`
 }
@@ -0,0 +1,8 @@
+package entity
+
+type Mention struct {
+	TargetType string `json:"target_type"`
+	TargetId   int    `json:"target_id"`
+	UserId     int    `json:"user_id"`
+	Username   string `json:"username"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+type Notification struct {
+	Id         int    `json:"id"`
+	UserId     int    `json:"user_id"`
+	ActorId    int    `json:"actor_id"`
+	Actor      string `json:"actor"`
+	Type       string `json:"type"`
+	TargetType string `json:"target_type"`
+	TargetId   int    `json:"target_id"`
+	ArticleId  int    `json:"article_id"`
+	ReadAt     string `json:"read_at"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserId  int    `json:"user_id"`
+	Type    string `json:"type"`
+	IsMuted bool   `json:"is_muted"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+type ReadingList struct {
+	Id          int               `json:"id"`
+	UserId      int               `json:"user_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	IsPublic    bool              `json:"is_public"`
+	ItemCount   int               `json:"item_count"`
+	Items       []ReadingListItem `json:"items"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+type ReadingListItem struct {
+	ReadingListId int     `json:"reading_list_id"`
+	Position      int     `json:"position"`
+	Note          string  `json:"note"`
+	Article       Article `json:"article"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
@@ -10,11 +10,12 @@ type User struct {
 }
 
 type UserWithProfile struct {
-	Id        int         `json:"id"`
-	Username  string      `json:"username"`
-	Profile   UserProfile `json:"profile"`
-	Password  string      `json:"password"`
-	Role      string      `json:"role"`
-	CreatedAt string      `json:"created_at"`
-	UpdatedAt string      `json:"updated_at"`
+	Id          int         `json:"id"`
+	Username    string      `json:"username"`
+	Profile     UserProfile `json:"profile"`
+	Password    string      `json:"password"`
+	Role        string      `json:"role"`
+	IsSuspended bool        `json:"is_suspended"`
+	CreatedAt   string      `json:"created_at"`
+	UpdatedAt   string      `json:"updated_at"`
 }
@@ -1,8 +1,20 @@
 package entity
 
 type UserProfilePhoto struct {
-	UserId    int    `json:"user_id"`
-	Path      string `json:"path"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	UserId    int                       `json:"user_id"`
+	Path      string                    `json:"path"`
+	Variants  []UserProfilePhotoVariant `json:"variants"`
+	CreatedAt string                    `json:"created_at"`
+	UpdatedAt string                    `json:"updated_at"`
+}
+
+type UserProfilePhotoVariant struct {
+	UserId      int    `json:"user_id"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
 }
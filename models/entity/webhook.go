@@ -0,0 +1,36 @@
+package entity
+
+type Webhook struct {
+	Id        int      `json:"id"`
+	Url       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Events    []string `json:"events"`
+	IsActive  bool     `json:"is_active"`
+	CreatedBy int      `json:"created_by"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	Id            int                      `json:"id"`
+	WebhookId     int                      `json:"webhook_id"`
+	Event         string                   `json:"event"`
+	Payload       string                   `json:"payload"`
+	Status        string                   `json:"status"`
+	AttemptCount  int                      `json:"attempt_count"`
+	ResponseCode  int                      `json:"response_code"`
+	NextAttemptAt string                   `json:"next_attempt_at"`
+	LastAttemptAt string                   `json:"last_attempt_at"`
+	CreatedAt     string                   `json:"created_at"`
+	Attempts      []WebhookDeliveryAttempt `json:"attempts"`
+}
+
+type WebhookDeliveryAttempt struct {
+	Id           int    `json:"id"`
+	DeliveryId   int    `json:"delivery_id"`
+	ResponseCode int    `json:"response_code"`
+	ResponseBody string `json:"response_body"`
+	Error        string `json:"error"`
+	DurationMs   int    `json:"duration_ms"`
+	CreatedAt    string `json:"created_at"`
+}
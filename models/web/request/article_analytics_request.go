@@ -0,0 +1,16 @@
+package request
+
+type ArticleViewCreateRequest struct {
+	ArticleId int `json:"article_id" validate:"required,numeric"`
+	AuthorId  int `json:"author_id" validate:"required,numeric"`
+	UserId    int `json:"user_id" validate:"required,numeric"`
+}
+
+// ArticleAnalyticsRequest selects the articles of UserId, optionally a single
+// one, over the days From to To inclusive. Both default to the last 30 days.
+type ArticleAnalyticsRequest struct {
+	ArticleId int    `json:"article_id" validate:"omitempty,numeric"`
+	UserId    int    `json:"user_id" validate:"required,numeric"`
+	From      string `json:"from" validate:"omitempty,datetime=2006-01-02"`
+	To        string `json:"to" validate:"omitempty,datetime=2006-01-02"`
+}
@@ -1,6 +1,9 @@
 package request
 
+import "mime/multipart"
+
 type ArticleMediaCreateRequest struct {
-	Type string `json:"type" validate:"required"`
-	Path string `json:"path" validate:"required"`
+	ArticleId int                   `json:"article_id" validate:"required,numeric"`
+	UserId    int                   `json:"user_id" validate:"required,numeric"`
+	File      *multipart.FileHeader `json:"-" validate:"required"`
 }
@@ -1,19 +1,57 @@
 package request
 
+import "mime/multipart"
+
 type ArticleCreateRequest struct {
-	UserId      int                         `json:"user_id" validate:"required,numeric"`
-	Title       string                      `json:"title" validate:"required,max=255"`
-	Description string                      `json:"description"`
-	Content     string                      `json:"content"`
-	IsPublished bool                        `json:"is_published" validate:"boolean"`
-	Media       []ArticleMediaCreateRequest `json:"media"`
+	UserId      int      `json:"user_id" validate:"required,numeric"`
+	Title       string   `json:"title" validate:"required,max=255"`
+	Description string   `json:"description"`
+	Content     string   `json:"content"`
+	IsPublished bool     `json:"is_published" validate:"boolean"`
+	Tags        []string `json:"tags" validate:"omitempty,max=10,dive,required,max=64"`
+	CategoryIds []int    `json:"category_ids" validate:"omitempty,max=5,dive,required,numeric"`
 }
 
 type ArticleUpdateRequest struct {
-	Id          int    `json:"id" validate:"required,numeric"`
-	UserId      int    `json:"user_id" validate:"required,numeric"`
-	Title       string `json:"title" validate:"required,max=255"`
-	Content     string `json:"content" validate:"required"`
-	Description string `json:"description"`
-	IsPublished bool   `json:"is_published" validate:"boolean"`
+	Id          int      `json:"id" validate:"required,numeric"`
+	UserId      int      `json:"user_id" validate:"required,numeric"`
+	Title       string   `json:"title" validate:"required,max=255"`
+	Content     string   `json:"content" validate:"required"`
+	Description string   `json:"description"`
+	IsPublished bool     `json:"is_published" validate:"boolean"`
+	Tags        []string `json:"tags" validate:"omitempty,max=10,dive,required,max=64"`
+	CategoryIds []int    `json:"category_ids" validate:"omitempty,max=5,dive,required,numeric"`
+	Version     int      `json:"-"`
+}
+
+type ArticlePatchRequest struct {
+	Id      int                     `json:"id" validate:"required,numeric"`
+	UserId  int                     `json:"user_id" validate:"required,numeric"`
+	Patch   []byte                  `json:"-" validate:"required"`
+	Media   []*multipart.FileHeader `json:"-" validate:"max=10"`
+	Version int                     `json:"-"`
+}
+
+// ArticleDocument is the editable part of an article that a merge patch is
+// applied to. Arrays are replaced as a whole, as RFC 7396 requires.
+type ArticleDocument struct {
+	Title       string   `json:"title" validate:"required,max=255"`
+	Description string   `json:"description"`
+	Content     string   `json:"content"`
+	Tags        []string `json:"tags" validate:"omitempty,max=10,dive,required,max=64"`
+	CategoryIds []int    `json:"category_ids" validate:"omitempty,max=5,dive,required,numeric"`
+	MediaIds    []int    `json:"media_ids" validate:"omitempty,dive,required,numeric"`
+}
+
+type ArticleFeedRequest struct {
+	Sort    string `json:"sort" validate:"required,oneof=trending top new"`
+	Period  string `json:"period" validate:"required,oneof=week month"`
+	Page    int    `json:"page" validate:"required,min=1"`
+	PerPage int    `json:"per_page" validate:"required,min=1,max=50"`
+}
+
+type TimelineRequest struct {
+	UserId int    `json:"user_id" validate:"required,numeric"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit" validate:"required,min=1,max=50"`
 }
@@ -0,0 +1,6 @@
+package request
+
+type BlockRequest struct {
+	BlockerId int `json:"blocker_id" validate:"required,numeric"`
+	BlockedId int `json:"blocked_id" validate:"required,numeric"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type BookmarkRequest struct {
+	UserId    int `json:"user_id" validate:"required,numeric"`
+	ArticleId int `json:"article_id" validate:"required,numeric"`
+}
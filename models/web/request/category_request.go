@@ -0,0 +1,18 @@
+package request
+
+type CategoryCreateRequest struct {
+	ParentId int    `json:"parent_id" validate:"omitempty,numeric"`
+	Name     string `json:"name" validate:"required,max=128"`
+}
+
+type CategoryUpdateRequest struct {
+	Id       int    `json:"id" validate:"required,numeric"`
+	ParentId int    `json:"parent_id" validate:"omitempty,numeric"`
+	Name     string `json:"name" validate:"required,max=128"`
+}
+
+type CategoryArticlesRequest struct {
+	CategoryId int    `json:"category_id" validate:"required,numeric"`
+	Cursor     string `json:"cursor"`
+	Limit      int    `json:"limit" validate:"required,min=1,max=50"`
+}
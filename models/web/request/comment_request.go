@@ -3,5 +3,12 @@ package request
 type CommentRequest struct {
 	UserId    int    `json:"user_id" validate:"required,numeric"`
 	ArticleId int    `json:"article_id" validate:"required,numeric"`
+	ParentId  int    `json:"parent_id" validate:"omitempty,numeric"`
 	Comment   string `json:"comment" validate:"required"`
 }
+
+type CommentUpdateRequest struct {
+	Id      int    `json:"id" validate:"required,numeric"`
+	UserId  int    `json:"user_id" validate:"required,numeric"`
+	Comment string `json:"comment" validate:"required"`
+}
@@ -0,0 +1,8 @@
+package request
+
+// ArticleFeedSourceRequest selects the published articles of a feed: all of
+// them, or those of one author or tag.
+type ArticleFeedSourceRequest struct {
+	UserId  int    `json:"user_id" validate:"omitempty,numeric"`
+	TagSlug string `json:"tag_slug" validate:"omitempty,max=64"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type FollowRequest struct {
+	FollowerId int `json:"follower_id" validate:"required,numeric"`
+	FolloweeId int `json:"followee_id" validate:"required,numeric"`
+}
@@ -4,3 +4,18 @@ type LikeRequest struct {
 	UserId    int `json:"user_id" validate:"required,numeric"`
 	ArticleId int `json:"article_id" validate:"required,numeric"`
 }
+
+type ReactionRequest struct {
+	UserId    int    `json:"user_id" validate:"required,numeric"`
+	ArticleId int    `json:"article_id" validate:"required,numeric"`
+	Reaction  string `json:"reaction" validate:"required,oneof=like love laugh insightful confused"`
+}
+
+// LikeListRequest pages through the likes of an article, when ArticleId is
+// set, or of a user.
+type LikeListRequest struct {
+	ArticleId int    `json:"article_id"`
+	UserId    int    `json:"user_id"`
+	Cursor    string `json:"cursor"`
+	Limit     int    `json:"limit" validate:"required,min=1,max=50"`
+}
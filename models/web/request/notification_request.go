@@ -0,0 +1,20 @@
+package request
+
+type NotificationListRequest struct {
+	UserId     int    `json:"user_id" validate:"required,numeric"`
+	UnreadOnly bool   `json:"unread_only"`
+	Cursor     string `json:"cursor"`
+	Limit      int    `json:"limit" validate:"required,min=1,max=50"`
+}
+
+type NotificationReadRequest struct {
+	Id     int  `json:"id" validate:"required,numeric"`
+	UserId int  `json:"user_id" validate:"required,numeric"`
+	IsRead bool `json:"is_read"`
+}
+
+type NotificationPreferenceRequest struct {
+	UserId  int    `json:"user_id" validate:"required,numeric"`
+	Type    string `json:"type" validate:"required,oneof=mention like reaction comment reply follow article_published article_unpublished new_article"`
+	IsMuted bool   `json:"is_muted"`
+}
@@ -0,0 +1,30 @@
+package request
+
+type ReadingListCreateRequest struct {
+	UserId      int    `json:"user_id" validate:"required,numeric"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public" validate:"boolean"`
+}
+
+type ReadingListUpdateRequest struct {
+	Id          int    `json:"id" validate:"required,numeric"`
+	UserId      int    `json:"user_id" validate:"required,numeric"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public" validate:"boolean"`
+}
+
+type ReadingListItemRequest struct {
+	ReadingListId int    `json:"reading_list_id" validate:"required,numeric"`
+	UserId        int    `json:"user_id" validate:"required,numeric"`
+	ArticleId     int    `json:"article_id" validate:"required,numeric"`
+	Note          string `json:"note" validate:"max=2000"`
+}
+
+// ReadingListReorderRequest lists every article of the reading list in its new order.
+type ReadingListReorderRequest struct {
+	ReadingListId int   `json:"reading_list_id" validate:"required,numeric"`
+	UserId        int   `json:"user_id" validate:"required,numeric"`
+	ArticleIds    []int `json:"article_ids" validate:"required,dive,required,numeric"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type RealtimeSubscribeRequest struct {
+	UserId     int   `json:"user_id" validate:"required,numeric"`
+	ArticleIds []int `json:"article_ids" validate:"dive,min=1"`
+}
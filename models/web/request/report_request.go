@@ -0,0 +1,18 @@
+package request
+
+type ReportCreateRequest struct {
+	ReporterId int    `json:"reporter_id" validate:"required,numeric"`
+	TargetType string `json:"target_type" validate:"required,oneof=article comment"`
+	TargetId   int    `json:"target_id" validate:"required,numeric"`
+	Reason     string `json:"reason" validate:"required,oneof=spam harassment hate_speech misinformation plagiarism other"`
+	Details    string `json:"details" validate:"max=1000"`
+}
+
+type ModerationActionRequest struct {
+	ModeratorId int    `json:"moderator_id" validate:"required,numeric"`
+	TargetType  string `json:"target_type" validate:"required,oneof=article comment"`
+	TargetId    int    `json:"target_id" validate:"required,numeric"`
+	Action      string `json:"action" validate:"required,oneof=dismiss hide delete warn suspend"`
+	Note        string `json:"note" validate:"max=1000"`
+	SuspendDays int    `json:"suspend_days" validate:"required_if=Action suspend,omitempty,min=1,max=365"`
+}
@@ -0,0 +1,17 @@
+package request
+
+type TagUpdateRequest struct {
+	Id   int    `json:"id" validate:"required,numeric"`
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+type TagMergeRequest struct {
+	SourceId int `json:"source_id" validate:"required,numeric"`
+	TargetId int `json:"target_id" validate:"required,numeric,nefield=SourceId"`
+}
+
+type TagArticlesRequest struct {
+	Slug   string `json:"slug" validate:"required"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit" validate:"required,min=1,max=50"`
+}
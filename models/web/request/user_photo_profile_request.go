@@ -1,6 +1,8 @@
 package request
 
+import "mime/multipart"
+
 type UserProfilePhotoRequest struct {
-	UserId int    `json:"user_id" validate:"required,numeric"`
-	Path   string `json:"path" validate:"required"`
+	UserId int                   `json:"user_id" validate:"required,numeric"`
+	File   *multipart.FileHeader `json:"-" validate:"required"`
 }
@@ -7,4 +7,5 @@ type UserProfileUpdateRequest struct {
 	BirthDate   string `json:"birth_date" validate:"required"`
 	PhoneNumber string `json:"phone_number" validate:"required,e164"`
 	Address     string `json:"address" validate:"required"`
+	Version     int    `json:"-"`
 }
@@ -0,0 +1,18 @@
+package request
+
+type WebhookCreateRequest struct {
+	Url       string   `json:"url" validate:"required,http_url,max=2048"`
+	Events    []string `json:"events" validate:"required,min=1,unique,dive,oneof=article.published article.deleted user.registered comment.created"`
+	Secret    string   `json:"secret" validate:"omitempty,min=16,max=64"`
+	CreatedBy int      `json:"created_by" validate:"required,numeric"`
+}
+
+// WebhookUpdateRequest replaces the settings of a webhook. The secret is only
+// changed when a new one is given.
+type WebhookUpdateRequest struct {
+	Id       int      `json:"id" validate:"required,numeric"`
+	Url      string   `json:"url" validate:"required,http_url,max=2048"`
+	Events   []string `json:"events" validate:"required,min=1,unique,dive,oneof=article.published article.deleted user.registered comment.created"`
+	Secret   string   `json:"secret" validate:"omitempty,min=16,max=64"`
+	IsActive bool     `json:"is_active"`
+}
@@ -0,0 +1,22 @@
+package response
+
+type ArticleAnalyticsResponse struct {
+	ArticleId     int                         `json:"article_id"`
+	Title         string                      `json:"title"`
+	Slug          string                      `json:"slug"`
+	From          string                      `json:"from"`
+	To            string                      `json:"to"`
+	Views         int                         `json:"views"`
+	UniqueReaders int                         `json:"unique_readers"`
+	Likes         int                         `json:"likes"`
+	Comments      int                         `json:"comments"`
+	Daily         []ArticleStatsDailyResponse `json:"daily,omitempty"`
+}
+
+type ArticleStatsDailyResponse struct {
+	Day           string `json:"day"`
+	Views         int    `json:"views"`
+	UniqueReaders int    `json:"unique_readers"`
+	Likes         int    `json:"likes"`
+	Comments      int    `json:"comments"`
+}
@@ -5,6 +5,7 @@ type ArticleMediaResponse struct {
 	ArticleId int    `json:"article_id"`
 	Type      string `json:"type"`
 	Path      string `json:"path"`
+	Url       string `json:"url"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
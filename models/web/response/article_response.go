@@ -1,16 +1,48 @@
 package response
 
-import "uaspw2/models/entity"
-
 type ArticleResponse struct {
-	Id          int                   `json:"id"`
-	UserId      int                   `json:"user_id"`
-	Title       string                `json:"title"`
-	Description string                `json:"description"`
-	Content     string                `json:"content"`
-	Author      string                `json:"author"`
-	IsPublished bool                  `json:"is_published"`
-	Media       []entity.ArticleMedia `json:"media"`
-	CreatedAt   string                `json:"created_at"`
-	UpdatedAt   string                `json:"updated_at"`
+	Id              int                    `json:"id"`
+	UserId          int                    `json:"user_id"`
+	Title           string                 `json:"title"`
+	Slug            string                 `json:"slug"`
+	Description     string                 `json:"description"`
+	Content         string                 `json:"content"`
+	ContentHtml     string                 `json:"content_html"`
+	TableOfContents []TableOfContentsEntry `json:"table_of_contents"`
+	WordCount       int                    `json:"word_count"`
+	ReadingTime     int                    `json:"reading_time_minutes"`
+	Author          string                 `json:"author"`
+	IsPublished     bool                   `json:"is_published"`
+	IsBookmarked    bool                   `json:"is_bookmarked"`
+	LikedByMe       bool                   `json:"liked_by_me"`
+	LikeCount       int                    `json:"like_count"`
+	CommentCount    int                    `json:"comment_count"`
+	Version         int                    `json:"version"`
+	Media           []ArticleMediaResponse `json:"media"`
+	Tags            []TagResponse          `json:"tags"`
+	Categories      []CategoryResponse     `json:"categories"`
+	Mentions        []MentionResponse      `json:"mentions"`
+	Reactions       map[string]int         `json:"reactions"`
+	CreatedAt       string                 `json:"created_at"`
+	UpdatedAt       string                 `json:"updated_at"`
+}
+
+type TableOfContentsEntry struct {
+	Level  int    `json:"level"`
+	Text   string `json:"text"`
+	Anchor string `json:"anchor"`
+}
+
+type ArticleFeedResponse struct {
+	Sort     string            `json:"sort"`
+	Period   string            `json:"period,omitempty"`
+	Page     int               `json:"page"`
+	PerPage  int               `json:"per_page"`
+	HasMore  bool              `json:"has_more"`
+	Articles []ArticleResponse `json:"articles"`
+}
+
+type TimelineResponse struct {
+	Articles   []ArticleResponse `json:"articles"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,14 @@
+package response
+
+type BlockResponse struct {
+	BlockerId int    `json:"blocker_id"`
+	BlockedId int    `json:"blocked_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type BlockedUserResponse struct {
+	UserId    int    `json:"user_id"`
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	BlockedAt string `json:"blocked_at"`
+}
@@ -0,0 +1,18 @@
+package response
+
+type CategoryResponse struct {
+	Id           int                `json:"id"`
+	ParentId     int                `json:"parent_id"`
+	Name         string             `json:"name"`
+	Slug         string             `json:"slug"`
+	ArticleCount int                `json:"article_count"`
+	Children     []CategoryResponse `json:"children,omitempty"`
+	CreatedAt    string             `json:"created_at"`
+	UpdatedAt    string             `json:"updated_at"`
+}
+
+type CategoryArticlesResponse struct {
+	Category   CategoryResponse  `json:"category"`
+	Articles   []ArticleResponse `json:"articles"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
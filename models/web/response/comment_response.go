@@ -1,11 +1,21 @@
 package response
 
 type CommentResponse struct {
-	Id        int    `json:"id"`
-	UserId    int    `json:"user_id"`
-	ArticleId int    `json:"article_id"`
-	Author    string `json:"author"`
-	Comment   string `json:"comment"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Id          int               `json:"id"`
+	UserId      int               `json:"user_id"`
+	ArticleId   int               `json:"article_id"`
+	ParentId    int               `json:"parent_id"`
+	Depth       int               `json:"depth"`
+	Author      string            `json:"author"`
+	Comment     string            `json:"comment"`
+	CommentHtml string            `json:"comment_html"`
+	Mentions    []MentionResponse `json:"mentions"`
+	ReplyCount  int               `json:"reply_count"`
+	IsHidden    bool              `json:"is_hidden"`
+	IsDeleted   bool              `json:"is_deleted"`
+	IsEdited    bool              `json:"is_edited"`
+	Replies     []CommentResponse `json:"replies,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+	EditedAt    string            `json:"edited_at"`
 }
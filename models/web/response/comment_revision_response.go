@@ -0,0 +1,8 @@
+package response
+
+type CommentRevisionResponse struct {
+	Id        int    `json:"id"`
+	CommentId int    `json:"comment_id"`
+	Comment   string `json:"comment"`
+	CreatedAt string `json:"created_at"`
+}
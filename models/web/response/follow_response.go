@@ -0,0 +1,14 @@
+package response
+
+type FollowResponse struct {
+	FollowerId int    `json:"follower_id"`
+	FolloweeId int    `json:"followee_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type FollowUserResponse struct {
+	UserId     int    `json:"user_id"`
+	Username   string `json:"username"`
+	FullName   string `json:"full_name"`
+	FollowedAt string `json:"followed_at"`
+}
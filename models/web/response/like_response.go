@@ -1,9 +1,50 @@
 package response
 
 type LikeResponse struct {
-	Id        int    `json:"id"`
-	UserId    int    `json:"user_id"`
-	ArticleId int    `json:"article_id"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Id        int                  `json:"id"`
+	UserId    int                  `json:"user_id"`
+	ArticleId int                  `json:"article_id"`
+	Reaction  string               `json:"reaction"`
+	User      *LikeUserResponse    `json:"user,omitempty"`
+	Article   *LikeArticleResponse `json:"article,omitempty"`
+	CreatedAt string               `json:"created_at"`
+	UpdatedAt string               `json:"updated_at"`
+}
+
+type LikeUserResponse struct {
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	PhotoPath string `json:"photo_path"`
+	PhotoUrl  string `json:"photo_url"`
+}
+
+type LikeArticleResponse struct {
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Author string `json:"author"`
+}
+
+type LikeListResponse struct {
+	Likes      []LikeResponse `json:"likes"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// LikeCountResponse is pushed to the clients following an article whenever
+// its likes change.
+type LikeCountResponse struct {
+	ArticleId int `json:"article_id"`
+	LikeCount int `json:"like_count"`
+}
+
+// ReactionCountResponse is pushed to the clients following an article
+// whenever any of its reactions change.
+type ReactionCountResponse struct {
+	ArticleId int            `json:"article_id"`
+	Reactions map[string]int `json:"reactions"`
+}
+
+type ArticleReactionsResponse struct {
+	ArticleId int            `json:"article_id"`
+	Reactions map[string]int `json:"reactions"`
+	Mine      []string       `json:"mine"`
 }
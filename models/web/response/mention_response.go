@@ -0,0 +1,6 @@
+package response
+
+type MentionResponse struct {
+	UserId   int    `json:"user_id"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,29 @@
+package response
+
+type NotificationResponse struct {
+	Id         int    `json:"id"`
+	ActorId    int    `json:"actor_id"`
+	Actor      string `json:"actor"`
+	Type       string `json:"type"`
+	TargetType string `json:"target_type"`
+	TargetId   int    `json:"target_id"`
+	ArticleId  int    `json:"article_id"`
+	IsRead     bool   `json:"is_read"`
+	ReadAt     string `json:"read_at"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+}
+
+type NotificationUnreadCountResponse struct {
+	Total  int            `json:"total"`
+	ByType map[string]int `json:"by_type"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type    string `json:"type"`
+	IsMuted bool   `json:"is_muted"`
+}
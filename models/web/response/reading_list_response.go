@@ -0,0 +1,21 @@
+package response
+
+type ReadingListResponse struct {
+	Id          int                       `json:"id"`
+	UserId      int                       `json:"user_id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	IsPublic    bool                      `json:"is_public"`
+	ItemCount   int                       `json:"item_count"`
+	Items       []ReadingListItemResponse `json:"items,omitempty"`
+	CreatedAt   string                    `json:"created_at"`
+	UpdatedAt   string                    `json:"updated_at"`
+}
+
+type ReadingListItemResponse struct {
+	Position  int             `json:"position"`
+	Note      string          `json:"note"`
+	Article   ArticleResponse `json:"article"`
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package response
+
+type ReportResponse struct {
+	Id         int    `json:"id"`
+	ReporterId int    `json:"reporter_id"`
+	Reporter   string `json:"reporter"`
+	TargetType string `json:"target_type"`
+	TargetId   int    `json:"target_id"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type ReportGroupResponse struct {
+	TargetType      string           `json:"target_type"`
+	TargetId        int              `json:"target_id"`
+	ReportCount     int              `json:"report_count"`
+	Reasons         map[string]int   `json:"reasons"`
+	FirstReportedAt string           `json:"first_reported_at"`
+	LastReportedAt  string           `json:"last_reported_at"`
+	Reports         []ReportResponse `json:"reports"`
+}
+
+type ModerationActionResponse struct {
+	Id          int    `json:"id"`
+	ModeratorId int    `json:"moderator_id"`
+	TargetType  string `json:"target_type"`
+	TargetId    int    `json:"target_id"`
+	UserId      int    `json:"user_id"`
+	Action      string `json:"action"`
+	Note        string `json:"note"`
+	SuspendDays int    `json:"suspend_days"`
+	CreatedAt   string `json:"created_at"`
+}
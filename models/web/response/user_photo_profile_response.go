@@ -1,8 +1,19 @@
 package response
 
 type UserProfilePhotoResponse struct {
-	UserId    int    `json:"user_id"`
-	Path      string `json:"path"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	UserId    int                               `json:"user_id"`
+	Path      string                            `json:"path"`
+	Url       string                            `json:"url"`
+	Variants  []UserProfilePhotoVariantResponse `json:"variants"`
+	CreatedAt string                            `json:"created_at"`
+	UpdatedAt string                            `json:"updated_at"`
+}
+
+type UserProfilePhotoVariantResponse struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Url         string `json:"url"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type"`
 }
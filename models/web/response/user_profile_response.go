@@ -1,12 +1,15 @@
 package response
 
 type UserProfileResponse struct {
-	UserId      int    `json:"user_id"`
-	FullName    string `json:"full_name"`
-	Gender      string `json:"gender"`
-	BirthDate   string `json:"birth_date"`
-	PhoneNumber string `json:"phone_number"`
-	Address     string `json:"address"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	UserId         int    `json:"user_id"`
+	FullName       string `json:"full_name"`
+	Gender         string `json:"gender"`
+	BirthDate      string `json:"birth_date"`
+	PhoneNumber    string `json:"phone_number"`
+	Address        string `json:"address"`
+	Version        int    `json:"version"`
+	FollowersCount int    `json:"followers_count"`
+	FollowingCount int    `json:"following_count"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
 }
@@ -0,0 +1,45 @@
+package response
+
+import "encoding/json"
+
+// WebhookResponse only carries the secret right after it was set.
+type WebhookResponse struct {
+	Id        int      `json:"id"`
+	Url       string   `json:"url"`
+	Events    []string `json:"events"`
+	IsActive  bool     `json:"is_active"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedBy int      `json:"created_by"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id            int                              `json:"id"`
+	WebhookId     int                              `json:"webhook_id"`
+	Event         string                           `json:"event"`
+	Payload       json.RawMessage                  `json:"payload"`
+	Status        string                           `json:"status"`
+	AttemptCount  int                              `json:"attempt_count"`
+	ResponseCode  int                              `json:"response_code"`
+	NextAttemptAt string                           `json:"next_attempt_at"`
+	LastAttemptAt string                           `json:"last_attempt_at"`
+	CreatedAt     string                           `json:"created_at"`
+	Attempts      []WebhookDeliveryAttemptResponse `json:"attempts,omitempty"`
+}
+
+type WebhookDeliveryAttemptResponse struct {
+	Id           int    `json:"id"`
+	ResponseCode int    `json:"response_code"`
+	ResponseBody string `json:"response_body"`
+	Error        string `json:"error"`
+	DurationMs   int    `json:"duration_ms"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// WebhookPayload is the JSON body posted to webhook endpoints.
+type WebhookPayload struct {
+	Event      string `json:"event"`
+	OccurredAt string `json:"occurred_at"`
+	Data       any    `json:"data"`
+}
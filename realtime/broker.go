@@ -0,0 +1,52 @@
+package realtime
+
+import (
+	"context"
+	"uaspw2/config"
+)
+
+// Broker carries encoded events between publishers and hubs. Subscribe is
+// called once by NewHub; deliver must receive every event published through
+// any broker sharing the same backend, including this one.
+type Broker interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Subscribe(deliver func(topic string, payload []byte))
+	Close() error
+}
+
+func NewBroker() Broker {
+	switch config.RealtimeBroker {
+	case "redis":
+		return NewRedisBroker(RedisConfig{
+			Addr:     config.RealtimeRedisAddr,
+			Password: config.RealtimeRedisPassword,
+			Prefix:   config.RealtimeRedisPrefix,
+		})
+	default:
+		return NewLocalBroker()
+	}
+}
+
+// LocalBroker delivers events within this process only.
+type LocalBroker struct {
+	deliver func(topic string, payload []byte)
+}
+
+func NewLocalBroker() Broker {
+	return &LocalBroker{}
+}
+
+func (broker *LocalBroker) Publish(ctx context.Context, topic string, payload []byte) error {
+	if broker.deliver != nil {
+		broker.deliver(topic, payload)
+	}
+	return nil
+}
+
+func (broker *LocalBroker) Subscribe(deliver func(topic string, payload []byte)) {
+	broker.deliver = deliver
+}
+
+func (broker *LocalBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,183 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2/log"
+	"strconv"
+	"sync"
+)
+
+// ErrSlowConsumer closes a subscription whose client does not read its
+// events fast enough.
+var ErrSlowConsumer = errors.New("too many pending events, reconnect to resume")
+
+// Publisher is what services use to push events to connected clients.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, eventType string, data any)
+}
+
+// Message is one event delivered to a subscription.
+type Message struct {
+	Topic string          `json:"topic,omitempty"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+func ArticleTopic(articleId int) string {
+	return "article:" + strconv.Itoa(articleId)
+}
+
+func UserTopic(userId int) string {
+	return "user:" + strconv.Itoa(userId)
+}
+
+// Hub fans published events out to the subscriptions of their topic. Events
+// always travel through the Broker, so with a shared broker every replica
+// delivers them to its own clients.
+type Hub struct {
+	Broker     Broker
+	BufferSize int
+
+	mutex  sync.Mutex
+	topics map[string]map[*Subscription]bool
+}
+
+func NewHub(broker Broker, bufferSize int) *Hub {
+	hub := &Hub{
+		Broker:     broker,
+		BufferSize: bufferSize,
+		topics:     map[string]map[*Subscription]bool{},
+	}
+	broker.Subscribe(hub.deliver)
+	return hub
+}
+
+// Publish sends an event to every subscriber of topic. Failures are logged:
+// a lost push must not fail the request that caused it.
+func (hub *Hub) Publish(ctx context.Context, topic string, eventType string, data any) {
+	encoded, err := json.Marshal(data)
+	if err == nil {
+		var payload []byte
+		payload, err = json.Marshal(Message{Topic: topic, Type: eventType, Data: encoded})
+		if err == nil {
+			err = hub.Broker.Publish(ctx, topic, payload)
+		}
+	}
+	if err != nil {
+		log.Errorf("realtime: publish %s to %s: %v", eventType, topic, err)
+	}
+}
+
+func (hub *Hub) deliver(topic string, payload []byte) {
+	var message Message
+	if err := json.Unmarshal(payload, &message); err != nil {
+		log.Errorf("realtime: invalid event on %s: %v", topic, err)
+		return
+	}
+	message.Topic = topic
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	for subscription := range hub.topics[topic] {
+		select {
+		case subscription.messages <- message:
+		default:
+			hub.close(subscription, ErrSlowConsumer)
+		}
+	}
+}
+
+// Subscribe returns a subscription receiving the events of the given topics.
+func (hub *Hub) Subscribe(topics ...string) *Subscription {
+	subscription := &Subscription{
+		hub:      hub,
+		messages: make(chan Message, hub.BufferSize),
+		topics:   map[string]bool{},
+	}
+	subscription.Add(topics...)
+	return subscription
+}
+
+// close must be called with the mutex held.
+func (hub *Hub) close(subscription *Subscription, err error) {
+	if subscription.closed {
+		return
+	}
+	for topic := range subscription.topics {
+		hub.remove(subscription, topic)
+	}
+	subscription.closed = true
+	subscription.err = err
+	close(subscription.messages)
+}
+
+// remove must be called with the mutex held.
+func (hub *Hub) remove(subscription *Subscription, topic string) {
+	delete(subscription.topics, topic)
+	delete(hub.topics[topic], subscription)
+	if len(hub.topics[topic]) == 0 {
+		delete(hub.topics, topic)
+	}
+}
+
+// Subscription is one client's view of the hub. Its Messages channel is
+// closed by Close, or when the client falls BufferSize events behind.
+type Subscription struct {
+	hub      *Hub
+	messages chan Message
+	topics   map[string]bool
+	closed   bool
+	err      error
+}
+
+func (subscription *Subscription) Messages() <-chan Message {
+	return subscription.messages
+}
+
+// Err tells why the hub closed the subscription, or nil when the client
+// closed it.
+func (subscription *Subscription) Err() error {
+	subscription.hub.mutex.Lock()
+	defer subscription.hub.mutex.Unlock()
+	return subscription.err
+}
+
+func (subscription *Subscription) Add(topics ...string) {
+	hub := subscription.hub
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if subscription.closed {
+		return
+	}
+	for _, topic := range topics {
+		if hub.topics[topic] == nil {
+			hub.topics[topic] = map[*Subscription]bool{}
+		}
+		hub.topics[topic][subscription] = true
+		subscription.topics[topic] = true
+	}
+}
+
+func (subscription *Subscription) Remove(topics ...string) {
+	hub := subscription.hub
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	for _, topic := range topics {
+		hub.remove(subscription, topic)
+	}
+}
+
+// Count returns how many topics the subscription follows.
+func (subscription *Subscription) Count() int {
+	subscription.hub.mutex.Lock()
+	defer subscription.hub.mutex.Unlock()
+	return len(subscription.topics)
+}
+
+func (subscription *Subscription) Close() {
+	subscription.hub.mutex.Lock()
+	defer subscription.hub.mutex.Unlock()
+	subscription.hub.close(subscription, nil)
+}
@@ -0,0 +1,275 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2/log"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisConfig describes any server speaking the Redis protocol (Redis,
+// Valkey, KeyDB, ...). Every topic is published on the channel Prefix+topic.
+type RedisConfig struct {
+	Addr     string
+	Password string
+	Prefix   string
+}
+
+// RedisBroker fans events out to every replica through Redis pub/sub. It
+// keeps one connection for publishing and one for its pattern subscription,
+// and reconnects both when they fail. Publishing happens on a background
+// writer so a slow or unreachable server never holds up a request; events
+// that do not fit in the queue are dropped.
+type RedisBroker struct {
+	RedisConfig
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	queue     chan redisMessage
+	closeOnce sync.Once
+	closed    chan struct{}
+	stopped   chan struct{}
+}
+
+type redisMessage struct {
+	channel string
+	payload string
+}
+
+const (
+	redisRetryDelay = time.Second
+	redisQueueSize  = 1024
+)
+
+var errRedisQueueFull = errors.New("redis: publish queue is full")
+
+func NewRedisBroker(config RedisConfig) Broker {
+	broker := &RedisBroker{
+		RedisConfig:  config,
+		DialTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+		queue:        make(chan redisMessage, redisQueueSize),
+		closed:       make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	go broker.write()
+	return broker
+}
+
+// Publish queues the event for the writer and returns at once.
+func (broker *RedisBroker) Publish(ctx context.Context, topic string, payload []byte) error {
+	select {
+	case <-broker.closed:
+		return net.ErrClosed
+	default:
+	}
+
+	select {
+	case broker.queue <- redisMessage{channel: broker.Prefix + topic, payload: string(payload)}:
+		return nil
+	default:
+		return errRedisQueueFull
+	}
+}
+
+// write sends queued events until Close.
+func (broker *RedisBroker) write() {
+	defer close(broker.stopped)
+
+	var publisher *redisConn
+	defer func() {
+		if publisher != nil {
+			publisher.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-broker.closed:
+			return
+		case message := <-broker.queue:
+			publisher = broker.publish(publisher, message)
+		}
+	}
+}
+
+// publish sends one event and returns the connection to use for the next, nil
+// when it failed. A connection dropped since the last publish is only noticed
+// on use, so the event is retried once on a fresh one.
+func (broker *RedisBroker) publish(publisher *redisConn, message redisMessage) *redisConn {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if publisher == nil {
+			publisher, err = broker.dial(context.Background())
+			if err != nil {
+				break
+			}
+		}
+		publisher.conn.SetDeadline(time.Now().Add(broker.WriteTimeout))
+		_, err = publisher.do("PUBLISH", message.channel, message.payload)
+		if err == nil {
+			return publisher
+		}
+		publisher.conn.Close()
+		publisher = nil
+	}
+	log.Errorf("realtime: redis publish to %s: %v", message.channel, err)
+	return nil
+}
+
+// Subscribe listens on a background connection until Close.
+func (broker *RedisBroker) Subscribe(deliver func(topic string, payload []byte)) {
+	go func() {
+		for {
+			err := broker.listen(deliver)
+			select {
+			case <-broker.closed:
+				return
+			default:
+			}
+			log.Errorf("realtime: redis subscription lost, reconnecting: %v", err)
+			select {
+			case <-broker.closed:
+				return
+			case <-time.After(redisRetryDelay):
+			}
+		}
+	}()
+}
+
+func (broker *RedisBroker) listen(deliver func(topic string, payload []byte)) error {
+	subscriber, err := broker.dial(context.Background())
+	if err != nil {
+		return err
+	}
+	defer subscriber.conn.Close()
+
+	go func() {
+		select {
+		case <-broker.closed:
+			subscriber.conn.Close()
+		case <-subscriber.done:
+		}
+	}()
+	defer close(subscriber.done)
+
+	subscriber.conn.SetDeadline(time.Time{})
+	if _, err := subscriber.do("PSUBSCRIBE", broker.Prefix+"*"); err != nil {
+		return err
+	}
+	for {
+		reply, err := subscriber.read()
+		if err != nil {
+			return err
+		}
+		// [pmessage, pattern, channel, payload]
+		parts, ok := reply.([]any)
+		if !ok || len(parts) != 4 || parts[0] != "pmessage" {
+			continue
+		}
+		channel, _ := parts[2].(string)
+		payload, _ := parts[3].(string)
+		deliver(strings.TrimPrefix(channel, broker.Prefix), []byte(payload))
+	}
+}
+
+// Close stops the writer and the subscription. Events still queued are
+// dropped.
+func (broker *RedisBroker) Close() error {
+	broker.closeOnce.Do(func() {
+		close(broker.closed)
+	})
+	<-broker.stopped
+	return nil
+}
+
+func (broker *RedisBroker) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: broker.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", broker.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &redisConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		done:   make(chan struct{}),
+	}
+	if broker.Password != "" {
+		conn.SetDeadline(time.Now().Add(broker.DialTimeout))
+		if _, err := client.do("AUTH", broker.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// redisConn speaks just enough RESP for AUTH, PUBLISH and PSUBSCRIBE.
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	done   chan struct{}
+}
+
+func (client *redisConn) do(args ...string) (any, error) {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := client.conn.Write([]byte(command.String())); err != nil {
+		return nil, err
+	}
+	return client.read()
+}
+
+func (client *redisConn) read() (any, error) {
+	line, err := client.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, errors.New("redis: " + line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(client.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]any, count)
+		for i := range items {
+			if items[i], err = client.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, errors.New("redis: unexpected reply " + strconv.Quote(line))
+}
@@ -0,0 +1,279 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRedisRead(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  any
+		err   string
+	}{
+		{"simple string", "+OK\r\n", "OK", ""},
+		{"error", "-ERR unknown command\r\n", nil, "redis: ERR unknown command"},
+		{"integer", ":42\r\n", int64(42), ""},
+		{"negative integer", ":-3\r\n", int64(-3), ""},
+		{"bulk string", "$5\r\nhello\r\n", "hello", ""},
+		{"bulk string with CRLF", "$7\r\nab\r\ncde\r\n", "ab\r\ncde", ""},
+		{"empty bulk string", "$0\r\n\r\n", "", ""},
+		{"null bulk string", "$-1\r\n", nil, ""},
+		{"array", "*4\r\n$8\r\npmessage\r\n$3\r\np:*\r\n$5\r\np:a:1\r\n$2\r\n{}\r\n", []any{"pmessage", "p:*", "p:a:1", "{}"}, ""},
+		{"mixed array", "*3\r\n$10\r\npsubscribe\r\n$3\r\np:*\r\n:1\r\n", []any{"psubscribe", "p:*", int64(1)}, ""},
+		{"nested array", "*2\r\n*1\r\n:1\r\n+OK\r\n", []any{[]any{int64(1)}, "OK"}, ""},
+		{"empty array", "*0\r\n", []any{}, ""},
+		{"null array", "*-1\r\n", nil, ""},
+		{"empty line", "\r\n", nil, "redis: empty reply"},
+		{"unknown type", "?x\r\n", nil, `redis: unexpected reply "?x"`},
+		{"truncated bulk string", "$5\r\nhel", nil, "unexpected EOF"},
+		{"truncated array", "*2\r\n:1\r\n", nil, "EOF"},
+		{"bad integer", ":x\r\n", nil, "invalid syntax"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &redisConn{reader: bufio.NewReader(strings.NewReader(test.reply))}
+			got, err := client.read()
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("err = %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("reply = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedisDo(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	want := "*3\r\n$7\r\nPUBLISH\r\n$5\r\np:a:1\r\n$7\r\n{\"a\":1}\r\n"
+	command := make(chan string, 1)
+	go func() {
+		data := make([]byte, len(want))
+		n, _ := io.ReadFull(client, data)
+		command <- string(data[:n])
+		client.Write([]byte(":2\r\n"))
+	}()
+
+	conn := &redisConn{conn: server, reader: bufio.NewReader(server)}
+	reply, err := conn.do("PUBLISH", "p:a:1", `{"a":1}`)
+	if err != nil || reply != int64(2) {
+		t.Errorf("reply = %#v %v, want 2", reply, err)
+	}
+	if got := <-command; got != want {
+		t.Errorf("command = %q, want %q", got, want)
+	}
+}
+
+// fakeRedis serves AUTH, PUBLISH and PSUBSCRIBE well enough for the broker.
+// Patterns are ignored: every subscriber gets every message.
+type fakeRedis struct {
+	password string
+	// dropAfterPublish closes the connection after answering a PUBLISH
+	dropAfterPublish bool
+
+	listener    net.Listener
+	mutex       sync.Mutex
+	subscribers []net.Conn
+	subscribed  chan struct{}
+	published   chan string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeRedis{
+		password:   password,
+		listener:   listener,
+		subscribed: make(chan struct{}, 4),
+		published:  make(chan string, 16),
+	}
+	t.Cleanup(func() {
+		listener.Close()
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		for _, conn := range server.subscribers {
+			conn.Close()
+		}
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *fakeRedis) serve(conn net.Conn) {
+	client := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	authenticated := server.password == ""
+	for {
+		request, err := client.read()
+		if err != nil {
+			conn.Close()
+			return
+		}
+		args, _ := request.([]any)
+		if len(args) == 0 {
+			conn.Close()
+			return
+		}
+
+		server.mutex.Lock()
+		switch command := strings.ToUpper(args[0].(string)); {
+		case command == "AUTH":
+			if args[1] == server.password {
+				authenticated = true
+				conn.Write([]byte("+OK\r\n"))
+			} else {
+				conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+			}
+		case !authenticated:
+			conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+		case command == "PUBLISH":
+			channel, payload := args[1].(string), args[2].(string)
+			for _, subscriber := range server.subscribers {
+				fmt.Fprintf(subscriber, "*4\r\n$8\r\npmessage\r\n$1\r\n*\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(channel), channel, len(payload), payload)
+			}
+			fmt.Fprintf(conn, ":%d\r\n", len(server.subscribers))
+			server.published <- channel + " " + payload
+			if server.dropAfterPublish {
+				conn.Close()
+				server.mutex.Unlock()
+				return
+			}
+		case command == "PSUBSCRIBE":
+			pattern := args[1].(string)
+			server.subscribers = append(server.subscribers, conn)
+			fmt.Fprintf(conn, "*3\r\n$10\r\npsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(pattern), pattern)
+			server.subscribed <- struct{}{}
+		default:
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+		server.mutex.Unlock()
+	}
+}
+
+func receive[T any](t *testing.T, channel <-chan T, what string) T {
+	t.Helper()
+	select {
+	case value := <-channel:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+	var zero T
+	return zero
+}
+
+func TestRedisBrokerPublishSubscribe(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	broker := NewRedisBroker(RedisConfig{Addr: server.listener.Addr().String(), Password: "secret", Prefix: "test:"})
+	defer broker.Close()
+
+	delivered := make(chan string, 4)
+	broker.Subscribe(func(topic string, payload []byte) {
+		delivered <- topic + " " + string(payload)
+	})
+	receive(t, server.subscribed, "the subscription")
+
+	if err := broker.Publish(context.Background(), "article:1", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if got := receive(t, server.published, "the publish"); got != `test:article:1 {"id":1}` {
+		t.Errorf("published %q", got)
+	}
+	if got := receive(t, delivered, "the delivery"); got != `article:1 {"id":1}` {
+		t.Errorf("delivered %q", got)
+	}
+}
+
+func TestRedisBrokerReconnects(t *testing.T) {
+	server := newFakeRedis(t, "")
+	server.dropAfterPublish = true
+	broker := NewRedisBroker(RedisConfig{Addr: server.listener.Addr().String(), Prefix: "test:"})
+	defer broker.Close()
+
+	for i := 1; i <= 3; i++ {
+		payload := fmt.Sprintf("%d", i)
+		if err := broker.Publish(context.Background(), "user:1", []byte(payload)); err != nil {
+			t.Fatalf("publish %d: %v", i, err)
+		}
+		if got := receive(t, server.published, "the publish"); got != "test:user:1 "+payload {
+			t.Errorf("published %q", got)
+		}
+	}
+}
+
+func TestRedisBrokerDialFailures(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	broker := &RedisBroker{
+		RedisConfig: RedisConfig{Addr: server.listener.Addr().String(), Password: "wrong"},
+		DialTimeout: time.Second,
+	}
+	if _, err := broker.dial(context.Background()); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("dial with a wrong password: %v", err)
+	}
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	broker.Addr = listener.Addr().String()
+	listener.Close()
+	if _, err := broker.dial(context.Background()); err == nil {
+		t.Error("dial to a closed port succeeded")
+	}
+}
+
+func TestRedisBrokerPublishDoesNotBlock(t *testing.T) {
+	// no writer drains the queue, as if the server hung
+	broker := &RedisBroker{queue: make(chan redisMessage, 1), closed: make(chan struct{})}
+
+	if err := broker.Publish(context.Background(), "user:1", []byte("1")); err != nil {
+		t.Fatalf("first publish: %v", err)
+	}
+	if err := broker.Publish(context.Background(), "user:1", []byte("2")); err != errRedisQueueFull {
+		t.Errorf("publish to a full queue: %v, want %v", err, errRedisQueueFull)
+	}
+
+	close(broker.closed)
+	if err := broker.Publish(context.Background(), "user:1", []byte("3")); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("publish after close: %v, want %v", err, net.ErrClosed)
+	}
+}
+
+func TestRedisBrokerCloseStopsWriter(t *testing.T) {
+	broker := NewRedisBroker(RedisConfig{Addr: "127.0.0.1:1"})
+	closed := make(chan error, 1)
+	go func() {
+		closed <- broker.Close()
+		closed <- broker.Close()
+	}()
+	receive(t, closed, "the first close")
+	receive(t, closed, "the second close")
+}
@@ -0,0 +1,120 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Command is a message a WebSocket client sends to change what it follows,
+// e.g. {"action": "subscribe", "article_id": 1}.
+type Command struct {
+	Action    string `json:"action"`
+	ArticleId int    `json:"article_id"`
+}
+
+// StreamEvents writes the events of subscription to w as Server-Sent Events
+// until the client goes away or falls too far behind, then closes the
+// subscription. A comment line every heartbeat keeps proxies from timing the
+// stream out and reveals clients that left.
+func StreamEvents(w *bufio.Writer, subscription *Subscription, heartbeat time.Duration) {
+	defer subscription.Close()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	w.WriteString("retry: 3000\n\n")
+	for {
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case message, ok := <-subscription.Messages():
+			if !ok {
+				writeEvent(w, errorMessage(subscription.Err()))
+				w.Flush()
+				return
+			}
+			writeEvent(w, message)
+		case <-ticker.C:
+			w.WriteString(": heartbeat\n\n")
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, message Message) {
+	encoded, _ := json.Marshal(message)
+	w.WriteString("event: " + message.Type + "\ndata: ")
+	w.Write(encoded)
+	w.WriteString("\n\n")
+}
+
+// ServeWebSocket sends the events of subscription to ws and passes the
+// client's commands to handle until either side goes away, then closes the
+// subscription. The client is pinged every heartbeat and dropped when it
+// stays silent for two.
+func ServeWebSocket(ws *WebSocketConn, subscription *Subscription, heartbeat time.Duration, handle func(command Command) error) {
+	defer subscription.Close()
+	ws.ReadTimeout = 2 * heartbeat
+
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			data, err := ws.ReadMessage()
+			if errors.Is(err, errProtocol) {
+				ws.WriteClose(1002, err.Error())
+			} else if errors.Is(err, errMessageTooBig) {
+				ws.WriteClose(1009, err.Error())
+			}
+			if err != nil {
+				return
+			}
+
+			var command Command
+			if err := json.Unmarshal(data, &command); err != nil {
+				err = errors.New("invalid command")
+				writeMessage(ws, errorMessage(err))
+				continue
+			}
+			if err := handle(command); err != nil {
+				writeMessage(ws, errorMessage(err))
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message, ok := <-subscription.Messages():
+			if !ok {
+				// 1013: try again later
+				ws.WriteClose(1013, subscription.Err().Error())
+				return
+			}
+			if err := writeMessage(ws, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := ws.WritePing(); err != nil {
+				return
+			}
+		case <-readerDone:
+			return
+		}
+	}
+}
+
+func writeMessage(ws *WebSocketConn, message Message) error {
+	encoded, _ := json.Marshal(message)
+	return ws.WriteText(encoded)
+}
+
+func errorMessage(err error) Message {
+	data, _ := json.Marshal(map[string]string{"message": err.Error()})
+	return Message{Type: "error", Data: data}
+}
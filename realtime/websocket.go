@@ -0,0 +1,204 @@
+package realtime
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGuid is the fixed key suffix of the RFC 6455 handshake.
+const websocketGuid = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxClientMessage bounds the messages clients may send; they only send
+// small commands.
+const maxClientMessage = 4096
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var errMessageTooBig = errors.New("websocket: message too big")
+var errProtocol = errors.New("websocket: protocol error")
+
+func IsWebSocketUpgrade(c *fiber.Ctx) bool {
+	return strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket") &&
+		strings.Contains(strings.ToLower(c.Get(fiber.HeaderConnection)), "upgrade")
+}
+
+// UpgradeWebSocket answers the handshake and hands the connection to handler
+// once the response is sent. handler runs after the request is finished, so
+// it must not use c.
+func UpgradeWebSocket(c *fiber.Ctx, handler func(conn *WebSocketConn)) error {
+	key := c.Get("Sec-WebSocket-Key")
+	if !IsWebSocketUpgrade(c) || key == "" || c.Get("Sec-WebSocket-Version") != "13" {
+		return fiber.ErrUpgradeRequired
+	}
+
+	digest := sha1.Sum([]byte(key + websocketGuid))
+	c.Status(fiber.StatusSwitchingProtocols)
+	c.Set(fiber.HeaderUpgrade, "websocket")
+	c.Set(fiber.HeaderConnection, "Upgrade")
+	c.Set("Sec-WebSocket-Accept", base64.StdEncoding.EncodeToString(digest[:]))
+
+	c.Context().Hijack(func(conn net.Conn) {
+		defer conn.Close()
+		handler(&WebSocketConn{conn: conn, reader: bufio.NewReader(conn)})
+	})
+	return nil
+}
+
+// WebSocketConn is the server side of an upgraded connection. Reads must come
+// from one goroutine; writes are safe from several.
+type WebSocketConn struct {
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	conn       net.Conn
+	reader     *bufio.Reader
+	writeMutex sync.Mutex
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// and a close frame is echoed before io.EOF is returned.
+func (ws *WebSocketConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		if ws.ReadTimeout > 0 {
+			ws.conn.SetReadDeadline(time.Now().Add(ws.ReadTimeout))
+		}
+		final, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := ws.write(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			ws.write(opClose, payload)
+			return nil, io.EOF
+		case opText, opBinary, opContinuation:
+			// a message starts with a text or binary frame and goes on
+			// with continuation frames
+			if (opcode == opContinuation) != started {
+				return nil, errProtocol
+			}
+			if len(message)+len(payload) > maxClientMessage {
+				return nil, errMessageTooBig
+			}
+			message = append(message, payload...)
+			started = true
+			if final {
+				return message, nil
+			}
+		default:
+			return nil, errProtocol
+		}
+	}
+}
+
+func (ws *WebSocketConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	final := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	if !masked {
+		// clients must mask every frame
+		return false, 0, nil, errProtocol
+	}
+
+	size := uint64(header[1] & 0x7F)
+	switch size {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(ws.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(ws.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(extended[:])
+	}
+	if size > maxClientMessage {
+		return false, 0, nil, errMessageTooBig
+	}
+	if opcode&0x8 != 0 && (!final || size > 125) {
+		// control frames are never fragmented and carry at most 125 bytes
+		return false, 0, nil, errProtocol
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return final, opcode, payload, nil
+}
+
+func (ws *WebSocketConn) WriteText(payload []byte) error {
+	return ws.write(opText, payload)
+}
+
+func (ws *WebSocketConn) WritePing() error {
+	return ws.write(opPing, nil)
+}
+
+// WriteClose sends a close frame with the given status code, e.g. 1008 for a
+// policy violation.
+func (ws *WebSocketConn) WriteClose(code uint16, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, code)
+	return ws.write(opClose, append(payload, reason...))
+}
+
+func (ws *WebSocketConn) write(opcode byte, payload []byte) error {
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	switch size := len(payload); {
+	case size < 126:
+		frame = append(frame, byte(size))
+	case size <= 0xFFFF:
+		frame = binary.BigEndian.AppendUint16(append(frame, 126), uint16(size))
+	default:
+		frame = binary.BigEndian.AppendUint64(append(frame, 127), uint64(size))
+	}
+	frame = append(frame, payload...)
+
+	// a client that stops reading fills its socket buffer; the deadline turns
+	// that into an error instead of a stuck goroutine
+	if ws.WriteTimeout > 0 {
+		ws.conn.SetWriteDeadline(time.Now().Add(ws.WriteTimeout))
+	}
+	_, err := ws.conn.Write(frame)
+	return err
+}
@@ -0,0 +1,314 @@
+package realtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// newTestWebSocket returns the server side of a WebSocket over an in-memory
+// pipe and the raw client side.
+func newTestWebSocket(t *testing.T) (*WebSocketConn, net.Conn) {
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		server.Close()
+		client.Close()
+	})
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	return &WebSocketConn{conn: server, reader: bufio.NewReader(server)}, client
+}
+
+// clientFrame encodes a frame the way a browser sends it, masked.
+func clientFrame(final bool, opcode byte, payload []byte) []byte {
+	mask := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+	first := opcode
+	if final {
+		first |= 0x80
+	}
+
+	frame := []byte{first}
+	switch size := len(payload); {
+	case size < 126:
+		frame = append(frame, 0x80|byte(size))
+	case size <= 0xFFFF:
+		frame = binary.BigEndian.AppendUint16(append(frame, 0x80|126), uint16(size))
+	default:
+		frame = binary.BigEndian.AppendUint64(append(frame, 0x80|127), uint64(size))
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+type serverFrame struct {
+	final   bool
+	opcode  byte
+	payload []byte
+}
+
+// readServerFrame reads one frame written by the server, which must not be
+// masked.
+func readServerFrame(t *testing.T, reader *bufio.Reader) serverFrame {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		t.Fatalf("read frame header: %v", err)
+	}
+	if header[1]&0x80 != 0 {
+		t.Fatalf("server frame is masked")
+	}
+
+	size := uint64(header[1] & 0x7F)
+	switch size {
+	case 126:
+		var extended [2]byte
+		io.ReadFull(reader, extended[:])
+		size = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		io.ReadFull(reader, extended[:])
+		size = binary.BigEndian.Uint64(extended[:])
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("read frame payload: %v", err)
+	}
+	return serverFrame{final: header[0]&0x80 != 0, opcode: header[0] & 0x0F, payload: payload}
+}
+
+type readResult struct {
+	message []byte
+	err     error
+}
+
+// readMessage sends frames from the client while ReadMessage runs on the
+// server. Frames the server answers with are left for the caller to read.
+func readMessage(ws *WebSocketConn, client net.Conn, frames ...[]byte) <-chan readResult {
+	go func() {
+		for _, frame := range frames {
+			if _, err := client.Write(frame); err != nil {
+				return
+			}
+		}
+	}()
+
+	result := make(chan readResult, 1)
+	go func() {
+		message, err := ws.ReadMessage()
+		result <- readResult{message, err}
+	}()
+	return result
+}
+
+func TestWebSocketReadMessage(t *testing.T) {
+	long := bytes.Repeat([]byte("a"), 300)
+
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		message []byte
+		err     error
+	}{
+		{"text", [][]byte{clientFrame(true, opText, []byte(`{"action":"subscribe"}`))}, []byte(`{"action":"subscribe"}`), nil},
+		{"binary", [][]byte{clientFrame(true, opBinary, []byte{0, 1, 2})}, []byte{0, 1, 2}, nil},
+		{"empty", [][]byte{clientFrame(true, opText, nil)}, []byte{}, nil},
+		{"16-bit length", [][]byte{clientFrame(true, opText, long)}, long, nil},
+		{"fragments", [][]byte{
+			clientFrame(false, opText, []byte("hel")),
+			clientFrame(false, opContinuation, []byte("lo ")),
+			clientFrame(true, opContinuation, []byte("world")),
+		}, []byte("hello world"), nil},
+		{"pong between fragments", [][]byte{
+			clientFrame(false, opText, []byte("a")),
+			clientFrame(true, opPong, nil),
+			clientFrame(true, opContinuation, []byte("b")),
+		}, []byte("ab"), nil},
+		{"unmasked", [][]byte{{0x81, 0x01, 'a'}}, nil, errProtocol},
+		{"continuation first", [][]byte{clientFrame(true, opContinuation, []byte("a"))}, nil, errProtocol},
+		{"new message inside a fragmented one", [][]byte{
+			clientFrame(false, opText, []byte("a")),
+			clientFrame(true, opText, []byte("b")),
+		}, nil, errProtocol},
+		{"unknown opcode", [][]byte{clientFrame(true, 0x3, nil)}, nil, errProtocol},
+		{"fragmented control frame", [][]byte{clientFrame(false, opPing, nil)}, nil, errProtocol},
+		{"control frame too long", [][]byte{clientFrame(true, opPing, bytes.Repeat([]byte("a"), 126))}, nil, errProtocol},
+		{"frame too big", [][]byte{clientFrame(true, opText, make([]byte, maxClientMessage+1))}, nil, errMessageTooBig},
+		{"message too big", [][]byte{
+			clientFrame(false, opText, make([]byte, maxClientMessage)),
+			clientFrame(true, opContinuation, []byte("a")),
+		}, nil, errMessageTooBig},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ws, client := newTestWebSocket(t)
+			result := <-readMessage(ws, client, test.frames...)
+			if !errors.Is(result.err, test.err) {
+				t.Fatalf("err = %v, want %v", result.err, test.err)
+			}
+			if !bytes.Equal(result.message, test.message) {
+				t.Errorf("message = %q, want %q", result.message, test.message)
+			}
+		})
+	}
+}
+
+func TestWebSocketAnswersPing(t *testing.T) {
+	ws, client := newTestWebSocket(t)
+	result := readMessage(ws, client,
+		clientFrame(false, opText, []byte("a")),
+		clientFrame(true, opPing, []byte("ping data")),
+		clientFrame(true, opContinuation, []byte("b")),
+	)
+
+	frame := readServerFrame(t, bufio.NewReader(client))
+	if frame.opcode != opPong || !frame.final || string(frame.payload) != "ping data" {
+		t.Errorf("reply = %+v, want a pong echoing the ping", frame)
+	}
+	if got := <-result; got.err != nil || string(got.message) != "ab" {
+		t.Errorf("message = %q %v, want \"ab\"", got.message, got.err)
+	}
+}
+
+func TestWebSocketCloseHandshake(t *testing.T) {
+	ws, client := newTestWebSocket(t)
+	payload := binary.BigEndian.AppendUint16(nil, 1000)
+	payload = append(payload, "bye"...)
+	result := readMessage(ws, client, clientFrame(true, opClose, payload))
+
+	frame := readServerFrame(t, bufio.NewReader(client))
+	if frame.opcode != opClose || !bytes.Equal(frame.payload, payload) {
+		t.Errorf("reply = %+v, want the close frame echoed", frame)
+	}
+	if got := <-result; got.err != io.EOF {
+		t.Errorf("err = %v, want io.EOF", got.err)
+	}
+}
+
+func TestWebSocketWrite(t *testing.T) {
+	long := bytes.Repeat([]byte("b"), 70000)
+
+	tests := []struct {
+		name    string
+		write   func(ws *WebSocketConn) error
+		opcode  byte
+		payload []byte
+	}{
+		{"text", func(ws *WebSocketConn) error { return ws.WriteText([]byte("hi")) }, opText, []byte("hi")},
+		{"16-bit length", func(ws *WebSocketConn) error { return ws.WriteText(long[:200]) }, opText, long[:200]},
+		{"64-bit length", func(ws *WebSocketConn) error { return ws.WriteText(long) }, opText, long},
+		{"ping", func(ws *WebSocketConn) error { return ws.WritePing() }, opPing, []byte{}},
+		{"close", func(ws *WebSocketConn) error { return ws.WriteClose(1008, "policy") }, opClose, append([]byte{0x03, 0xF0}, "policy"...)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ws, client := newTestWebSocket(t)
+			ws.WriteTimeout = 5 * time.Second
+			written := make(chan error, 1)
+			go func() {
+				written <- test.write(ws)
+			}()
+
+			frame := readServerFrame(t, bufio.NewReader(client))
+			if err := <-written; err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if !frame.final || frame.opcode != test.opcode || !bytes.Equal(frame.payload, test.payload) {
+				t.Errorf("frame = final %v opcode %x %d bytes, want opcode %x %d bytes", frame.final, frame.opcode, len(frame.payload), test.opcode, len(test.payload))
+			}
+		})
+	}
+}
+
+func TestWebSocketWriteTimeout(t *testing.T) {
+	ws, _ := newTestWebSocket(t)
+	ws.WriteTimeout = 10 * time.Millisecond
+
+	// nobody reads the client side
+	var err error
+	if err = ws.WriteText([]byte("hi")); err == nil {
+		t.Fatal("write to a client that does not read succeeded")
+	}
+	if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+}
+
+func TestServeWebSocket(t *testing.T) {
+	ws, client := newTestWebSocket(t)
+	hub := NewHub(NewLocalBroker(), 4)
+	subscription := hub.Subscribe(ArticleTopic(1))
+
+	commands := make(chan Command, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ServeWebSocket(ws, subscription, time.Minute, func(command Command) error {
+			commands <- command
+			return nil
+		})
+	}()
+	reader := bufio.NewReader(client)
+
+	client.Write(clientFrame(true, opText, []byte(`{"action":"subscribe","article_id":2}`)))
+	if command := <-commands; command.Action != "subscribe" || command.ArticleId != 2 {
+		t.Errorf("command = %+v", command)
+	}
+
+	hub.Publish(context.Background(), ArticleTopic(1), "comment.created", map[string]int{"id": 7})
+	frame := readServerFrame(t, reader)
+	var message Message
+	if err := json.Unmarshal(frame.payload, &message); err != nil || frame.opcode != opText {
+		t.Fatalf("frame = %x %q: %v", frame.opcode, frame.payload, err)
+	}
+	if message.Topic != ArticleTopic(1) || message.Type != "comment.created" || string(message.Data) != `{"id":7}` {
+		t.Errorf("message = %+v", message)
+	}
+
+	client.Write(clientFrame(true, opText, []byte("not json")))
+	frame = readServerFrame(t, reader)
+	if err := json.Unmarshal(frame.payload, &message); err != nil || message.Type != "error" {
+		t.Errorf("reply to an invalid command = %q", frame.payload)
+	}
+
+	// a protocol error closes the connection with 1002
+	client.Write([]byte{0x81, 0x01, 'a'})
+	frame = readServerFrame(t, reader)
+	if frame.opcode != opClose || binary.BigEndian.Uint16(frame.payload) != 1002 {
+		t.Errorf("frame = %x %q, want close 1002", frame.opcode, frame.payload)
+	}
+
+	<-done
+	if subscription.Count() != 0 {
+		t.Error("subscription still open after the connection ended")
+	}
+}
+
+func TestServeWebSocketSlowConsumer(t *testing.T) {
+	ws, client := newTestWebSocket(t)
+	hub := NewHub(NewLocalBroker(), 1)
+	subscription := hub.Subscribe(UserTopic(1))
+
+	// the subscription overflows before the writer gets to it
+	hub.Publish(context.Background(), UserTopic(1), "notification.created", 1)
+	hub.Publish(context.Background(), UserTopic(1), "notification.created", 2)
+
+	go ServeWebSocket(ws, subscription, time.Minute, func(command Command) error { return nil })
+
+	reader := bufio.NewReader(client)
+	if frame := readServerFrame(t, reader); frame.opcode != opText {
+		t.Errorf("first frame = %x %q, want the queued event", frame.opcode, frame.payload)
+	}
+	frame := readServerFrame(t, reader)
+	if frame.opcode != opClose || binary.BigEndian.Uint16(frame.payload) != 1013 || string(frame.payload[2:]) != ErrSlowConsumer.Error() {
+		t.Errorf("frame = %x %q, want close 1013", frame.opcode, frame.payload)
+	}
+}
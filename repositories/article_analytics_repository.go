@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type ArticleAnalyticsRepository interface {
+	CreateView(ctx context.Context, tx *sql.Tx, view entity.ArticleView, window time.Duration) bool
+	CurrentDay(ctx context.Context, tx *sql.Tx) string
+	RollupDay(ctx context.Context, tx *sql.Tx, day string)
+	FindStatsByArticleID(ctx context.Context, tx *sql.Tx, articleId int, from string, to string) (entity.ArticleStats, error)
+	FindStatsByUserID(ctx context.Context, tx *sql.Tx, userId int, from string, to string) []entity.ArticleStats
+	FindDailyByArticleID(ctx context.Context, tx *sql.Tx, articleId int, from string, to string) []entity.ArticleStatsDaily
+}
+
+type ArticleAnalyticsRepositoryImpl struct {
+}
+
+func NewArticleAnalyticsRepository() ArticleAnalyticsRepository {
+	return &ArticleAnalyticsRepositoryImpl{}
+}
+
+// CreateView stores a view unless userId already viewed the article within
+// the current window, and reports whether it did. The window is a fixed slot
+// of NOW(), so concurrent reads cannot both be counted.
+func (repository *ArticleAnalyticsRepositoryImpl) CreateView(ctx context.Context, tx *sql.Tx, view entity.ArticleView, window time.Duration) bool {
+	SQL := `INSERT IGNORE INTO article_views (article_id, user_id, view_window) VALUES (?, ?, FLOOR(UNIX_TIMESTAMP(NOW()) / ?))`
+	result, err := tx.ExecContext(ctx, SQL, view.ArticleId, view.UserId, int(window.Seconds()))
+	helper.PanicIfErr(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfErr(err)
+	return affected > 0
+}
+
+// CurrentDay returns the date (YYYY-MM-DD) of NOW() in the database, which is
+// the clock view, like and comment timestamps are taken from.
+func (repository *ArticleAnalyticsRepositoryImpl) CurrentDay(ctx context.Context, tx *sql.Tx) string {
+	SQL := `SELECT DATE_FORMAT(NOW(), '%Y-%m-%d')`
+	var day string
+	err := tx.QueryRowContext(ctx, SQL).Scan(&day)
+	helper.PanicIfErr(err)
+	return day
+}
+
+// RollupDay recomputes the article_stats_daily rows of day (YYYY-MM-DD) from
+// the raw views, likes and comments. Running it again for the same day is safe.
+func (repository *ArticleAnalyticsRepositoryImpl) RollupDay(ctx context.Context, tx *sql.Tx, day string) {
+	SQL := `INSERT INTO article_stats_daily (article_id, day, views, unique_readers, likes, comments)
+			SELECT
+				s.article_id, ?, SUM(s.views), SUM(s.unique_readers), SUM(s.likes), SUM(s.comments)
+			FROM (
+				SELECT article_id, COUNT(*) AS views, COUNT(DISTINCT user_id) AS unique_readers, 0 AS likes, 0 AS comments
+				FROM article_views
+				WHERE created_at >= ? AND created_at < ? + INTERVAL 1 DAY
+				GROUP BY article_id
+				UNION ALL
+				SELECT article_id, 0, 0, COUNT(*), 0
+				FROM likes
+				WHERE reaction = 'like' AND created_at >= ? AND created_at < ? + INTERVAL 1 DAY
+				GROUP BY article_id
+				UNION ALL
+				SELECT article_id, 0, 0, 0, COUNT(*)
+				FROM comments
+				WHERE article_id IS NOT NULL AND deleted_at IS NULL AND created_at >= ? AND created_at < ? + INTERVAL 1 DAY
+				GROUP BY article_id
+			) s
+			GROUP BY
+				s.article_id
+			ON DUPLICATE KEY UPDATE
+				views = VALUES(views),
+				unique_readers = VALUES(unique_readers),
+				likes = VALUES(likes),
+				comments = VALUES(comments)`
+	_, err := tx.ExecContext(ctx, SQL, day, day, day, day, day, day, day)
+	helper.PanicIfErr(err)
+}
+
+func (repository *ArticleAnalyticsRepositoryImpl) FindStatsByArticleID(ctx context.Context, tx *sql.Tx, articleId int, from string, to string) (entity.ArticleStats, error) {
+	stats := repository.findStats(ctx, tx, "a.id = ?", articleId, from, to)
+	if len(stats) == 0 {
+		return entity.ArticleStats{}, errors.New("article not found")
+	}
+	return stats[0], nil
+}
+
+func (repository *ArticleAnalyticsRepositoryImpl) FindStatsByUserID(ctx context.Context, tx *sql.Tx, userId int, from string, to string) []entity.ArticleStats {
+	return repository.findStats(ctx, tx, "a.user_id = ?", userId, from, to)
+}
+
+// findStats sums the daily rollups over from..to. Unique readers cannot be
+// summed across days, so they are counted from the raw views instead.
+func (repository *ArticleAnalyticsRepositoryImpl) findStats(ctx context.Context, tx *sql.Tx, condition string, arg any, from string, to string) []entity.ArticleStats {
+	SQL := `SELECT
+				a.id,
+				a.title,
+				a.slug,
+				COALESCE(SUM(d.views), 0),
+				(
+					SELECT COUNT(DISTINCT v.user_id) FROM article_views v
+					WHERE v.article_id = a.id AND v.created_at >= ? AND v.created_at < ? + INTERVAL 1 DAY
+				),
+				COALESCE(SUM(d.likes), 0),
+				COALESCE(SUM(d.comments), 0)
+			FROM
+				articles a
+			LEFT JOIN
+				article_stats_daily d ON a.id = d.article_id AND d.day BETWEEN ? AND ?
+			WHERE
+				` + condition + `
+			GROUP BY
+				a.id
+			ORDER BY
+				a.created_at DESC`
+	rows, err := tx.QueryContext(ctx, SQL, from, to, from, to, arg)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var stats []entity.ArticleStats
+	for rows.Next() {
+		var stat entity.ArticleStats
+		err := rows.Scan(&stat.ArticleId, &stat.Title, &stat.Slug, &stat.Views, &stat.UniqueReaders, &stat.Likes, &stat.Comments)
+		helper.PanicIfErr(err)
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+func (repository *ArticleAnalyticsRepositoryImpl) FindDailyByArticleID(ctx context.Context, tx *sql.Tx, articleId int, from string, to string) []entity.ArticleStatsDaily {
+	SQL := `SELECT article_id, day, views, unique_readers, likes, comments
+			FROM article_stats_daily
+			WHERE article_id = ? AND day BETWEEN ? AND ?
+			ORDER BY day`
+	rows, err := tx.QueryContext(ctx, SQL, articleId, from, to)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var daily []entity.ArticleStatsDaily
+	for rows.Next() {
+		var stat entity.ArticleStatsDaily
+		err := rows.Scan(&stat.ArticleId, &stat.Day, &stat.Views, &stat.UniqueReaders, &stat.Likes, &stat.Comments)
+		helper.PanicIfErr(err)
+		daily = append(daily, stat)
+	}
+	return daily
+}
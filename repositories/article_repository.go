@@ -3,6 +3,9 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
+	"time"
 	"uaspw2/helper"
 	"uaspw2/models/entity"
 )
@@ -10,11 +13,27 @@ import (
 type ArticleRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, article entity.Article) entity.Article
 	CreateMedia(ctx context.Context, tx *sql.Tx, media entity.ArticleMedia) entity.ArticleMedia
-	Update(ctx context.Context, tx *sql.Tx, article entity.Article) entity.Article
+	FindMediaByID(ctx context.Context, tx *sql.Tx, mediaId int) (entity.ArticleMedia, error)
+	DeleteMedia(ctx context.Context, tx *sql.Tx, mediaId int)
+	Update(ctx context.Context, tx *sql.Tx, article entity.Article) (entity.Article, error)
+	IncrementVersion(ctx context.Context, tx *sql.Tx, articleId int)
 	Delete(ctx context.Context, tx *sql.Tx, articleId int)
 	FindByID(ctx context.Context, tx *sql.Tx, articleId int) (entity.Article, error)
+	FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Article, error)
+	FindIDBySlugRedirect(ctx context.Context, tx *sql.Tx, slug string) (int, error)
+	CreateSlugRedirect(ctx context.Context, tx *sql.Tx, articleId int, slug string)
+	DeleteSlugRedirect(ctx context.Context, tx *sql.Tx, slug string)
+	IsSlugTaken(ctx context.Context, tx *sql.Tx, slug string, articleId int) bool
 	FindAllByPublishStatus(ctx context.Context, tx *sql.Tx, publishStatus bool) []entity.Article
 	FindAllByPublishStatusAndUserID(ctx context.Context, tx *sql.Tx, publishStatus bool, userId int) []entity.Article
+	FindAllPublishedByTagID(ctx context.Context, tx *sql.Tx, tagId int, beforeCreatedAt string, beforeId int, limit int) []entity.Article
+	FindAllPublishedByCategoryIDs(ctx context.Context, tx *sql.Tx, categoryIds []int, beforeCreatedAt string, beforeId int, limit int) []entity.Article
+	FindTimeline(ctx context.Context, tx *sql.Tx, userId int, beforeCreatedAt string, beforeId int, limit int) []entity.Article
+	FindLatestPublished(ctx context.Context, tx *sql.Tx, userId int, tagId int, limit int) []entity.Article
+	FindAllPublishedRanked(ctx context.Context, tx *sql.Tx, sort string, period string, limit int, offset int) []entity.Article
+	RefreshScores(ctx context.Context, tx *sql.Tx, halfLife time.Duration)
+	CountReactionsByArticleIDs(ctx context.Context, tx *sql.Tx, articleIds []int) map[int]map[string]int
+	CountCommentsByArticleIDs(ctx context.Context, tx *sql.Tx, articleIds []int) map[int]int
 	UpdatePublishStatus(ctx context.Context, tx *sql.Tx, articleId int, status bool)
 }
 
@@ -26,14 +45,14 @@ func NewArticleRepository() ArticleRepository {
 }
 
 func (repository *ArticleRepositoryImpl) UpdatePublishStatus(ctx context.Context, tx *sql.Tx, articleId int, status bool) {
-	SQL := "UPDATE articles SET is_published = ? where id = ?"
+	SQL := "UPDATE articles SET is_published = ?, version = version + 1 where id = ?"
 	_, err := tx.ExecContext(ctx, SQL, status, articleId)
 	helper.PanicIfErr(err)
 }
 
 func (repository *ArticleRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, article entity.Article) entity.Article {
-	SQL := `INSERT INTO articles (user_id, title, description, content, is_published) VALUES (?, ?, ?, ?, ?)`
-	result, err := tx.ExecContext(ctx, SQL, article.UserId, article.Title, article.Description, article.Content, article.IsPublished)
+	SQL := `INSERT INTO articles (user_id, title, slug, description, content, is_published) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, article.UserId, article.Title, article.Slug, article.Description, article.Content, article.IsPublished)
 	helper.PanicIfErr(err)
 
 	id, err := result.LastInsertId()
@@ -53,12 +72,49 @@ func (repository *ArticleRepositoryImpl) CreateMedia(ctx context.Context, tx *sq
 	return media
 }
 
-func (repository *ArticleRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, article entity.Article) entity.Article {
-	SQL := `UPDATE articles SET title = ?, description = ?, is_published = ? WHERE id = ?`
-	_, err := tx.ExecContext(ctx, SQL, article.Title, article.Description, article.IsPublished, article.Id)
+func (repository *ArticleRepositoryImpl) FindMediaByID(ctx context.Context, tx *sql.Tx, mediaId int) (entity.ArticleMedia, error) {
+	SQL := `SELECT id, article_id, type, path, created_at, updated_at FROM article_medias WHERE id = ?`
+	row, err := tx.QueryContext(ctx, SQL, mediaId)
 	helper.PanicIfErr(err)
+	defer row.Close()
 
-	return article
+	var media entity.ArticleMedia
+	if row.Next() {
+		err := row.Scan(&media.Id, &media.ArticleId, &media.Type, &media.Path, &media.CreatedAt, &media.UpdatedAt)
+		helper.PanicIfErr(err)
+		return media, nil
+	} else {
+		return media, errors.New("article media not found")
+	}
+}
+
+func (repository *ArticleRepositoryImpl) DeleteMedia(ctx context.Context, tx *sql.Tx, mediaId int) {
+	SQL := `DELETE FROM article_medias WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, mediaId)
+	helper.PanicIfErr(err)
+}
+
+// Update only succeeds while the row still has article.Version; the returned
+// article carries the next version.
+func (repository *ArticleRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, article entity.Article) (entity.Article, error) {
+	SQL := `UPDATE articles SET title = ?, slug = ?, description = ?, content = ?, version = version + 1 WHERE id = ? AND version = ?`
+	result, err := tx.ExecContext(ctx, SQL, article.Title, article.Slug, article.Description, article.Content, article.Id, article.Version)
+	helper.PanicIfErr(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfErr(err)
+	if affected == 0 {
+		return article, errors.New("article was modified by another request")
+	}
+
+	article.Version++
+	return article, nil
+}
+
+func (repository *ArticleRepositoryImpl) IncrementVersion(ctx context.Context, tx *sql.Tx, articleId int) {
+	SQL := `UPDATE articles SET version = version + 1 WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, articleId)
+	helper.PanicIfErr(err)
 }
 
 func (repository *ArticleRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, articleId int) {
@@ -68,19 +124,31 @@ func (repository *ArticleRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx,
 }
 
 func (repository *ArticleRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, articleId int) (entity.Article, error) {
+	return repository.findOne(ctx, tx, "a.id = ?", articleId)
+}
+
+func (repository *ArticleRepositoryImpl) FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Article, error) {
+	return repository.findOne(ctx, tx, "a.slug = ?", slug)
+}
+
+func (repository *ArticleRepositoryImpl) findOne(ctx context.Context, tx *sql.Tx, condition string, arg any) (entity.Article, error) {
 	SQL := `SELECT 
 				a.id,
 				a.user_id,
 				a.title,
+				a.slug,
 				a.description,
 				a.content,
 				a.is_published,
+				a.version,
 				a.created_at,
 				a.updated_at,
 				up.full_name,
 				am.id AS media_id,
 				am.type AS media_type,
-				am.path AS media_path
+				am.path AS media_path,
+				am.created_at AS media_created_at,
+				am.updated_at AS media_updated_at
 			FROM 
 				articles a
 			JOIN 
@@ -88,8 +156,8 @@ func (repository *ArticleRepositoryImpl) FindByID(ctx context.Context, tx *sql.T
 			LEFT JOIN 
 				article_medias am ON a.id = am.article_id
 			WHERE 
-				a.id = ?`
-	rows, err := tx.QueryContext(ctx, SQL, articleId)
+				` + condition
+	rows, err := tx.QueryContext(ctx, SQL, arg)
 	helper.PanicIfErr(err)
 	defer rows.Close()
 
@@ -100,20 +168,26 @@ func (repository *ArticleRepositoryImpl) FindByID(ctx context.Context, tx *sql.T
 		var mediaID sql.NullInt64
 		var mediaType sql.NullString
 		var mediaPath sql.NullString
+		var mediaCreatedAt sql.NullString
+		var mediaUpdatedAt sql.NullString
 
 		err := rows.Scan(
 			&article.Id,
 			&article.UserId,
 			&article.Title,
+			&article.Slug,
 			&article.Description,
 			&article.Content,
 			&article.IsPublished,
+			&article.Version,
 			&article.CreatedAt,
 			&article.UpdatedAt,
 			&article.Author,
 			&mediaID,
 			&mediaType,
 			&mediaPath,
+			&mediaCreatedAt,
+			&mediaUpdatedAt,
 		)
 		if err != nil {
 			return entity.Article{}, err
@@ -121,13 +195,20 @@ func (repository *ArticleRepositoryImpl) FindByID(ctx context.Context, tx *sql.T
 
 		if mediaID.Valid {
 			media = append(media, entity.ArticleMedia{
-				Id:   int(mediaID.Int64),
-				Type: mediaType.String,
-				Path: mediaPath.String,
+				Id:        int(mediaID.Int64),
+				ArticleId: article.Id,
+				Type:      mediaType.String,
+				Path:      mediaPath.String,
+				CreatedAt: mediaCreatedAt.String,
+				UpdatedAt: mediaUpdatedAt.String,
 			})
 		}
 	}
 
+	if article.Id == 0 {
+		return article, errors.New("article not found")
+	}
+
 	article.Media = media
 	return article, nil
 }
@@ -137,9 +218,11 @@ func (repository *ArticleRepositoryImpl) FindAllByPublishStatus(ctx context.Cont
 					a.id,
 					a.user_id,
 					a.title,
+					a.slug,
 					a.description,
 					a.content,
 					a.is_published,
+					a.version,
 					a.created_at,
 					a.updated_at,
 					up.full_name
@@ -156,7 +239,7 @@ func (repository *ArticleRepositoryImpl) FindAllByPublishStatus(ctx context.Cont
 
 	for rows.Next() {
 		var article entity.Article
-		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Description, &article.Content, &article.IsPublished, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
 		articles = append(articles, article)
 		helper.PanicIfErr(err)
 	}
@@ -168,9 +251,11 @@ func (repository *ArticleRepositoryImpl) FindAllByPublishStatusAndUserID(ctx con
 					a.id,
 					a.user_id,
 					a.title,
+					a.slug,
 					a.description,
 					a.content,
 					a.is_published,
+					a.version,
 					a.created_at,
 					a.updated_at,
 					up.full_name
@@ -190,9 +275,391 @@ func (repository *ArticleRepositoryImpl) FindAllByPublishStatusAndUserID(ctx con
 
 	for rows.Next() {
 		var article entity.Article
-		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Description, &article.Content, &article.IsPublished, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
 		articles = append(articles, article)
 		helper.PanicIfErr(err)
 	}
 	return articles
 }
+
+// FindAllPublishedByTagID returns the published articles of a tag, newest
+// first. Paging works as in FindTimeline.
+func (repository *ArticleRepositoryImpl) FindAllPublishedByTagID(ctx context.Context, tx *sql.Tx, tagId int, beforeCreatedAt string, beforeId int, limit int) []entity.Article {
+	SQL := `SELECT 
+					a.id,
+					a.user_id,
+					a.title,
+					a.slug,
+					a.description,
+					a.content,
+					a.is_published,
+					a.version,
+					a.created_at,
+					a.updated_at,
+					up.full_name
+				FROM 
+					articles a
+				JOIN 
+					user_profiles up ON a.user_id = up.user_id
+				JOIN 
+					article_tags at ON a.id = at.article_id
+				WHERE 
+					a.is_published = true 
+					AND at.tag_id = ?
+					AND (? = '' OR a.created_at < ? OR (a.created_at = ? AND a.id < ?))
+				ORDER BY 
+					a.created_at DESC, a.id DESC
+				LIMIT ?`
+	rows, err := tx.QueryContext(ctx, SQL, tagId, beforeCreatedAt, beforeCreatedAt, beforeCreatedAt, beforeId, limit)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articles []entity.Article
+
+	for rows.Next() {
+		var article entity.Article
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// FindAllPublishedByCategoryIDs returns the published articles in any of
+// categoryIds, newest first. Paging works as in FindTimeline.
+func (repository *ArticleRepositoryImpl) FindAllPublishedByCategoryIDs(ctx context.Context, tx *sql.Tx, categoryIds []int, beforeCreatedAt string, beforeId int, limit int) []entity.Article {
+	if len(categoryIds) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(categoryIds)), ",")
+	args := make([]any, len(categoryIds))
+	for i, id := range categoryIds {
+		args[i] = id
+	}
+
+	SQL := `SELECT DISTINCT
+					a.id,
+					a.user_id,
+					a.title,
+					a.slug,
+					a.description,
+					a.content,
+					a.is_published,
+					a.version,
+					a.created_at,
+					a.updated_at,
+					up.full_name
+				FROM 
+					articles a
+				JOIN 
+					user_profiles up ON a.user_id = up.user_id
+				JOIN 
+					article_categories ac ON a.id = ac.article_id
+				WHERE 
+					a.is_published = true 
+					AND ac.category_id IN (` + placeholders + `)
+					AND (? = '' OR a.created_at < ? OR (a.created_at = ? AND a.id < ?))
+				ORDER BY 
+					a.created_at DESC, a.id DESC
+				LIMIT ?`
+	args = append(args, beforeCreatedAt, beforeCreatedAt, beforeCreatedAt, beforeId, limit)
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articles []entity.Article
+
+	for rows.Next() {
+		var article entity.Article
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// FindTimeline returns published articles of the authors userId follows,
+// newest first. An empty beforeCreatedAt starts at the newest article,
+// otherwise only articles ordered after (beforeCreatedAt, beforeId) are read.
+func (repository *ArticleRepositoryImpl) FindTimeline(ctx context.Context, tx *sql.Tx, userId int, beforeCreatedAt string, beforeId int, limit int) []entity.Article {
+	SQL := `SELECT 
+					a.id,
+					a.user_id,
+					a.title,
+					a.slug,
+					a.description,
+					a.content,
+					a.is_published,
+					a.version,
+					a.created_at,
+					a.updated_at,
+					up.full_name
+				FROM 
+					articles a
+				JOIN 
+					follows f ON a.user_id = f.followee_id
+				JOIN 
+					user_profiles up ON a.user_id = up.user_id
+				WHERE 
+					f.follower_id = ?
+					AND a.is_published = true
+					AND (? = '' OR a.created_at < ? OR (a.created_at = ? AND a.id < ?))
+				ORDER BY 
+					a.created_at DESC, a.id DESC
+				LIMIT ?`
+	rows, err := tx.QueryContext(ctx, SQL, userId, beforeCreatedAt, beforeCreatedAt, beforeCreatedAt, beforeId, limit)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articles []entity.Article
+
+	for rows.Next() {
+		var article entity.Article
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// FindLatestPublished returns the newest published articles, limited to one
+// author or tag when userId or tagId is not 0.
+func (repository *ArticleRepositoryImpl) FindLatestPublished(ctx context.Context, tx *sql.Tx, userId int, tagId int, limit int) []entity.Article {
+	SQL := `SELECT 
+					a.id,
+					a.user_id,
+					a.title,
+					a.slug,
+					a.description,
+					a.content,
+					a.is_published,
+					a.version,
+					a.created_at,
+					a.updated_at,
+					up.full_name
+				FROM 
+					articles a
+				JOIN 
+					user_profiles up ON a.user_id = up.user_id
+				WHERE 
+					a.is_published = true
+					AND (? = 0 OR a.user_id = ?)
+					AND (? = 0 OR EXISTS (SELECT 1 FROM article_tags at WHERE at.article_id = a.id AND at.tag_id = ?))
+				ORDER BY 
+					a.created_at DESC, a.id DESC
+				LIMIT ?`
+	rows, err := tx.QueryContext(ctx, SQL, userId, userId, tagId, tagId, limit)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articles []entity.Article
+
+	for rows.Next() {
+		var article entity.Article
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// FindAllPublishedRanked pages through published articles ordered for a feed:
+// "trending" by decayed score, "top" by likes in the last week or month and
+// "new" by creation time. Scores come from article_scores, see RefreshScores.
+func (repository *ArticleRepositoryImpl) FindAllPublishedRanked(ctx context.Context, tx *sql.Tx, sort string, period string, limit int, offset int) []entity.Article {
+	var orderBy string
+	switch {
+	case sort == "trending":
+		orderBy = "COALESCE(s.trending_score, 0) DESC, a.created_at DESC"
+	case sort == "top" && period == "month":
+		orderBy = "COALESCE(s.likes_month, 0) DESC, a.created_at DESC"
+	case sort == "top":
+		orderBy = "COALESCE(s.likes_week, 0) DESC, a.created_at DESC"
+	default:
+		orderBy = "a.created_at DESC"
+	}
+
+	SQL := `SELECT 
+					a.id,
+					a.user_id,
+					a.title,
+					a.slug,
+					a.description,
+					a.content,
+					a.is_published,
+					a.version,
+					a.created_at,
+					a.updated_at,
+					up.full_name
+				FROM 
+					articles a
+				JOIN 
+					user_profiles up ON a.user_id = up.user_id
+				LEFT JOIN 
+					article_scores s ON a.id = s.article_id
+				WHERE 
+					a.is_published = true
+				ORDER BY 
+					` + orderBy + `, a.id DESC
+				LIMIT ? OFFSET ?`
+	rows, err := tx.QueryContext(ctx, SQL, limit, offset)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articles []entity.Article
+
+	for rows.Next() {
+		var article entity.Article
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// RefreshScores recomputes article_scores for published articles. Reactions,
+// comments and views from the last 30 days are weighted 3, 2 and 1 and lose
+// half their weight every halfLife. The top feeds only count likes.
+func (repository *ArticleRepositoryImpl) RefreshScores(ctx context.Context, tx *sql.Tx, halfLife time.Duration) {
+	SQL := `INSERT INTO article_scores (article_id, trending_score, likes_week, likes_month)
+			SELECT
+				a.id,
+				COALESCE(e.score, 0),
+				COALESCE(l.likes_week, 0),
+				COALESCE(l.likes_month, 0)
+			FROM
+				articles a
+			LEFT JOIN (
+				SELECT article_id, SUM(weight * POW(0.5, TIMESTAMPDIFF(MINUTE, created_at, NOW()) / ?)) AS score
+				FROM (
+					SELECT article_id, created_at, 3 AS weight FROM likes
+					WHERE created_at >= NOW() - INTERVAL 30 DAY
+					UNION ALL
+					SELECT article_id, created_at, 2 FROM comments
+					WHERE article_id IS NOT NULL AND deleted_at IS NULL AND created_at >= NOW() - INTERVAL 30 DAY
+					UNION ALL
+					SELECT article_id, created_at, 1 FROM article_views
+					WHERE created_at >= NOW() - INTERVAL 30 DAY
+				) events
+				GROUP BY article_id
+			) e ON a.id = e.article_id
+			LEFT JOIN (
+				SELECT article_id, SUM(created_at >= NOW() - INTERVAL 7 DAY) AS likes_week, COUNT(*) AS likes_month
+				FROM likes
+				WHERE reaction = 'like' AND created_at >= NOW() - INTERVAL 30 DAY
+				GROUP BY article_id
+			) l ON a.id = l.article_id
+			WHERE
+				a.is_published = true
+			ON DUPLICATE KEY UPDATE
+				trending_score = VALUES(trending_score),
+				likes_week = VALUES(likes_week),
+				likes_month = VALUES(likes_month)`
+	_, err := tx.ExecContext(ctx, SQL, halfLife.Minutes())
+	helper.PanicIfErr(err)
+}
+
+func (repository *ArticleRepositoryImpl) FindIDBySlugRedirect(ctx context.Context, tx *sql.Tx, slug string) (int, error) {
+	SQL := `SELECT article_id FROM article_slug_redirects WHERE slug = ?`
+	row, err := tx.QueryContext(ctx, SQL, slug)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var articleId int
+	if row.Next() {
+		err := row.Scan(&articleId)
+		helper.PanicIfErr(err)
+		return articleId, nil
+	} else {
+		return articleId, errors.New("article not found")
+	}
+}
+
+func (repository *ArticleRepositoryImpl) CreateSlugRedirect(ctx context.Context, tx *sql.Tx, articleId int, slug string) {
+	SQL := `INSERT INTO article_slug_redirects (slug, article_id) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, slug, articleId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *ArticleRepositoryImpl) DeleteSlugRedirect(ctx context.Context, tx *sql.Tx, slug string) {
+	SQL := `DELETE FROM article_slug_redirects WHERE slug = ?`
+	_, err := tx.ExecContext(ctx, SQL, slug)
+	helper.PanicIfErr(err)
+}
+
+// IsSlugTaken reports whether slug is used by another article, either as its
+// current slug or as one of its old slugs kept for redirects.
+func (repository *ArticleRepositoryImpl) IsSlugTaken(ctx context.Context, tx *sql.Tx, slug string, articleId int) bool {
+	SQL := `SELECT EXISTS (
+				SELECT 1 FROM articles WHERE slug = ? AND id <> ?
+				UNION ALL
+				SELECT 1 FROM article_slug_redirects WHERE slug = ? AND article_id <> ?
+			)`
+	var taken bool
+	err := tx.QueryRowContext(ctx, SQL, slug, articleId, slug, articleId).Scan(&taken)
+	helper.PanicIfErr(err)
+	return taken
+}
+
+// CountReactionsByArticleIDs counts the reactions to each of articleIds by
+// reaction. Articles without reactions are left out.
+func (repository *ArticleRepositoryImpl) CountReactionsByArticleIDs(ctx context.Context, tx *sql.Tx, articleIds []int) map[int]map[string]int {
+	counts := map[int]map[string]int{}
+	if len(articleIds) == 0 {
+		return counts
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(articleIds)), ",")
+	var args []any
+	for _, id := range articleIds {
+		args = append(args, id)
+	}
+
+	SQL := `SELECT article_id, reaction, COUNT(*) FROM likes WHERE article_id IN (` + placeholders + `) GROUP BY article_id, reaction`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId, count int
+		var reaction string
+		err := rows.Scan(&articleId, &reaction, &count)
+		helper.PanicIfErr(err)
+		if counts[articleId] == nil {
+			counts[articleId] = map[string]int{}
+		}
+		counts[articleId][reaction] = count
+	}
+	return counts
+}
+
+// CountCommentsByArticleIDs counts the visible comments on each of articleIds.
+// Articles without comments are left out.
+func (repository *ArticleRepositoryImpl) CountCommentsByArticleIDs(ctx context.Context, tx *sql.Tx, articleIds []int) map[int]int {
+	counts := map[int]int{}
+	if len(articleIds) == 0 {
+		return counts
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(articleIds)), ",")
+	var args []any
+	for _, id := range articleIds {
+		args = append(args, id)
+	}
+
+	SQL := `SELECT article_id, COUNT(*) FROM comments
+			WHERE article_id IN (` + placeholders + `) AND is_hidden = false AND deleted_at IS NULL
+			GROUP BY article_id`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId, count int
+		err := rows.Scan(&articleId, &count)
+		helper.PanicIfErr(err)
+		counts[articleId] = count
+	}
+	return counts
+}
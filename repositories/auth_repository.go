@@ -28,6 +28,7 @@ func (repository *AuthRepositoryImpl) GetUserByUsername(ctx context.Context, tx
 				u.username,
 				u.password,
 				u.role,
+				u.suspended_until IS NOT NULL AND u.suspended_until > NOW(),
 				u.created_at AS user_created_at,
 				u.updated_at AS user_updated_at,
 				p.user_id,
@@ -58,7 +59,7 @@ func (repository *AuthRepositoryImpl) GetUserByUsername(ctx context.Context, tx
 		var phoneNumber sql.NullString
 		var address sql.NullString
 
-		err := row.Scan(&user.Id, &user.Username, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		err := row.Scan(&user.Id, &user.Username, &user.Password, &user.Role, &user.IsSuspended, &user.CreatedAt, &user.UpdatedAt,
 			&user.Profile.UserId, &fullName, &gender, &birthDate, &phoneNumber, &address, &user.Profile.CreatedAt, &user.Profile.UpdatedAt)
 		helper.PanicIfErr(err)
 
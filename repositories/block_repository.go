@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type BlockRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, block entity.Block) entity.Block
+	Delete(ctx context.Context, tx *sql.Tx, blockerId int, blockedId int)
+	FindByID(ctx context.Context, tx *sql.Tx, blockerId int, blockedId int) (entity.Block, error)
+	IsBlocked(ctx context.Context, tx *sql.Tx, blockerId int, blockedId int) bool
+	FindBlockedUsers(ctx context.Context, tx *sql.Tx, blockerId int) []entity.BlockedUser
+}
+
+type BlockRepositoryImpl struct {
+}
+
+func NewBlockRepository() BlockRepository {
+	return &BlockRepositoryImpl{}
+}
+
+func (repository *BlockRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, block entity.Block) entity.Block {
+	SQL := `INSERT INTO user_blocks (blocker_id, blocked_id) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, block.BlockerId, block.BlockedId)
+	helper.PanicIfErr(err)
+
+	return block
+}
+
+func (repository *BlockRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, blockerId int, blockedId int) {
+	SQL := `DELETE FROM user_blocks WHERE blocker_id = ? AND blocked_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, blockerId, blockedId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *BlockRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, blockerId int, blockedId int) (entity.Block, error) {
+	SQL := `SELECT blocker_id, blocked_id, created_at FROM user_blocks WHERE blocker_id = ? AND blocked_id = ?`
+	row, err := tx.QueryContext(ctx, SQL, blockerId, blockedId)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var block entity.Block
+	if row.Next() {
+		err := row.Scan(&block.BlockerId, &block.BlockedId, &block.CreatedAt)
+		helper.PanicIfErr(err)
+		return block, nil
+	} else {
+		return block, errors.New("block not found")
+	}
+}
+
+// IsBlocked reports whether blockerId has blocked blockedId.
+func (repository *BlockRepositoryImpl) IsBlocked(ctx context.Context, tx *sql.Tx, blockerId int, blockedId int) bool {
+	SQL := `SELECT EXISTS (SELECT 1 FROM user_blocks WHERE blocker_id = ? AND blocked_id = ?)`
+	var blocked bool
+	err := tx.QueryRowContext(ctx, SQL, blockerId, blockedId).Scan(&blocked)
+	helper.PanicIfErr(err)
+	return blocked
+}
+
+func (repository *BlockRepositoryImpl) FindBlockedUsers(ctx context.Context, tx *sql.Tx, blockerId int) []entity.BlockedUser {
+	SQL := `SELECT
+				u.id,
+				u.username,
+				up.full_name,
+				b.created_at
+			FROM
+				user_blocks b
+			JOIN
+				users u ON b.blocked_id = u.id
+			LEFT JOIN
+				user_profiles up ON u.id = up.user_id
+			WHERE
+				b.blocker_id = ?
+			ORDER BY
+				b.created_at DESC`
+	rows, err := tx.QueryContext(ctx, SQL, blockerId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var users []entity.BlockedUser
+	for rows.Next() {
+		var user entity.BlockedUser
+		var fullName sql.NullString
+		err := rows.Scan(&user.UserId, &user.Username, &fullName, &user.BlockedAt)
+		helper.PanicIfErr(err)
+		user.FullName = helper.NullStringToString(fullName)
+		users = append(users, user)
+	}
+	return users
+}
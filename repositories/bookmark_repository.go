@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type BookmarkRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, bookmark entity.Bookmark) entity.Bookmark
+	Delete(ctx context.Context, tx *sql.Tx, userId int, articleId int)
+	FindByUserAndArticle(ctx context.Context, tx *sql.Tx, userId int, articleId int) (entity.Bookmark, error)
+	FindArticlesByUserID(ctx context.Context, tx *sql.Tx, userId int) []entity.Article
+	FindBookmarkedArticleIDs(ctx context.Context, tx *sql.Tx, userId int, articleIds []int) map[int]bool
+}
+
+type BookmarkRepositoryImpl struct {
+}
+
+func NewBookmarkRepository() BookmarkRepository {
+	return &BookmarkRepositoryImpl{}
+}
+
+func (repository *BookmarkRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, bookmark entity.Bookmark) entity.Bookmark {
+	SQL := `INSERT INTO bookmarks (user_id, article_id) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, bookmark.UserId, bookmark.ArticleId)
+	helper.PanicIfErr(err)
+
+	return bookmark
+}
+
+func (repository *BookmarkRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, userId int, articleId int) {
+	SQL := `DELETE FROM bookmarks WHERE user_id = ? AND article_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, userId, articleId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *BookmarkRepositoryImpl) FindByUserAndArticle(ctx context.Context, tx *sql.Tx, userId int, articleId int) (entity.Bookmark, error) {
+	SQL := `SELECT user_id, article_id, created_at FROM bookmarks WHERE user_id = ? AND article_id = ?`
+	row, err := tx.QueryContext(ctx, SQL, userId, articleId)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var bookmark entity.Bookmark
+	if row.Next() {
+		err := row.Scan(&bookmark.UserId, &bookmark.ArticleId, &bookmark.CreatedAt)
+		helper.PanicIfErr(err)
+		return bookmark, nil
+	} else {
+		return bookmark, errors.New("bookmark not found")
+	}
+}
+
+// FindArticlesByUserID returns the bookmarked articles that are still
+// published, most recently bookmarked first.
+func (repository *BookmarkRepositoryImpl) FindArticlesByUserID(ctx context.Context, tx *sql.Tx, userId int) []entity.Article {
+	SQL := `SELECT
+					a.id,
+					a.user_id,
+					a.title,
+					a.slug,
+					a.description,
+					a.content,
+					a.is_published,
+					a.version,
+					a.created_at,
+					a.updated_at,
+					up.full_name
+				FROM
+					bookmarks b
+				JOIN
+					articles a ON b.article_id = a.id
+				JOIN
+					user_profiles up ON a.user_id = up.user_id
+				WHERE
+					b.user_id = ?
+					AND a.is_published = true
+				ORDER BY
+					b.created_at DESC`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articles []entity.Article
+
+	for rows.Next() {
+		var article entity.Article
+		err := rows.Scan(&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		articles = append(articles, article)
+	}
+	return articles
+}
+
+// FindBookmarkedArticleIDs reports which of articleIds userId has bookmarked.
+func (repository *BookmarkRepositoryImpl) FindBookmarkedArticleIDs(ctx context.Context, tx *sql.Tx, userId int, articleIds []int) map[int]bool {
+	bookmarked := map[int]bool{}
+	if len(articleIds) == 0 {
+		return bookmarked
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(articleIds)), ",")
+	args := []any{userId}
+	for _, id := range articleIds {
+		args = append(args, id)
+	}
+
+	SQL := `SELECT article_id FROM bookmarks WHERE user_id = ? AND article_id IN (` + placeholders + `)`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId int
+		err := rows.Scan(&articleId)
+		helper.PanicIfErr(err)
+		bookmarked[articleId] = true
+	}
+	return bookmarked
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, category entity.Category) entity.Category
+	Update(ctx context.Context, tx *sql.Tx, category entity.Category) entity.Category
+	Delete(ctx context.Context, tx *sql.Tx, categoryId int)
+	FindByID(ctx context.Context, tx *sql.Tx, categoryId int) (entity.Category, error)
+	FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Category, error)
+	FindAll(ctx context.Context, tx *sql.Tx) []entity.Category
+	FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int) []entity.Category
+	AddToArticle(ctx context.Context, tx *sql.Tx, articleId int, categoryId int)
+	DeleteFromArticle(ctx context.Context, tx *sql.Tx, articleId int)
+}
+
+type CategoryRepositoryImpl struct {
+}
+
+func NewCategoryRepository() CategoryRepository {
+	return &CategoryRepositoryImpl{}
+}
+
+func (repository *CategoryRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, category entity.Category) entity.Category {
+	SQL := `INSERT INTO categories (parent_id, name, slug) VALUES (?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, nullableId(category.ParentId), category.Name, category.Slug)
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+
+	category.Id = int(id)
+	return category
+}
+
+func (repository *CategoryRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, category entity.Category) entity.Category {
+	SQL := `UPDATE categories SET parent_id = ?, name = ?, slug = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, nullableId(category.ParentId), category.Name, category.Slug, category.Id)
+	helper.PanicIfErr(err)
+
+	return category
+}
+
+func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, categoryId int) {
+	SQL := `DELETE FROM categories WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, categoryId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *CategoryRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, categoryId int) (entity.Category, error) {
+	SQL := `SELECT id, parent_id, name, slug, created_at, updated_at FROM categories WHERE id = ?`
+	row, err := tx.QueryContext(ctx, SQL, categoryId)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var category entity.Category
+	if row.Next() {
+		var parentId sql.NullInt64
+		err := row.Scan(&category.Id, &parentId, &category.Name, &category.Slug, &category.CreatedAt, &category.UpdatedAt)
+		helper.PanicIfErr(err)
+		category.ParentId = int(parentId.Int64)
+		return category, nil
+	} else {
+		return category, errors.New("category not found")
+	}
+}
+
+func (repository *CategoryRepositoryImpl) FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Category, error) {
+	SQL := `SELECT id, parent_id, name, slug, created_at, updated_at FROM categories WHERE slug = ?`
+	row, err := tx.QueryContext(ctx, SQL, slug)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var category entity.Category
+	if row.Next() {
+		var parentId sql.NullInt64
+		err := row.Scan(&category.Id, &parentId, &category.Name, &category.Slug, &category.CreatedAt, &category.UpdatedAt)
+		helper.PanicIfErr(err)
+		category.ParentId = int(parentId.Int64)
+		return category, nil
+	} else {
+		return category, errors.New("category not found")
+	}
+}
+
+// FindAll returns every category with the number of published articles
+// linked directly to it (not including subcategories).
+func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []entity.Category {
+	SQL := `SELECT 
+				c.id,
+				c.parent_id,
+				c.name,
+				c.slug,
+				COUNT(a.id) AS article_count,
+				c.created_at,
+				c.updated_at
+			FROM 
+				categories c
+			LEFT JOIN 
+				article_categories ac ON c.id = ac.category_id
+			LEFT JOIN 
+				articles a ON ac.article_id = a.id AND a.is_published = true
+			GROUP BY 
+				c.id
+			ORDER BY 
+				c.name`
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var categories []entity.Category
+	for rows.Next() {
+		var category entity.Category
+		var parentId sql.NullInt64
+		err := rows.Scan(&category.Id, &parentId, &category.Name, &category.Slug, &category.ArticleCount, &category.CreatedAt, &category.UpdatedAt)
+		helper.PanicIfErr(err)
+		category.ParentId = int(parentId.Int64)
+		categories = append(categories, category)
+	}
+	return categories
+}
+
+func (repository *CategoryRepositoryImpl) FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int) []entity.Category {
+	SQL := `SELECT c.id, c.parent_id, c.name, c.slug, c.created_at, c.updated_at 
+			FROM categories c 
+			JOIN article_categories ac ON c.id = ac.category_id 
+			WHERE ac.article_id = ? 
+			ORDER BY c.name`
+	rows, err := tx.QueryContext(ctx, SQL, articleId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var categories []entity.Category
+	for rows.Next() {
+		var category entity.Category
+		var parentId sql.NullInt64
+		err := rows.Scan(&category.Id, &parentId, &category.Name, &category.Slug, &category.CreatedAt, &category.UpdatedAt)
+		helper.PanicIfErr(err)
+		category.ParentId = int(parentId.Int64)
+		categories = append(categories, category)
+	}
+	return categories
+}
+
+func (repository *CategoryRepositoryImpl) AddToArticle(ctx context.Context, tx *sql.Tx, articleId int, categoryId int) {
+	SQL := `INSERT IGNORE INTO article_categories (article_id, category_id) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, articleId, categoryId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *CategoryRepositoryImpl) DeleteFromArticle(ctx context.Context, tx *sql.Tx, articleId int) {
+	SQL := `DELETE FROM article_categories WHERE article_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, articleId)
+	helper.PanicIfErr(err)
+}
+
+// nullableId stores 0 as NULL for optional foreign keys.
+func nullableId(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
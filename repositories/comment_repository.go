@@ -11,7 +11,13 @@ import (
 type CommentRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, comment entity.Comment) entity.Comment
 	FindByID(ctx context.Context, tx *sql.Tx, commentId int) (entity.Comment, error)
-	FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int) []entity.Comment
+	FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int, includeHidden bool) []entity.Comment
+	SetHidden(ctx context.Context, tx *sql.Tx, commentId int, hidden bool)
+	Update(ctx context.Context, tx *sql.Tx, comment entity.Comment) entity.Comment
+	CreateRevision(ctx context.Context, tx *sql.Tx, revision entity.CommentRevision)
+	FindRevisionsByCommentID(ctx context.Context, tx *sql.Tx, commentId int) []entity.CommentRevision
+	CountReplies(ctx context.Context, tx *sql.Tx, commentId int) int
+	SoftDelete(ctx context.Context, tx *sql.Tx, commentId int)
 	Delete(ctx context.Context, tx *sql.Tx, commentId int, userId int)
 }
 
@@ -23,8 +29,8 @@ func NewCommentRepository() CommentRepository {
 }
 
 func (c CommentRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, comment entity.Comment) entity.Comment {
-	SQL := `INSERT INTO comments (user_id, article_id, comment) VALUES (?,?,?)`
-	result, err := tx.ExecContext(ctx, SQL, comment.UserId, comment.ArticleId, comment.Comment)
+	SQL := `INSERT INTO comments (user_id, article_id, parent_id, depth, comment, is_hidden) VALUES (?,?,?,?,?,?)`
+	result, err := tx.ExecContext(ctx, SQL, comment.UserId, comment.ArticleId, nullableId(comment.ParentId), comment.Depth, comment.Comment, comment.IsHidden)
 	helper.PanicIfErr(err)
 
 	id, err := result.LastInsertId()
@@ -36,29 +42,52 @@ func (c CommentRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, comment e
 }
 
 func (c CommentRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, commentId int) (entity.Comment, error) {
-	SQL := `SELECT id, user_id, article_id, comment, created_at, updated_at FROM comments WHERE id = ?`
-	row, err := tx.QueryContext(ctx, SQL, commentId)
-	helper.PanicIfErr(err)
-	defer row.Close()
-
-	var comment entity.Comment
-	if row.Next() {
-		err := row.Scan(&comment.Id, &comment.UserId, &comment.ArticleId, &comment.Comment, &comment.CreatedAt, &comment.UpdatedAt)
-		helper.PanicIfErr(err)
-		return comment, nil
-	} else {
-		return comment, errors.New("comment not found")
+	SQL := `SELECT 
+				comments.id,
+				comments.user_id,
+				comments.article_id,
+				comments.parent_id,
+				comments.depth,
+				comments.comment,
+				comments.is_hidden,
+				comments.deleted_at IS NOT NULL,
+				(SELECT COUNT(*) FROM comments replies WHERE replies.parent_id = comments.id),
+				comments.created_at,
+				comments.updated_at,
+				comments.edited_at,
+				user_profiles.full_name
+			FROM 
+				comments
+			JOIN 
+				user_profiles
+			ON 
+				comments.user_id = user_profiles.user_id
+			WHERE 
+				comments.id = ?`
+	comments := c.findAll(ctx, tx, SQL, commentId)
+	if len(comments) == 0 {
+		return entity.Comment{}, errors.New("comment not found")
 	}
+	return comments[0], nil
 }
 
-func (c CommentRepositoryImpl) FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int) []entity.Comment {
+// FindByArticleID returns the comments of an article as a flat list, oldest
+// first. Replies point at their parent through ParentId. Hidden comments are
+// left out, and not counted as replies, unless includeHidden is set.
+func (c CommentRepositoryImpl) FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int, includeHidden bool) []entity.Comment {
 	SQL := `SELECT 
 				comments.id,
 				comments.user_id,
 				comments.article_id,
+				comments.parent_id,
+				comments.depth,
 				comments.comment,
+				comments.is_hidden,
+				comments.deleted_at IS NOT NULL,
+				(SELECT COUNT(*) FROM comments replies WHERE replies.parent_id = comments.id AND (? OR replies.is_hidden = false)),
 				comments.created_at,
 				comments.updated_at,
+				comments.edited_at,
 				user_profiles.full_name
 			FROM 
 				comments
@@ -67,22 +96,86 @@ func (c CommentRepositoryImpl) FindByArticleID(ctx context.Context, tx *sql.Tx,
 			ON 
 				comments.user_id = user_profiles.user_id
 			WHERE 
-				comments.article_id = ?;
-`
-	rows, err := tx.QueryContext(ctx, SQL, articleId)
+				comments.article_id = ?
+				AND (? OR comments.is_hidden = false)
+			ORDER BY
+				comments.created_at, comments.id`
+	return c.findAll(ctx, tx, SQL, includeHidden, articleId, includeHidden)
+}
+
+func (c CommentRepositoryImpl) findAll(ctx context.Context, tx *sql.Tx, SQL string, args ...any) []entity.Comment {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
 	helper.PanicIfErr(err)
 	defer rows.Close()
 
 	var comments []entity.Comment
 	for rows.Next() {
 		var comment entity.Comment
-		err := rows.Scan(&comment.Id, &comment.UserId, &comment.ArticleId, &comment.Comment, &comment.CreatedAt, &comment.UpdatedAt, &comment.Author)
+		var parentId sql.NullInt64
+		var text, editedAt sql.NullString
+		err := rows.Scan(&comment.Id, &comment.UserId, &comment.ArticleId, &parentId, &comment.Depth, &text, &comment.IsHidden, &comment.IsDeleted, &comment.ReplyCount, &comment.CreatedAt, &comment.UpdatedAt, &editedAt, &comment.Author)
 		helper.PanicIfErr(err)
+		comment.ParentId = int(parentId.Int64)
+		comment.Comment = helper.NullStringToString(text)
+		comment.EditedAt = helper.NullStringToString(editedAt)
 		comments = append(comments, comment)
 	}
 	return comments
 }
 
+func (c CommentRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, comment entity.Comment) entity.Comment {
+	SQL := `UPDATE comments SET comment = ?, edited_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, comment.Comment, comment.Id)
+	helper.PanicIfErr(err)
+
+	return comment
+}
+
+func (c CommentRepositoryImpl) CreateRevision(ctx context.Context, tx *sql.Tx, revision entity.CommentRevision) {
+	SQL := `INSERT INTO comment_revisions (comment_id, comment) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, revision.CommentId, revision.Comment)
+	helper.PanicIfErr(err)
+}
+
+// FindRevisionsByCommentID returns the replaced versions of a comment, oldest first.
+func (c CommentRepositoryImpl) FindRevisionsByCommentID(ctx context.Context, tx *sql.Tx, commentId int) []entity.CommentRevision {
+	SQL := `SELECT id, comment_id, comment, created_at FROM comment_revisions WHERE comment_id = ? ORDER BY id`
+	rows, err := tx.QueryContext(ctx, SQL, commentId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var revisions []entity.CommentRevision
+	for rows.Next() {
+		var revision entity.CommentRevision
+		err := rows.Scan(&revision.Id, &revision.CommentId, &revision.Comment, &revision.CreatedAt)
+		helper.PanicIfErr(err)
+		revisions = append(revisions, revision)
+	}
+	return revisions
+}
+
+func (c CommentRepositoryImpl) CountReplies(ctx context.Context, tx *sql.Tx, commentId int) int {
+	SQL := `SELECT COUNT(*) FROM comments WHERE parent_id = ?`
+	var count int
+	err := tx.QueryRowContext(ctx, SQL, commentId).Scan(&count)
+	helper.PanicIfErr(err)
+	return count
+}
+
+func (c CommentRepositoryImpl) SetHidden(ctx context.Context, tx *sql.Tx, commentId int, hidden bool) {
+	SQL := `UPDATE comments SET is_hidden = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, hidden, commentId)
+	helper.PanicIfErr(err)
+}
+
+// SoftDelete clears the text of a comment but keeps the row so its replies
+// stay attached to the thread.
+func (c CommentRepositoryImpl) SoftDelete(ctx context.Context, tx *sql.Tx, commentId int) {
+	SQL := `UPDATE comments SET comment = NULL, deleted_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, commentId)
+	helper.PanicIfErr(err)
+}
+
 func (c CommentRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, commentId int, userId int) {
 	SQL := `DELETE FROM comments WHERE id = ? and user_id = ?`
 	_, err := tx.ExecContext(ctx, SQL, commentId, userId)
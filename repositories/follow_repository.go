@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type FollowRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, follow entity.Follow) entity.Follow
+	Delete(ctx context.Context, tx *sql.Tx, followerId int, followeeId int)
+	FindByFollowerAndFollowee(ctx context.Context, tx *sql.Tx, followerId int, followeeId int) (entity.Follow, error)
+	FindFollowers(ctx context.Context, tx *sql.Tx, userId int) []entity.FollowUser
+	FindFollowerIDs(ctx context.Context, tx *sql.Tx, userId int) []int
+	FindFollowing(ctx context.Context, tx *sql.Tx, userId int) []entity.FollowUser
+}
+
+type FollowRepositoryImpl struct {
+}
+
+func NewFollowRepository() FollowRepository {
+	return &FollowRepositoryImpl{}
+}
+
+func (repository *FollowRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, follow entity.Follow) entity.Follow {
+	SQL := `INSERT INTO follows (follower_id, followee_id) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, follow.FollowerId, follow.FolloweeId)
+	helper.PanicIfErr(err)
+
+	return follow
+}
+
+func (repository *FollowRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, followerId int, followeeId int) {
+	SQL := `DELETE FROM follows WHERE follower_id = ? AND followee_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, followerId, followeeId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *FollowRepositoryImpl) FindByFollowerAndFollowee(ctx context.Context, tx *sql.Tx, followerId int, followeeId int) (entity.Follow, error) {
+	SQL := `SELECT follower_id, followee_id, created_at FROM follows WHERE follower_id = ? AND followee_id = ?`
+	row, err := tx.QueryContext(ctx, SQL, followerId, followeeId)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var follow entity.Follow
+	if row.Next() {
+		err := row.Scan(&follow.FollowerId, &follow.FolloweeId, &follow.CreatedAt)
+		helper.PanicIfErr(err)
+		return follow, nil
+	} else {
+		return follow, errors.New("follow not found")
+	}
+}
+
+func (repository *FollowRepositoryImpl) FindFollowers(ctx context.Context, tx *sql.Tx, userId int) []entity.FollowUser {
+	SQL := `SELECT
+				u.id,
+				u.username,
+				up.full_name,
+				f.created_at
+			FROM
+				follows f
+			JOIN
+				users u ON f.follower_id = u.id
+			LEFT JOIN
+				user_profiles up ON u.id = up.user_id
+			WHERE
+				f.followee_id = ?
+			ORDER BY
+				f.created_at DESC`
+	return repository.findUsers(ctx, tx, SQL, userId)
+}
+
+func (repository *FollowRepositoryImpl) FindFollowerIDs(ctx context.Context, tx *sql.Tx, userId int) []int {
+	SQL := `SELECT follower_id FROM follows WHERE followee_id = ?`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var followerIds []int
+	for rows.Next() {
+		var followerId int
+		err := rows.Scan(&followerId)
+		helper.PanicIfErr(err)
+		followerIds = append(followerIds, followerId)
+	}
+	return followerIds
+}
+
+func (repository *FollowRepositoryImpl) FindFollowing(ctx context.Context, tx *sql.Tx, userId int) []entity.FollowUser {
+	SQL := `SELECT
+				u.id,
+				u.username,
+				up.full_name,
+				f.created_at
+			FROM
+				follows f
+			JOIN
+				users u ON f.followee_id = u.id
+			LEFT JOIN
+				user_profiles up ON u.id = up.user_id
+			WHERE
+				f.follower_id = ?
+			ORDER BY
+				f.created_at DESC`
+	return repository.findUsers(ctx, tx, SQL, userId)
+}
+
+func (repository *FollowRepositoryImpl) findUsers(ctx context.Context, tx *sql.Tx, SQL string, userId int) []entity.FollowUser {
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var users []entity.FollowUser
+	for rows.Next() {
+		var user entity.FollowUser
+		var fullName sql.NullString
+		err := rows.Scan(&user.UserId, &user.Username, &fullName, &user.FollowedAt)
+		helper.PanicIfErr(err)
+		user.FullName = helper.NullStringToString(fullName)
+		users = append(users, user)
+	}
+	return users
+}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"uaspw2/helper"
 	"uaspw2/models/entity"
 )
 
+// LikeRepository stores reactions to articles. A like is the "like" reaction;
+// a user can leave several different reactions on the same article.
 type LikeRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, like entity.Like) entity.Like
-	FindByArticleAndUser(ctx context.Context, tx *sql.Tx, articleId int, userId int) (entity.Like, error)
-	FindByArticleId(ctx context.Context, tx *sql.Tx, articleId int) []entity.Like
-	FindByUserId(ctx context.Context, tx *sql.Tx, userId int) []entity.Like
-	Delete(ctx context.Context, tx *sql.Tx, articleId int, userId int)
+	FindByArticleAndUser(ctx context.Context, tx *sql.Tx, articleId int, userId int, reaction string) (entity.Like, error)
+	FindByArticleId(ctx context.Context, tx *sql.Tx, articleId int, reaction string, beforeCreatedAt string, beforeId int, limit int) []entity.Like
+	FindByUserId(ctx context.Context, tx *sql.Tx, userId int, reaction string, beforeCreatedAt string, beforeId int, limit int) []entity.Like
+	FindReactionsByArticleAndUser(ctx context.Context, tx *sql.Tx, articleId int, userId int) []string
+	FindLikedArticleIDs(ctx context.Context, tx *sql.Tx, userId int, articleIds []int) map[int]bool
+	Delete(ctx context.Context, tx *sql.Tx, articleId int, userId int, reaction string)
+	CountByArticleID(ctx context.Context, tx *sql.Tx, articleId int, reaction string) int
 }
 
 type LikeRepositoryImpl struct {
@@ -23,15 +29,15 @@ func NewLikeRepository() LikeRepository {
 	return &LikeRepositoryImpl{}
 }
 
-func (repository *LikeRepositoryImpl) FindByArticleAndUser(ctx context.Context, tx *sql.Tx, articleId int, userId int) (entity.Like, error) {
-	SQL := `SELECT id, user_id, article_id, created_at, updated_at FROM likes WHERE article_id = ? AND user_id = ?`
-	row, err := tx.QueryContext(ctx, SQL, articleId, userId)
+func (repository *LikeRepositoryImpl) FindByArticleAndUser(ctx context.Context, tx *sql.Tx, articleId int, userId int, reaction string) (entity.Like, error) {
+	SQL := `SELECT id, user_id, article_id, reaction, created_at, updated_at FROM likes WHERE article_id = ? AND user_id = ? AND reaction = ?`
+	row, err := tx.QueryContext(ctx, SQL, articleId, userId, reaction)
 	helper.PanicIfErr(err)
 	defer row.Close()
 
 	var like entity.Like
 	if row.Next() {
-		err := row.Scan(&like.Id, &like.UserId, &like.ArticleId, &like.CreatedAt, &like.UpdatedAt)
+		err := row.Scan(&like.Id, &like.UserId, &like.ArticleId, &like.Reaction, &like.CreatedAt, &like.UpdatedAt)
 		helper.PanicIfErr(err)
 		return like, nil
 	} else {
@@ -41,8 +47,8 @@ func (repository *LikeRepositoryImpl) FindByArticleAndUser(ctx context.Context,
 }
 
 func (repository *LikeRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, like entity.Like) entity.Like {
-	SQL := "INSERT INTO likes (user_id, article_id) VALUES (?, ?)"
-	result, err := tx.ExecContext(ctx, SQL, like.UserId, like.ArticleId)
+	SQL := "INSERT INTO likes (user_id, article_id, reaction) VALUES (?, ?, ?)"
+	result, err := tx.ExecContext(ctx, SQL, like.UserId, like.ArticleId, like.Reaction)
 	helper.PanicIfErr(err)
 
 	id, err := result.LastInsertId()
@@ -53,44 +59,153 @@ func (repository *LikeRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, li
 	return like
 }
 
-func (repository *LikeRepositoryImpl) FindByArticleId(ctx context.Context, tx *sql.Tx, articleId int) []entity.Like {
-	SQL := "SELECT id, article_id, user_id, created_at, updated_at FROM likes WHERE article_id = ?"
-	rows, err := tx.QueryContext(ctx, SQL, articleId)
+// FindByArticleId returns the likes of an article with the profile of each
+// liker, newest first. An empty beforeCreatedAt starts at the newest like,
+// otherwise only those ordered after (beforeCreatedAt, beforeId) are read.
+func (repository *LikeRepositoryImpl) FindByArticleId(ctx context.Context, tx *sql.Tx, articleId int, reaction string, beforeCreatedAt string, beforeId int, limit int) []entity.Like {
+	SQL := `SELECT
+				l.id,
+				l.article_id,
+				l.user_id,
+				l.reaction,
+				l.created_at,
+				l.updated_at,
+				u.username,
+				up.full_name,
+				upp.path
+			FROM
+				likes l
+			JOIN
+				users u ON l.user_id = u.id
+			LEFT JOIN
+				user_profiles up ON l.user_id = up.user_id
+			LEFT JOIN
+				user_profile_photos upp ON l.user_id = upp.user_id
+			WHERE
+				l.article_id = ?
+				AND l.reaction = ?
+				AND (? = '' OR l.created_at < ? OR (l.created_at = ? AND l.id < ?))
+			ORDER BY
+				l.created_at DESC, l.id DESC
+			LIMIT ?`
+	rows, err := tx.QueryContext(ctx, SQL, articleId, reaction, beforeCreatedAt, beforeCreatedAt, beforeCreatedAt, beforeId, limit)
 	helper.PanicIfErr(err)
 	defer rows.Close()
 
 	var likes []entity.Like
-
 	for rows.Next() {
 		var like entity.Like
-		err := rows.Scan(&like.Id, &like.ArticleId, &like.UserId, &like.CreatedAt, &like.UpdatedAt)
+		var user entity.LikeUser
+		var fullName, photoPath sql.NullString
+		err := rows.Scan(&like.Id, &like.ArticleId, &like.UserId, &like.Reaction, &like.CreatedAt, &like.UpdatedAt, &user.Username, &fullName, &photoPath)
 		helper.PanicIfErr(err)
+		user.FullName = helper.NullStringToString(fullName)
+		user.PhotoPath = helper.NullStringToString(photoPath)
+		like.User = &user
 		likes = append(likes, like)
 	}
-
 	return likes
 }
 
-func (repository *LikeRepositoryImpl) FindByUserId(ctx context.Context, tx *sql.Tx, userId int) []entity.Like {
-	SQL := "SELECT id, article_id, user_id, created_at, updated_at FROM likes WHERE user_id = ?"
-	rows, err := tx.QueryContext(ctx, SQL, userId)
+// FindByUserId returns the likes of a user on published articles with a
+// summary of each article, newest first. Paging works as in FindByArticleId.
+func (repository *LikeRepositoryImpl) FindByUserId(ctx context.Context, tx *sql.Tx, userId int, reaction string, beforeCreatedAt string, beforeId int, limit int) []entity.Like {
+	SQL := `SELECT
+				l.id,
+				l.article_id,
+				l.user_id,
+				l.reaction,
+				l.created_at,
+				l.updated_at,
+				a.title,
+				a.slug,
+				up.full_name
+			FROM
+				likes l
+			JOIN
+				articles a ON l.article_id = a.id
+			LEFT JOIN
+				user_profiles up ON a.user_id = up.user_id
+			WHERE
+				l.user_id = ?
+				AND l.reaction = ?
+				AND a.is_published = true
+				AND (? = '' OR l.created_at < ? OR (l.created_at = ? AND l.id < ?))
+			ORDER BY
+				l.created_at DESC, l.id DESC
+			LIMIT ?`
+	rows, err := tx.QueryContext(ctx, SQL, userId, reaction, beforeCreatedAt, beforeCreatedAt, beforeCreatedAt, beforeId, limit)
 	helper.PanicIfErr(err)
 	defer rows.Close()
 
 	var likes []entity.Like
-
 	for rows.Next() {
 		var like entity.Like
-		err := rows.Scan(&like.Id, &like.ArticleId, &like.UserId, &like.CreatedAt, &like.UpdatedAt)
+		var article entity.LikeArticle
+		var author sql.NullString
+		err := rows.Scan(&like.Id, &like.ArticleId, &like.UserId, &like.Reaction, &like.CreatedAt, &like.UpdatedAt, &article.Title, &article.Slug, &author)
 		helper.PanicIfErr(err)
+		article.Author = helper.NullStringToString(author)
+		like.Article = &article
 		likes = append(likes, like)
 	}
-
 	return likes
 }
 
-func (repository *LikeRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, articleId int, userId int) {
-	SQL := `DELETE FROM likes WHERE article_id = ? AND user_id = ?`
-	_, err := tx.ExecContext(ctx, SQL, articleId, userId)
+// FindReactionsByArticleAndUser lists the reactions userId left on articleId.
+func (repository *LikeRepositoryImpl) FindReactionsByArticleAndUser(ctx context.Context, tx *sql.Tx, articleId int, userId int) []string {
+	SQL := `SELECT reaction FROM likes WHERE article_id = ? AND user_id = ? ORDER BY created_at, id`
+	rows, err := tx.QueryContext(ctx, SQL, articleId, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	reactions := []string{}
+	for rows.Next() {
+		var reaction string
+		err := rows.Scan(&reaction)
+		helper.PanicIfErr(err)
+		reactions = append(reactions, reaction)
+	}
+	return reactions
+}
+
+// FindLikedArticleIDs reports which of articleIds userId has liked.
+func (repository *LikeRepositoryImpl) FindLikedArticleIDs(ctx context.Context, tx *sql.Tx, userId int, articleIds []int) map[int]bool {
+	liked := map[int]bool{}
+	if len(articleIds) == 0 {
+		return liked
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(articleIds)), ",")
+	args := []any{userId}
+	for _, id := range articleIds {
+		args = append(args, id)
+	}
+
+	SQL := `SELECT article_id FROM likes WHERE user_id = ? AND reaction = 'like' AND article_id IN (` + placeholders + `)`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId int
+		err := rows.Scan(&articleId)
+		helper.PanicIfErr(err)
+		liked[articleId] = true
+	}
+	return liked
+}
+
+func (repository *LikeRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, articleId int, userId int, reaction string) {
+	SQL := `DELETE FROM likes WHERE article_id = ? AND user_id = ? AND reaction = ?`
+	_, err := tx.ExecContext(ctx, SQL, articleId, userId, reaction)
+	helper.PanicIfErr(err)
+}
+
+func (repository *LikeRepositoryImpl) CountByArticleID(ctx context.Context, tx *sql.Tx, articleId int, reaction string) int {
+	SQL := `SELECT COUNT(*) FROM likes WHERE article_id = ? AND reaction = ?`
+	var count int
+	err := tx.QueryRowContext(ctx, SQL, articleId, reaction).Scan(&count)
 	helper.PanicIfErr(err)
+	return count
 }
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type MentionRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, mention entity.Mention)
+	Delete(ctx context.Context, tx *sql.Tx, mention entity.Mention)
+	DeleteByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int)
+	FindByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) []entity.Mention
+	FindByTargets(ctx context.Context, tx *sql.Tx, targetType string, targetIds []int) map[int][]entity.Mention
+	FindPending(ctx context.Context, tx *sql.Tx, targetType string, targetId int) []entity.Mention
+	MarkNotified(ctx context.Context, tx *sql.Tx, mention entity.Mention)
+	FindUsersByUsernames(ctx context.Context, tx *sql.Tx, usernames []string) []entity.User
+}
+
+type MentionRepositoryImpl struct {
+}
+
+func NewMentionRepository() MentionRepository {
+	return &MentionRepositoryImpl{}
+}
+
+func (repository *MentionRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, mention entity.Mention) {
+	SQL := `INSERT IGNORE INTO mentions (target_type, target_id, user_id) VALUES (?, ?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, mention.TargetType, mention.TargetId, mention.UserId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *MentionRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, mention entity.Mention) {
+	SQL := `DELETE FROM mentions WHERE target_type = ? AND target_id = ? AND user_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, mention.TargetType, mention.TargetId, mention.UserId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *MentionRepositoryImpl) DeleteByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) {
+	SQL := `DELETE FROM mentions WHERE target_type = ? AND target_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, targetType, targetId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *MentionRepositoryImpl) FindByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) []entity.Mention {
+	return repository.FindByTargets(ctx, tx, targetType, []int{targetId})[targetId]
+}
+
+// FindByTargets returns the mentions of several articles or comments at once,
+// keyed by target id.
+func (repository *MentionRepositoryImpl) FindByTargets(ctx context.Context, tx *sql.Tx, targetType string, targetIds []int) map[int][]entity.Mention {
+	mentions := map[int][]entity.Mention{}
+	if len(targetIds) == 0 {
+		return mentions
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(targetIds)), ",")
+	args := []any{targetType}
+	for _, id := range targetIds {
+		args = append(args, id)
+	}
+
+	SQL := `SELECT m.target_type, m.target_id, m.user_id, u.username
+			FROM mentions m
+			JOIN users u ON m.user_id = u.id
+			WHERE m.target_type = ? AND m.target_id IN (` + placeholders + `)
+			ORDER BY u.username`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var mention entity.Mention
+		err := rows.Scan(&mention.TargetType, &mention.TargetId, &mention.UserId, &mention.Username)
+		helper.PanicIfErr(err)
+		mentions[mention.TargetId] = append(mentions[mention.TargetId], mention)
+	}
+	return mentions
+}
+
+// FindPending returns the mentions of a target whose user was not notified yet.
+func (repository *MentionRepositoryImpl) FindPending(ctx context.Context, tx *sql.Tx, targetType string, targetId int) []entity.Mention {
+	SQL := `SELECT m.target_type, m.target_id, m.user_id, u.username
+			FROM mentions m
+			JOIN users u ON m.user_id = u.id
+			WHERE m.target_type = ? AND m.target_id = ? AND m.notified_at IS NULL`
+	rows, err := tx.QueryContext(ctx, SQL, targetType, targetId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var mentions []entity.Mention
+	for rows.Next() {
+		var mention entity.Mention
+		err := rows.Scan(&mention.TargetType, &mention.TargetId, &mention.UserId, &mention.Username)
+		helper.PanicIfErr(err)
+		mentions = append(mentions, mention)
+	}
+	return mentions
+}
+
+func (repository *MentionRepositoryImpl) MarkNotified(ctx context.Context, tx *sql.Tx, mention entity.Mention) {
+	SQL := `UPDATE mentions SET notified_at = CURRENT_TIMESTAMP WHERE target_type = ? AND target_id = ? AND user_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, mention.TargetType, mention.TargetId, mention.UserId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *MentionRepositoryImpl) FindUsersByUsernames(ctx context.Context, tx *sql.Tx, usernames []string) []entity.User {
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(usernames)), ",")
+	var args []any
+	for _, username := range usernames {
+		args = append(args, username)
+	}
+
+	SQL := `SELECT id, username, role, created_at, updated_at FROM users WHERE username IN (` + placeholders + `)`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		var user entity.User
+		err := rows.Scan(&user.Id, &user.Username, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+		helper.PanicIfErr(err)
+		users = append(users, user)
+	}
+	return users
+}
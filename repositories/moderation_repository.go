@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, tx *sql.Tx, report entity.Report) entity.Report
+	HasOpenReport(ctx context.Context, tx *sql.Tx, reporterId int, targetType string, targetId int) bool
+	FindOpenReports(ctx context.Context, tx *sql.Tx) []entity.Report
+	FindOpenReportsByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) []entity.Report
+	ResolveReports(ctx context.Context, tx *sql.Tx, targetType string, targetId int, status string)
+	CreateAction(ctx context.Context, tx *sql.Tx, action entity.ModerationAction) entity.ModerationAction
+	FindActions(ctx context.Context, tx *sql.Tx, userId int) []entity.ModerationAction
+}
+
+type ModerationRepositoryImpl struct {
+}
+
+func NewModerationRepository() ModerationRepository {
+	return &ModerationRepositoryImpl{}
+}
+
+func (repository *ModerationRepositoryImpl) CreateReport(ctx context.Context, tx *sql.Tx, report entity.Report) entity.Report {
+	SQL := `INSERT INTO reports (reporter_id, target_type, target_id, reason, details) VALUES (?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, nullableId(report.ReporterId), report.TargetType, report.TargetId, report.Reason, report.Details)
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+	report.Id = int(id)
+	report.Status = "open"
+
+	return report
+}
+
+func (repository *ModerationRepositoryImpl) HasOpenReport(ctx context.Context, tx *sql.Tx, reporterId int, targetType string, targetId int) bool {
+	SQL := `SELECT EXISTS (SELECT 1 FROM reports WHERE reporter_id = ? AND target_type = ? AND target_id = ? AND status = 'open')`
+	var exists bool
+	err := tx.QueryRowContext(ctx, SQL, reporterId, targetType, targetId).Scan(&exists)
+	helper.PanicIfErr(err)
+	return exists
+}
+
+// FindOpenReports returns every open report, oldest first. Reports raised by
+// the content filter have no reporter.
+func (repository *ModerationRepositoryImpl) FindOpenReports(ctx context.Context, tx *sql.Tx) []entity.Report {
+	return repository.findReports(ctx, tx, "r.status = 'open'")
+}
+
+func (repository *ModerationRepositoryImpl) FindOpenReportsByTarget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) []entity.Report {
+	return repository.findReports(ctx, tx, "r.status = 'open' AND r.target_type = ? AND r.target_id = ?", targetType, targetId)
+}
+
+func (repository *ModerationRepositoryImpl) findReports(ctx context.Context, tx *sql.Tx, condition string, args ...any) []entity.Report {
+	SQL := `SELECT
+				r.id,
+				r.reporter_id,
+				u.username,
+				r.target_type,
+				r.target_id,
+				r.reason,
+				r.details,
+				r.status,
+				r.created_at,
+				r.updated_at
+			FROM
+				reports r
+			LEFT JOIN
+				users u ON r.reporter_id = u.id
+			WHERE
+				` + condition + `
+			ORDER BY
+				r.created_at, r.id`
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var reports []entity.Report
+	for rows.Next() {
+		var report entity.Report
+		var reporterId sql.NullInt64
+		var reporter, details sql.NullString
+		err := rows.Scan(&report.Id, &reporterId, &reporter, &report.TargetType, &report.TargetId, &report.Reason, &details, &report.Status, &report.CreatedAt, &report.UpdatedAt)
+		helper.PanicIfErr(err)
+		report.ReporterId = int(reporterId.Int64)
+		report.Reporter = helper.NullStringToString(reporter)
+		report.Details = helper.NullStringToString(details)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func (repository *ModerationRepositoryImpl) ResolveReports(ctx context.Context, tx *sql.Tx, targetType string, targetId int, status string) {
+	SQL := `UPDATE reports SET status = ? WHERE target_type = ? AND target_id = ? AND status = 'open'`
+	_, err := tx.ExecContext(ctx, SQL, status, targetType, targetId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *ModerationRepositoryImpl) CreateAction(ctx context.Context, tx *sql.Tx, action entity.ModerationAction) entity.ModerationAction {
+	SQL := `INSERT INTO moderation_actions (moderator_id, target_type, target_id, user_id, action, note, suspend_days) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, action.ModeratorId, action.TargetType, action.TargetId, nullableId(action.UserId), action.Action, action.Note, action.SuspendDays)
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+	action.Id = int(id)
+
+	return action
+}
+
+// FindActions returns the recorded moderation actions, newest first. A
+// non-zero userId limits them to actions taken against that user.
+func (repository *ModerationRepositoryImpl) FindActions(ctx context.Context, tx *sql.Tx, userId int) []entity.ModerationAction {
+	SQL := `SELECT id, moderator_id, target_type, target_id, user_id, action, note, suspend_days, created_at
+			FROM moderation_actions
+			WHERE ? = 0 OR user_id = ?
+			ORDER BY created_at DESC, id DESC`
+	rows, err := tx.QueryContext(ctx, SQL, userId, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var actions []entity.ModerationAction
+	for rows.Next() {
+		var action entity.ModerationAction
+		var actionUserId sql.NullInt64
+		var note sql.NullString
+		err := rows.Scan(&action.Id, &action.ModeratorId, &action.TargetType, &action.TargetId, &actionUserId, &action.Action, &note, &action.SuspendDays, &action.CreatedAt)
+		helper.PanicIfErr(err)
+		action.UserId = int(actionUserId.Int64)
+		action.Note = helper.NullStringToString(note)
+		actions = append(actions, action)
+	}
+	return actions
+}
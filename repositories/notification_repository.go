@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, notification entity.Notification) entity.Notification
+	FindByID(ctx context.Context, tx *sql.Tx, id int) (entity.Notification, error)
+	FindByUserID(ctx context.Context, tx *sql.Tx, userId int, unreadOnly bool, beforeCreatedAt string, beforeId int, limit int) []entity.Notification
+	MarkRead(ctx context.Context, tx *sql.Tx, id int, read bool)
+	MarkAllRead(ctx context.Context, tx *sql.Tx, userId int)
+	CountUnread(ctx context.Context, tx *sql.Tx, userId int) map[string]int
+	HasRecent(ctx context.Context, tx *sql.Tx, notification entity.Notification, types []string, window time.Duration) bool
+	IsMuted(ctx context.Context, tx *sql.Tx, userId int, notificationType string) bool
+	FindPreferences(ctx context.Context, tx *sql.Tx, userId int) []entity.NotificationPreference
+	SavePreference(ctx context.Context, tx *sql.Tx, preference entity.NotificationPreference)
+}
+
+type NotificationRepositoryImpl struct {
+}
+
+func NewNotificationRepository() NotificationRepository {
+	return &NotificationRepositoryImpl{}
+}
+
+func (repository *NotificationRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, notification entity.Notification) entity.Notification {
+	SQL := `INSERT INTO notifications (user_id, actor_id, type, target_type, target_id, article_id) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, notification.UserId, nullableId(notification.ActorId), notification.Type, notification.TargetType, notification.TargetId, nullableId(notification.ArticleId))
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+	notification.Id = int(id)
+
+	return notification
+}
+
+func (repository *NotificationRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, id int) (entity.Notification, error) {
+	SQL := `SELECT
+				n.id,
+				n.user_id,
+				n.actor_id,
+				u.username,
+				n.type,
+				n.target_type,
+				n.target_id,
+				n.article_id,
+				n.read_at,
+				n.created_at
+			FROM
+				notifications n
+			LEFT JOIN
+				users u ON n.actor_id = u.id
+			WHERE
+				n.id = ?`
+	notifications := repository.findAll(ctx, tx, SQL, id)
+	if len(notifications) == 0 {
+		return entity.Notification{}, errors.New("notification not found")
+	}
+	return notifications[0], nil
+}
+
+// FindByUserID returns the notifications of a user, newest first, optionally
+// only the unread ones. An empty beforeCreatedAt starts at the newest
+// notification, otherwise only those ordered after (beforeCreatedAt, beforeId)
+// are read.
+func (repository *NotificationRepositoryImpl) FindByUserID(ctx context.Context, tx *sql.Tx, userId int, unreadOnly bool, beforeCreatedAt string, beforeId int, limit int) []entity.Notification {
+	SQL := `SELECT
+				n.id,
+				n.user_id,
+				n.actor_id,
+				u.username,
+				n.type,
+				n.target_type,
+				n.target_id,
+				n.article_id,
+				n.read_at,
+				n.created_at
+			FROM
+				notifications n
+			LEFT JOIN
+				users u ON n.actor_id = u.id
+			WHERE
+				n.user_id = ?
+				AND (? = false OR n.read_at IS NULL)
+				AND (? = '' OR n.created_at < ? OR (n.created_at = ? AND n.id < ?))
+			ORDER BY
+				n.created_at DESC, n.id DESC
+			LIMIT ?`
+	return repository.findAll(ctx, tx, SQL, userId, unreadOnly, beforeCreatedAt, beforeCreatedAt, beforeCreatedAt, beforeId, limit)
+}
+
+func (repository *NotificationRepositoryImpl) findAll(ctx context.Context, tx *sql.Tx, SQL string, args ...any) []entity.Notification {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var notifications []entity.Notification
+	for rows.Next() {
+		var notification entity.Notification
+		var actorId, articleId sql.NullInt64
+		var actor, readAt sql.NullString
+		err := rows.Scan(&notification.Id, &notification.UserId, &actorId, &actor, &notification.Type, &notification.TargetType, &notification.TargetId, &articleId, &readAt, &notification.CreatedAt)
+		helper.PanicIfErr(err)
+		notification.ActorId = int(actorId.Int64)
+		notification.Actor = helper.NullStringToString(actor)
+		notification.ArticleId = int(articleId.Int64)
+		notification.ReadAt = helper.NullStringToString(readAt)
+		notifications = append(notifications, notification)
+	}
+	return notifications
+}
+
+// MarkRead sets or clears the read time of a notification. A notification that
+// is already read keeps its original read time.
+func (repository *NotificationRepositoryImpl) MarkRead(ctx context.Context, tx *sql.Tx, id int, read bool) {
+	SQL := `UPDATE notifications SET read_at = NULL WHERE id = ?`
+	if read {
+		SQL = `UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP) WHERE id = ?`
+	}
+	_, err := tx.ExecContext(ctx, SQL, id)
+	helper.PanicIfErr(err)
+}
+
+func (repository *NotificationRepositoryImpl) MarkAllRead(ctx context.Context, tx *sql.Tx, userId int) {
+	SQL := `UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = ? AND read_at IS NULL`
+	_, err := tx.ExecContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+}
+
+// CountUnread returns the number of unread notifications of a user per type.
+func (repository *NotificationRepositoryImpl) CountUnread(ctx context.Context, tx *sql.Tx, userId int) map[string]int {
+	SQL := `SELECT type, COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL GROUP BY type`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var notificationType string
+		var count int
+		err := rows.Scan(&notificationType, &count)
+		helper.PanicIfErr(err)
+		counts[notificationType] = count
+	}
+	return counts
+}
+
+// HasRecent reports whether the recipient already got a notification of one
+// of types from the same actor about the same article within window.
+func (repository *NotificationRepositoryImpl) HasRecent(ctx context.Context, tx *sql.Tx, notification entity.Notification, types []string, window time.Duration) bool {
+	SQL := `SELECT 1 FROM notifications
+			WHERE user_id = ? AND actor_id <=> ? AND article_id <=> ?
+				AND type IN (` + strings.TrimSuffix(strings.Repeat("?,", len(types)), ",") + `)
+				AND created_at > NOW() - INTERVAL ? SECOND
+			LIMIT 1`
+	args := []any{notification.UserId, nullableId(notification.ActorId), nullableId(notification.ArticleId)}
+	for _, notificationType := range types {
+		args = append(args, notificationType)
+	}
+	args = append(args, int(window.Seconds()))
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	return rows.Next()
+}
+
+func (repository *NotificationRepositoryImpl) IsMuted(ctx context.Context, tx *sql.Tx, userId int, notificationType string) bool {
+	SQL := `SELECT 1 FROM notification_preferences WHERE user_id = ? AND type = ? AND is_muted = true`
+	rows, err := tx.QueryContext(ctx, SQL, userId, notificationType)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	return rows.Next()
+}
+
+// FindPreferences returns the stored preferences of a user. Types without a
+// stored preference are not muted.
+func (repository *NotificationRepositoryImpl) FindPreferences(ctx context.Context, tx *sql.Tx, userId int) []entity.NotificationPreference {
+	SQL := `SELECT user_id, type, is_muted FROM notification_preferences WHERE user_id = ?`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var preferences []entity.NotificationPreference
+	for rows.Next() {
+		var preference entity.NotificationPreference
+		err := rows.Scan(&preference.UserId, &preference.Type, &preference.IsMuted)
+		helper.PanicIfErr(err)
+		preferences = append(preferences, preference)
+	}
+	return preferences
+}
+
+func (repository *NotificationRepositoryImpl) SavePreference(ctx context.Context, tx *sql.Tx, preference entity.NotificationPreference) {
+	SQL := `INSERT INTO notification_preferences (user_id, type, is_muted) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE is_muted = VALUES(is_muted)`
+	_, err := tx.ExecContext(ctx, SQL, preference.UserId, preference.Type, preference.IsMuted)
+	helper.PanicIfErr(err)
+}
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type ReadingListRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, readingList entity.ReadingList) entity.ReadingList
+	Update(ctx context.Context, tx *sql.Tx, readingList entity.ReadingList) entity.ReadingList
+	Delete(ctx context.Context, tx *sql.Tx, readingListId int)
+	FindByID(ctx context.Context, tx *sql.Tx, readingListId int) (entity.ReadingList, error)
+	FindAllByUserID(ctx context.Context, tx *sql.Tx, userId int, publicOnly bool) []entity.ReadingList
+	CreateItem(ctx context.Context, tx *sql.Tx, item entity.ReadingListItem)
+	UpdateItem(ctx context.Context, tx *sql.Tx, item entity.ReadingListItem)
+	DeleteItem(ctx context.Context, tx *sql.Tx, readingListId int, articleId int)
+	FindItem(ctx context.Context, tx *sql.Tx, readingListId int, articleId int) (entity.ReadingListItem, error)
+	FindItems(ctx context.Context, tx *sql.Tx, readingListId int) []entity.ReadingListItem
+	FindItemArticleIDs(ctx context.Context, tx *sql.Tx, readingListId int) []int
+	NextItemPosition(ctx context.Context, tx *sql.Tx, readingListId int) int
+}
+
+type ReadingListRepositoryImpl struct {
+}
+
+func NewReadingListRepository() ReadingListRepository {
+	return &ReadingListRepositoryImpl{}
+}
+
+func (repository *ReadingListRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, readingList entity.ReadingList) entity.ReadingList {
+	SQL := `INSERT INTO reading_lists (user_id, name, description, is_public) VALUES (?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, readingList.UserId, readingList.Name, readingList.Description, readingList.IsPublic)
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+	readingList.Id = int(id)
+
+	return readingList
+}
+
+func (repository *ReadingListRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, readingList entity.ReadingList) entity.ReadingList {
+	SQL := `UPDATE reading_lists SET name = ?, description = ?, is_public = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, readingList.Name, readingList.Description, readingList.IsPublic, readingList.Id)
+	helper.PanicIfErr(err)
+
+	return readingList
+}
+
+func (repository *ReadingListRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, readingListId int) {
+	SQL := `DELETE FROM reading_lists WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, readingListId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *ReadingListRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, readingListId int) (entity.ReadingList, error) {
+	lists := repository.findAll(ctx, tx, "rl.id = ?", readingListId)
+	if len(lists) == 0 {
+		return entity.ReadingList{}, errors.New("reading list not found")
+	}
+	return lists[0], nil
+}
+
+func (repository *ReadingListRepositoryImpl) FindAllByUserID(ctx context.Context, tx *sql.Tx, userId int, publicOnly bool) []entity.ReadingList {
+	if publicOnly {
+		return repository.findAll(ctx, tx, "rl.user_id = ? AND rl.is_public = true", userId)
+	}
+	return repository.findAll(ctx, tx, "rl.user_id = ?", userId)
+}
+
+func (repository *ReadingListRepositoryImpl) findAll(ctx context.Context, tx *sql.Tx, condition string, arg any) []entity.ReadingList {
+	SQL := `SELECT
+				rl.id,
+				rl.user_id,
+				rl.name,
+				rl.description,
+				rl.is_public,
+				(SELECT COUNT(*) FROM reading_list_items rli WHERE rli.reading_list_id = rl.id),
+				rl.created_at,
+				rl.updated_at
+			FROM
+				reading_lists rl
+			WHERE
+				` + condition + `
+			ORDER BY
+				rl.created_at DESC`
+	rows, err := tx.QueryContext(ctx, SQL, arg)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var lists []entity.ReadingList
+	for rows.Next() {
+		var list entity.ReadingList
+		var description sql.NullString
+		err := rows.Scan(&list.Id, &list.UserId, &list.Name, &description, &list.IsPublic, &list.ItemCount, &list.CreatedAt, &list.UpdatedAt)
+		helper.PanicIfErr(err)
+		list.Description = helper.NullStringToString(description)
+		lists = append(lists, list)
+	}
+	return lists
+}
+
+func (repository *ReadingListRepositoryImpl) CreateItem(ctx context.Context, tx *sql.Tx, item entity.ReadingListItem) {
+	SQL := `INSERT INTO reading_list_items (reading_list_id, article_id, position, note) VALUES (?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, item.ReadingListId, item.Article.Id, item.Position, item.Note)
+	helper.PanicIfErr(err)
+}
+
+func (repository *ReadingListRepositoryImpl) UpdateItem(ctx context.Context, tx *sql.Tx, item entity.ReadingListItem) {
+	SQL := `UPDATE reading_list_items SET position = ?, note = ? WHERE reading_list_id = ? AND article_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, item.Position, item.Note, item.ReadingListId, item.Article.Id)
+	helper.PanicIfErr(err)
+}
+
+func (repository *ReadingListRepositoryImpl) DeleteItem(ctx context.Context, tx *sql.Tx, readingListId int, articleId int) {
+	SQL := `DELETE FROM reading_list_items WHERE reading_list_id = ? AND article_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, readingListId, articleId)
+	helper.PanicIfErr(err)
+}
+
+// FindItem returns an item without its article, only Article.Id is set.
+func (repository *ReadingListRepositoryImpl) FindItem(ctx context.Context, tx *sql.Tx, readingListId int, articleId int) (entity.ReadingListItem, error) {
+	SQL := `SELECT reading_list_id, article_id, position, note, created_at, updated_at FROM reading_list_items WHERE reading_list_id = ? AND article_id = ?`
+	row, err := tx.QueryContext(ctx, SQL, readingListId, articleId)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var item entity.ReadingListItem
+	if row.Next() {
+		var note sql.NullString
+		err := row.Scan(&item.ReadingListId, &item.Article.Id, &item.Position, &note, &item.CreatedAt, &item.UpdatedAt)
+		helper.PanicIfErr(err)
+		item.Note = helper.NullStringToString(note)
+		return item, nil
+	} else {
+		return item, errors.New("reading list item not found")
+	}
+}
+
+// FindItems returns the items of a reading list in order. Articles that were
+// unpublished after being added are left out.
+func (repository *ReadingListRepositoryImpl) FindItems(ctx context.Context, tx *sql.Tx, readingListId int) []entity.ReadingListItem {
+	SQL := `SELECT
+				rli.reading_list_id,
+				rli.position,
+				rli.note,
+				rli.created_at,
+				rli.updated_at,
+				a.id,
+				a.user_id,
+				a.title,
+				a.slug,
+				a.description,
+				a.content,
+				a.is_published,
+				a.version,
+				a.created_at,
+				a.updated_at,
+				up.full_name
+			FROM
+				reading_list_items rli
+			JOIN
+				articles a ON rli.article_id = a.id
+			JOIN
+				user_profiles up ON a.user_id = up.user_id
+			WHERE
+				rli.reading_list_id = ?
+				AND a.is_published = true
+			ORDER BY
+				rli.position, rli.created_at`
+	rows, err := tx.QueryContext(ctx, SQL, readingListId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var items []entity.ReadingListItem
+	for rows.Next() {
+		var item entity.ReadingListItem
+		var note sql.NullString
+		article := &item.Article
+		err := rows.Scan(&item.ReadingListId, &item.Position, &note, &item.CreatedAt, &item.UpdatedAt,
+			&article.Id, &article.UserId, &article.Title, &article.Slug, &article.Description, &article.Content, &article.IsPublished, &article.Version, &article.CreatedAt, &article.UpdatedAt, &article.Author)
+		helper.PanicIfErr(err)
+		item.Note = helper.NullStringToString(note)
+		items = append(items, item)
+	}
+	return items
+}
+
+// FindItemArticleIDs returns the article ids of all items in order, including
+// unpublished articles that FindItems leaves out.
+func (repository *ReadingListRepositoryImpl) FindItemArticleIDs(ctx context.Context, tx *sql.Tx, readingListId int) []int {
+	SQL := `SELECT article_id FROM reading_list_items WHERE reading_list_id = ? ORDER BY position, created_at`
+	rows, err := tx.QueryContext(ctx, SQL, readingListId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var articleIds []int
+	for rows.Next() {
+		var articleId int
+		err := rows.Scan(&articleId)
+		helper.PanicIfErr(err)
+		articleIds = append(articleIds, articleId)
+	}
+	return articleIds
+}
+
+func (repository *ReadingListRepositoryImpl) NextItemPosition(ctx context.Context, tx *sql.Tx, readingListId int) int {
+	SQL := `SELECT COALESCE(MAX(position), 0) + 1 FROM reading_list_items WHERE reading_list_id = ?`
+	var position int
+	err := tx.QueryRowContext(ctx, SQL, readingListId).Scan(&position)
+	helper.PanicIfErr(err)
+	return position
+}
@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, tag entity.Tag) entity.Tag
+	Update(ctx context.Context, tx *sql.Tx, tag entity.Tag) entity.Tag
+	Delete(ctx context.Context, tx *sql.Tx, tagId int)
+	FindByID(ctx context.Context, tx *sql.Tx, tagId int) (entity.Tag, error)
+	FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Tag, error)
+	LockBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Tag, error)
+	FindAll(ctx context.Context, tx *sql.Tx, slugPrefix string, limit int) []entity.Tag
+	FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int) []entity.Tag
+	AddToArticle(ctx context.Context, tx *sql.Tx, articleId int, tagId int)
+	DeleteFromArticle(ctx context.Context, tx *sql.Tx, articleId int)
+	MoveArticles(ctx context.Context, tx *sql.Tx, sourceId int, targetId int)
+}
+
+type TagRepositoryImpl struct {
+}
+
+func NewTagRepository() TagRepository {
+	return &TagRepositoryImpl{}
+}
+
+func (repository *TagRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, tag entity.Tag) entity.Tag {
+	SQL := `INSERT INTO tags (name, slug) VALUES (?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, tag.Name, tag.Slug)
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+
+	tag.Id = int(id)
+	return tag
+}
+
+func (repository *TagRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, tag entity.Tag) entity.Tag {
+	SQL := `UPDATE tags SET name = ?, slug = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, tag.Name, tag.Slug, tag.Id)
+	helper.PanicIfErr(err)
+
+	return tag
+}
+
+func (repository *TagRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, tagId int) {
+	SQL := `DELETE FROM tags WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, tagId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *TagRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, tagId int) (entity.Tag, error) {
+	SQL := `SELECT 
+				t.id,
+				t.name,
+				t.slug,
+				COUNT(a.id) AS article_count,
+				t.created_at,
+				t.updated_at
+			FROM 
+				tags t
+			LEFT JOIN 
+				article_tags at ON t.id = at.tag_id
+			LEFT JOIN 
+				articles a ON at.article_id = a.id AND a.is_published = true
+			WHERE 
+				t.id = ?
+			GROUP BY 
+				t.id`
+	return repository.findOne(ctx, tx, SQL, tagId)
+}
+
+func (repository *TagRepositoryImpl) FindBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Tag, error) {
+	SQL := `SELECT 
+				t.id,
+				t.name,
+				t.slug,
+				COUNT(a.id) AS article_count,
+				t.created_at,
+				t.updated_at
+			FROM 
+				tags t
+			LEFT JOIN 
+				article_tags at ON t.id = at.tag_id
+			LEFT JOIN 
+				articles a ON at.article_id = a.id AND a.is_published = true
+			WHERE 
+				t.slug = ?
+			GROUP BY 
+				t.id`
+	return repository.findOne(ctx, tx, SQL, slug)
+}
+
+// LockBySlug reads the latest committed tag with slug, which a plain read
+// misses when another transaction created it after this one's snapshot.
+// The article count is not loaded.
+func (repository *TagRepositoryImpl) LockBySlug(ctx context.Context, tx *sql.Tx, slug string) (entity.Tag, error) {
+	SQL := `SELECT id, name, slug, 0, created_at, updated_at FROM tags WHERE slug = ? FOR SHARE`
+	return repository.findOne(ctx, tx, SQL, slug)
+}
+
+func (repository *TagRepositoryImpl) findOne(ctx context.Context, tx *sql.Tx, SQL string, args ...any) (entity.Tag, error) {
+	row, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer row.Close()
+
+	var tag entity.Tag
+	if row.Next() {
+		err := row.Scan(&tag.Id, &tag.Name, &tag.Slug, &tag.ArticleCount, &tag.CreatedAt, &tag.UpdatedAt)
+		helper.PanicIfErr(err)
+		return tag, nil
+	} else {
+		return tag, errors.New("tag not found")
+	}
+}
+
+func (repository *TagRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, slugPrefix string, limit int) []entity.Tag {
+	SQL := `SELECT 
+				t.id,
+				t.name,
+				t.slug,
+				COUNT(a.id) AS article_count,
+				t.created_at,
+				t.updated_at
+			FROM 
+				tags t
+			LEFT JOIN 
+				article_tags at ON t.id = at.tag_id
+			LEFT JOIN 
+				articles a ON at.article_id = a.id AND a.is_published = true
+			WHERE 
+				t.slug LIKE CONCAT(?, '%')
+			GROUP BY 
+				t.id
+			ORDER BY 
+				article_count DESC, t.name
+			LIMIT ?`
+	rows, err := tx.QueryContext(ctx, SQL, slugPrefix, limit)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var tags []entity.Tag
+	for rows.Next() {
+		var tag entity.Tag
+		err := rows.Scan(&tag.Id, &tag.Name, &tag.Slug, &tag.ArticleCount, &tag.CreatedAt, &tag.UpdatedAt)
+		helper.PanicIfErr(err)
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (repository *TagRepositoryImpl) FindByArticleID(ctx context.Context, tx *sql.Tx, articleId int) []entity.Tag {
+	SQL := `SELECT t.id, t.name, t.slug, t.created_at, t.updated_at 
+			FROM tags t 
+			JOIN article_tags at ON t.id = at.tag_id 
+			WHERE at.article_id = ? 
+			ORDER BY t.name`
+	rows, err := tx.QueryContext(ctx, SQL, articleId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var tags []entity.Tag
+	for rows.Next() {
+		var tag entity.Tag
+		err := rows.Scan(&tag.Id, &tag.Name, &tag.Slug, &tag.CreatedAt, &tag.UpdatedAt)
+		helper.PanicIfErr(err)
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (repository *TagRepositoryImpl) AddToArticle(ctx context.Context, tx *sql.Tx, articleId int, tagId int) {
+	SQL := `INSERT IGNORE INTO article_tags (article_id, tag_id) VALUES (?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, articleId, tagId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *TagRepositoryImpl) DeleteFromArticle(ctx context.Context, tx *sql.Tx, articleId int) {
+	SQL := `DELETE FROM article_tags WHERE article_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, articleId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *TagRepositoryImpl) MoveArticles(ctx context.Context, tx *sql.Tx, sourceId int, targetId int) {
+	SQL := `INSERT IGNORE INTO article_tags (article_id, tag_id) SELECT article_id, ? FROM article_tags WHERE tag_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, targetId, sourceId)
+	helper.PanicIfErr(err)
+
+	SQL = `DELETE FROM article_tags WHERE tag_id = ?`
+	_, err = tx.ExecContext(ctx, SQL, sourceId)
+	helper.PanicIfErr(err)
+}
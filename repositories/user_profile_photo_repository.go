@@ -11,6 +11,9 @@ import (
 type UserProfilePhotoRepository interface {
 	Update(ctx context.Context, tx *sql.Tx, profiles entity.UserProfilePhoto) entity.UserProfilePhoto
 	FindByUserID(ctx context.Context, tx *sql.Tx, userId int) (entity.UserProfilePhoto, error)
+	CreateVariant(ctx context.Context, tx *sql.Tx, variant entity.UserProfilePhotoVariant) entity.UserProfilePhotoVariant
+	FindVariantsByUserID(ctx context.Context, tx *sql.Tx, userId int) []entity.UserProfilePhotoVariant
+	DeleteVariantsByUserID(ctx context.Context, tx *sql.Tx, userId int)
 }
 
 type UserProfilePhotoRepositoryImpl struct {
@@ -43,3 +46,33 @@ func (repository *UserProfilePhotoRepositoryImpl) FindByUserID(ctx context.Conte
 		return profiles, errors.New("user profile photo not found")
 	}
 }
+
+func (repository *UserProfilePhotoRepositoryImpl) CreateVariant(ctx context.Context, tx *sql.Tx, variant entity.UserProfilePhotoVariant) entity.UserProfilePhotoVariant {
+	SQL := `INSERT INTO user_profile_photo_variants (user_id, name, path, width, height, content_type) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, SQL, variant.UserId, variant.Name, variant.Path, variant.Width, variant.Height, variant.ContentType)
+	helper.PanicIfErr(err)
+
+	return variant
+}
+
+func (repository *UserProfilePhotoRepositoryImpl) FindVariantsByUserID(ctx context.Context, tx *sql.Tx, userId int) []entity.UserProfilePhotoVariant {
+	SQL := `SELECT user_id, name, path, width, height, content_type, created_at, updated_at FROM user_profile_photo_variants WHERE user_id = ? ORDER BY width, name`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var variants []entity.UserProfilePhotoVariant
+	for rows.Next() {
+		var variant entity.UserProfilePhotoVariant
+		err := rows.Scan(&variant.UserId, &variant.Name, &variant.Path, &variant.Width, &variant.Height, &variant.ContentType, &variant.CreatedAt, &variant.UpdatedAt)
+		helper.PanicIfErr(err)
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+func (repository *UserProfilePhotoRepositoryImpl) DeleteVariantsByUserID(ctx context.Context, tx *sql.Tx, userId int) {
+	SQL := `DELETE FROM user_profile_photo_variants WHERE user_id = ?`
+	_, err := tx.ExecContext(ctx, SQL, userId)
+	helper.PanicIfErr(err)
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type UserProfileRepository interface {
-	Update(ctx context.Context, tx *sql.Tx, userProfile entity.UserProfile) entity.UserProfile
+	Update(ctx context.Context, tx *sql.Tx, userProfile entity.UserProfile) (entity.UserProfile, error)
 	Delete(ctx context.Context, tx *sql.Tx, userId int)
 	FindByUserID(ctx context.Context, tx *sql.Tx, userId int) (entity.UserProfile, error)
 	FindAll(ctx context.Context, tx *sql.Tx) []entity.UserProfile
@@ -22,12 +22,21 @@ func NewUserProfileRepository() UserProfileRepository {
 	return &UserProfileRepositoryImpl{}
 }
 
-func (repository *UserProfileRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, userProfile entity.UserProfile) entity.UserProfile {
-	SQL := `UPDATE user_profiles SET full_name = ?, gender = ?, birthdate = ?, phone_number = ?, address = ?  WHERE user_id = ?`
-	_, err := tx.ExecContext(ctx, SQL, userProfile.FullName, userProfile.Gender, userProfile.BirthDate, userProfile.PhoneNumber, userProfile.Address, userProfile.UserId)
+// Update only succeeds while the row still has userProfile.Version; the
+// returned profile carries the next version.
+func (repository *UserProfileRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, userProfile entity.UserProfile) (entity.UserProfile, error) {
+	SQL := `UPDATE user_profiles SET full_name = ?, gender = ?, birthdate = ?, phone_number = ?, address = ?, version = version + 1 WHERE user_id = ? AND version = ?`
+	result, err := tx.ExecContext(ctx, SQL, userProfile.FullName, userProfile.Gender, userProfile.BirthDate, userProfile.PhoneNumber, userProfile.Address, userProfile.UserId, userProfile.Version)
 	helper.PanicIfErr(err)
 
-	return userProfile
+	affected, err := result.RowsAffected()
+	helper.PanicIfErr(err)
+	if affected == 0 {
+		return userProfile, errors.New("user profile was modified by another request")
+	}
+
+	userProfile.Version++
+	return userProfile, nil
 }
 
 func (repository *UserProfileRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, userId int) {
@@ -37,7 +46,11 @@ func (repository *UserProfileRepositoryImpl) Delete(ctx context.Context, tx *sql
 }
 
 func (repository *UserProfileRepositoryImpl) FindByUserID(ctx context.Context, tx *sql.Tx, id int) (entity.UserProfile, error) {
-	SQL := `SELECT user_id, full_name, gender, birthdate, phone_number, address, created_at, updated_at FROM user_profiles WHERE user_id = ?`
+	SQL := `SELECT user_id, full_name, gender, birthdate, phone_number, address, version,
+				(SELECT COUNT(*) FROM follows WHERE followee_id = user_profiles.user_id),
+				(SELECT COUNT(*) FROM follows WHERE follower_id = user_profiles.user_id),
+				created_at, updated_at
+			FROM user_profiles WHERE user_id = ?`
 	row, err := tx.QueryContext(ctx, SQL, id)
 	helper.PanicIfErr(err)
 	defer row.Close()
@@ -50,7 +63,7 @@ func (repository *UserProfileRepositoryImpl) FindByUserID(ctx context.Context, t
 		var phoneNumber sql.NullString
 		var address sql.NullString
 
-		err = row.Scan(&user.UserId, &fullName, &gender, &birthDate, &phoneNumber, &address, &user.CreatedAt, &user.UpdatedAt)
+		err = row.Scan(&user.UserId, &fullName, &gender, &birthDate, &phoneNumber, &address, &user.Version, &user.FollowersCount, &user.FollowingCount, &user.CreatedAt, &user.UpdatedAt)
 		helper.PanicIfErr(err)
 
 		user.FullName = helper.NullStringToString(fullName)
@@ -66,7 +79,11 @@ func (repository *UserProfileRepositoryImpl) FindByUserID(ctx context.Context, t
 }
 
 func (repository *UserProfileRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []entity.UserProfile {
-	SQL := `SELECT user_id, full_name, gender, birthdate, phone_number, address, created_at, updated_at FROM user_profiles`
+	SQL := `SELECT user_id, full_name, gender, birthdate, phone_number, address, version,
+				(SELECT COUNT(*) FROM follows WHERE followee_id = user_profiles.user_id),
+				(SELECT COUNT(*) FROM follows WHERE follower_id = user_profiles.user_id),
+				created_at, updated_at
+			FROM user_profiles`
 	rows, err := tx.QueryContext(ctx, SQL)
 	helper.PanicIfErr(err)
 	defer rows.Close()
@@ -82,7 +99,7 @@ func (repository *UserProfileRepositoryImpl) FindAll(ctx context.Context, tx *sq
 		var phoneNumber sql.NullString
 		var address sql.NullString
 
-		err = rows.Scan(&user.UserId, &fullName, &gender, &birthDate, &phoneNumber, &address, &user.CreatedAt, &user.UpdatedAt)
+		err = rows.Scan(&user.UserId, &fullName, &gender, &birthDate, &phoneNumber, &address, &user.Version, &user.FollowersCount, &user.FollowingCount, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			helper.PanicIfErr(err)
 		}
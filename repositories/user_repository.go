@@ -13,6 +13,8 @@ type UserRepository interface {
 	Delete(ctx context.Context, tx *sql.Tx, id int)
 	FindByID(ctx context.Context, tx *sql.Tx, id int) (entity.User, error)
 	FindAll(ctx context.Context, tx *sql.Tx) []entity.User
+	Suspend(ctx context.Context, tx *sql.Tx, id int, days int)
+	IsSuspended(ctx context.Context, tx *sql.Tx, id int) bool
 }
 
 type UserRepositoryImpl struct {
@@ -68,3 +70,19 @@ func (repository *UserRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) [
 	}
 	return users
 }
+
+// Suspend blocks the user from logging in for the given number of days,
+// replacing any earlier suspension.
+func (repository *UserRepositoryImpl) Suspend(ctx context.Context, tx *sql.Tx, id int, days int) {
+	SQL := `UPDATE users SET suspended_until = NOW() + INTERVAL ? DAY WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, days, id)
+	helper.PanicIfErr(err)
+}
+
+func (repository *UserRepositoryImpl) IsSuspended(ctx context.Context, tx *sql.Tx, id int) bool {
+	SQL := `SELECT EXISTS (SELECT 1 FROM users WHERE id = ? AND suspended_until > NOW())`
+	var suspended bool
+	err := tx.QueryRowContext(ctx, SQL, id).Scan(&suspended)
+	helper.PanicIfErr(err)
+	return suspended
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, webhook entity.Webhook) entity.Webhook
+	Update(ctx context.Context, tx *sql.Tx, webhook entity.Webhook)
+	Delete(ctx context.Context, tx *sql.Tx, webhookId int)
+	FindByID(ctx context.Context, tx *sql.Tx, webhookId int) (entity.Webhook, error)
+	FindAll(ctx context.Context, tx *sql.Tx) []entity.Webhook
+	FindActiveByEvent(ctx context.Context, tx *sql.Tx, event string) []entity.Webhook
+	CreateDelivery(ctx context.Context, tx *sql.Tx, delivery entity.WebhookDelivery) entity.WebhookDelivery
+	FindDeliveryByID(ctx context.Context, tx *sql.Tx, deliveryId int) (entity.WebhookDelivery, error)
+	FindDeliveriesByWebhookID(ctx context.Context, tx *sql.Tx, webhookId int, limit int) []entity.WebhookDelivery
+	ClaimDueDeliveries(ctx context.Context, tx *sql.Tx, limit int, lease time.Duration) []entity.WebhookDelivery
+	FinishAttempt(ctx context.Context, tx *sql.Tx, delivery entity.WebhookDelivery, retryIn time.Duration)
+	ResetDelivery(ctx context.Context, tx *sql.Tx, deliveryId int)
+	CreateAttempt(ctx context.Context, tx *sql.Tx, attempt entity.WebhookDeliveryAttempt)
+	FindAttemptsByDeliveryID(ctx context.Context, tx *sql.Tx, deliveryId int) []entity.WebhookDeliveryAttempt
+}
+
+type WebhookRepositoryImpl struct {
+}
+
+func NewWebhookRepository() WebhookRepository {
+	return &WebhookRepositoryImpl{}
+}
+
+func (repository *WebhookRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, webhook entity.Webhook) entity.Webhook {
+	SQL := `INSERT INTO webhooks (url, secret, events, is_active, created_by) VALUES (?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, webhook.Url, webhook.Secret, strings.Join(webhook.Events, ","), webhook.IsActive, nullableId(webhook.CreatedBy))
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+	webhook.Id = int(id)
+
+	return webhook
+}
+
+func (repository *WebhookRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, webhook entity.Webhook) {
+	SQL := `UPDATE webhooks SET url = ?, secret = ?, events = ?, is_active = ? WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, webhook.Url, webhook.Secret, strings.Join(webhook.Events, ","), webhook.IsActive, webhook.Id)
+	helper.PanicIfErr(err)
+}
+
+func (repository *WebhookRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, webhookId int) {
+	SQL := `DELETE FROM webhooks WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, webhookId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *WebhookRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, webhookId int) (entity.Webhook, error) {
+	SQL := `SELECT id, url, secret, events, is_active, created_by, created_at, updated_at FROM webhooks WHERE id = ?`
+	webhooks := repository.findAll(ctx, tx, SQL, webhookId)
+	if len(webhooks) == 0 {
+		return entity.Webhook{}, errors.New("webhook not found")
+	}
+	return webhooks[0], nil
+}
+
+func (repository *WebhookRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []entity.Webhook {
+	SQL := `SELECT id, url, secret, events, is_active, created_by, created_at, updated_at FROM webhooks ORDER BY id`
+	return repository.findAll(ctx, tx, SQL)
+}
+
+// FindActiveByEvent returns the active webhooks subscribed to event.
+func (repository *WebhookRepositoryImpl) FindActiveByEvent(ctx context.Context, tx *sql.Tx, event string) []entity.Webhook {
+	SQL := `SELECT id, url, secret, events, is_active, created_by, created_at, updated_at FROM webhooks WHERE is_active = true AND FIND_IN_SET(?, events) > 0`
+	return repository.findAll(ctx, tx, SQL, event)
+}
+
+func (repository *WebhookRepositoryImpl) findAll(ctx context.Context, tx *sql.Tx, SQL string, args ...any) []entity.Webhook {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var webhooks []entity.Webhook
+	for rows.Next() {
+		var webhook entity.Webhook
+		var events string
+		var createdBy sql.NullInt64
+		err := rows.Scan(&webhook.Id, &webhook.Url, &webhook.Secret, &events, &webhook.IsActive, &createdBy, &webhook.CreatedAt, &webhook.UpdatedAt)
+		helper.PanicIfErr(err)
+		webhook.Events = strings.Split(events, ",")
+		webhook.CreatedBy = int(createdBy.Int64)
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks
+}
+
+func (repository *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, tx *sql.Tx, delivery entity.WebhookDelivery) entity.WebhookDelivery {
+	SQL := `INSERT INTO webhook_deliveries (webhook_id, event, payload) VALUES (?, ?, ?)`
+	result, err := tx.ExecContext(ctx, SQL, delivery.WebhookId, delivery.Event, delivery.Payload)
+	helper.PanicIfErr(err)
+
+	id, err := result.LastInsertId()
+	helper.PanicIfErr(err)
+	delivery.Id = int(id)
+
+	return delivery
+}
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempt_count, d.response_code, d.next_attempt_at, d.last_attempt_at, d.created_at`
+
+func (repository *WebhookRepositoryImpl) FindDeliveryByID(ctx context.Context, tx *sql.Tx, deliveryId int) (entity.WebhookDelivery, error) {
+	SQL := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries d WHERE d.id = ?`
+	deliveries := repository.findDeliveries(ctx, tx, SQL, deliveryId)
+	if len(deliveries) == 0 {
+		return entity.WebhookDelivery{}, errors.New("delivery not found")
+	}
+	return deliveries[0], nil
+}
+
+// FindDeliveriesByWebhookID returns the latest deliveries of a webhook, newest
+// first.
+func (repository *WebhookRepositoryImpl) FindDeliveriesByWebhookID(ctx context.Context, tx *sql.Tx, webhookId int, limit int) []entity.WebhookDelivery {
+	SQL := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries d WHERE d.webhook_id = ? ORDER BY d.created_at DESC, d.id DESC LIMIT ?`
+	return repository.findDeliveries(ctx, tx, SQL, webhookId, limit)
+}
+
+// ClaimDueDeliveries locks the pending deliveries of active webhooks that are
+// due and pushes their next attempt lease into the future, so other replicas
+// skip them while they are being sent. A delivery whose sender dies is picked
+// up again once the lease ends.
+func (repository *WebhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, tx *sql.Tx, limit int, lease time.Duration) []entity.WebhookDelivery {
+	SQL := `SELECT ` + webhookDeliveryColumns + `
+			FROM
+				webhook_deliveries d
+			WHERE
+				d.status = 'pending'
+				AND d.next_attempt_at <= CURRENT_TIMESTAMP
+				AND d.webhook_id IN (SELECT id FROM webhooks WHERE is_active = true)
+			ORDER BY
+				d.next_attempt_at, d.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED`
+	deliveries := repository.findDeliveries(ctx, tx, SQL, limit)
+
+	for _, delivery := range deliveries {
+		SQL := `UPDATE webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP + INTERVAL ? SECOND WHERE id = ?`
+		_, err := tx.ExecContext(ctx, SQL, int(lease.Seconds()), delivery.Id)
+		helper.PanicIfErr(err)
+	}
+	return deliveries
+}
+
+// FinishAttempt stores the outcome of an attempt. retryIn is only used while
+// the delivery stays pending.
+func (repository *WebhookRepositoryImpl) FinishAttempt(ctx context.Context, tx *sql.Tx, delivery entity.WebhookDelivery, retryIn time.Duration) {
+	SQL := `UPDATE
+				webhook_deliveries
+			SET
+				status = ?,
+				attempt_count = ?,
+				response_code = ?,
+				last_attempt_at = CURRENT_TIMESTAMP,
+				next_attempt_at = CURRENT_TIMESTAMP + INTERVAL ? SECOND
+			WHERE
+				id = ?`
+	responseCode := sql.NullInt64{Int64: int64(delivery.ResponseCode), Valid: delivery.ResponseCode != 0}
+	_, err := tx.ExecContext(ctx, SQL, delivery.Status, delivery.AttemptCount, responseCode, int(retryIn.Seconds()), delivery.Id)
+	helper.PanicIfErr(err)
+}
+
+// ResetDelivery queues a delivery again with a fresh retry budget.
+func (repository *WebhookRepositoryImpl) ResetDelivery(ctx context.Context, tx *sql.Tx, deliveryId int) {
+	SQL := `UPDATE webhook_deliveries SET status = 'pending', attempt_count = 0, next_attempt_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := tx.ExecContext(ctx, SQL, deliveryId)
+	helper.PanicIfErr(err)
+}
+
+func (repository *WebhookRepositoryImpl) findDeliveries(ctx context.Context, tx *sql.Tx, SQL string, args ...any) []entity.WebhookDelivery {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var deliveries []entity.WebhookDelivery
+	for rows.Next() {
+		var delivery entity.WebhookDelivery
+		var responseCode sql.NullInt64
+		var nextAttemptAt, lastAttemptAt sql.NullString
+		err := rows.Scan(&delivery.Id, &delivery.WebhookId, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.AttemptCount, &responseCode, &nextAttemptAt, &lastAttemptAt, &delivery.CreatedAt)
+		helper.PanicIfErr(err)
+		delivery.ResponseCode = int(responseCode.Int64)
+		delivery.NextAttemptAt = helper.NullStringToString(nextAttemptAt)
+		delivery.LastAttemptAt = helper.NullStringToString(lastAttemptAt)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (repository *WebhookRepositoryImpl) CreateAttempt(ctx context.Context, tx *sql.Tx, attempt entity.WebhookDeliveryAttempt) {
+	SQL := `INSERT INTO webhook_delivery_attempts (delivery_id, response_code, response_body, error, duration_ms) VALUES (?, ?, ?, ?, ?)`
+	responseCode := sql.NullInt64{Int64: int64(attempt.ResponseCode), Valid: attempt.ResponseCode != 0}
+	attemptError := sql.NullString{String: attempt.Error, Valid: attempt.Error != ""}
+	_, err := tx.ExecContext(ctx, SQL, attempt.DeliveryId, responseCode, attempt.ResponseBody, attemptError, attempt.DurationMs)
+	helper.PanicIfErr(err)
+}
+
+func (repository *WebhookRepositoryImpl) FindAttemptsByDeliveryID(ctx context.Context, tx *sql.Tx, deliveryId int) []entity.WebhookDeliveryAttempt {
+	SQL := `SELECT id, delivery_id, response_code, response_body, error, duration_ms, created_at FROM webhook_delivery_attempts WHERE delivery_id = ? ORDER BY id`
+	rows, err := tx.QueryContext(ctx, SQL, deliveryId)
+	helper.PanicIfErr(err)
+	defer rows.Close()
+
+	var attempts []entity.WebhookDeliveryAttempt
+	for rows.Next() {
+		var attempt entity.WebhookDeliveryAttempt
+		var responseCode sql.NullInt64
+		var responseBody, attemptError sql.NullString
+		err := rows.Scan(&attempt.Id, &attempt.DeliveryId, &responseCode, &responseBody, &attemptError, &attempt.DurationMs, &attempt.CreatedAt)
+		helper.PanicIfErr(err)
+		attempt.ResponseCode = int(responseCode.Int64)
+		attempt.ResponseBody = helper.NullStringToString(responseBody)
+		attempt.Error = helper.NullStringToString(attemptError)
+		attempts = append(attempts, attempt)
+	}
+	return attempts
+}
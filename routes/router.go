@@ -58,14 +58,29 @@ func SetupArticlePhotoRoutes(app *fiber.App, controller controllers.ArticleContr
 	{
 		articleGroup.Post("/", middlewares.AuthRequired, controller.CreateByToken)
 		articleGroup.Get("/published", middlewares.AuthRequired, controller.FindAllPublished)
+		articleGroup.Get("/feed", middlewares.AuthRequired, controller.FindFeed)
 		articleGroup.Put("/published/:articleId", middlewares.AdminOnly, controller.PublishArticle)
 		articleGroup.Get("/published/user", middlewares.UserOnly, controller.FindAllPublishedByUserID)
 		articleGroup.Get("/unpublished", middlewares.AdminOnly, controller.FindAllUnpublished)
 		articleGroup.Get("/unpublished/user", middlewares.UserOnly, controller.FindAllUnpublishedByUserID)
 		articleGroup.Put("/unpublished/:articleId", middlewares.AdminOnly, controller.UnpublishArticle)
+		articleGroup.Get("/slug/:slug", middlewares.AuthRequired, controller.FindBySlug)
 		articleGroup.Get("/:articleId", middlewares.AuthRequired, controller.FindByID)
 		articleGroup.Put("/:articleId", middlewares.UserOnly, controller.UpdateByID)
+		articleGroup.Patch("/:articleId", middlewares.UserOnly, controller.PatchByID)
 		articleGroup.Delete("/:articleId", middlewares.AuthRequired, controller.DeleteByID)
+		articleGroup.Post("/:articleId/media", middlewares.UserOnly, controller.CreateMedia)
+		articleGroup.Delete("/:articleId/media/:mediaId", middlewares.UserOnly, controller.DeleteMedia)
+	}
+	apiGroup.Get("/timeline", middlewares.AuthRequired, controller.FindTimeline)
+}
+
+func SetupArticleAnalyticsRoutes(app *fiber.App, controller controllers.ArticleAnalyticsController) {
+	apiGroup := app.Group("/api")
+	analyticsGroup := apiGroup.Group("/analytics")
+	{
+		analyticsGroup.Get("/articles", middlewares.UserOnly, controller.FindByToken)
+		analyticsGroup.Get("/articles/:articleId", middlewares.UserOnly, controller.FindByArticleID)
 	}
 }
 
@@ -78,6 +93,98 @@ func SetupLikeRoutes(app *fiber.App, controller controllers.LikeController) {
 		likeGroup.Delete("/articles/:articleId", middlewares.UserOnly, controller.Delete)
 		likeGroup.Get("/users/:userId", middlewares.AuthRequired, controller.FindByUserId)
 	}
+	reactionGroup := apiGroup.Group("/reactions")
+	{
+		reactionGroup.Get("/articles/:articleId", middlewares.AuthRequired, controller.FindReactions)
+		reactionGroup.Post("/articles/:articleId/:reaction", middlewares.UserOnly, controller.CreateReaction)
+		reactionGroup.Delete("/articles/:articleId/:reaction", middlewares.UserOnly, controller.DeleteReaction)
+	}
+}
+
+func SetupFollowRoutes(app *fiber.App, controller controllers.FollowController) {
+	apiGroup := app.Group("/api")
+	followGroup := apiGroup.Group("/follows")
+	{
+		followGroup.Post("/users/:userId", middlewares.AuthRequired, controller.Create)
+		followGroup.Delete("/users/:userId", middlewares.AuthRequired, controller.Delete)
+		followGroup.Get("/users/:userId/followers", middlewares.AuthRequired, controller.FindFollowers)
+		followGroup.Get("/users/:userId/following", middlewares.AuthRequired, controller.FindFollowing)
+	}
+}
+
+func SetupBlockRoutes(app *fiber.App, controller controllers.BlockController) {
+	apiGroup := app.Group("/api")
+	blockGroup := apiGroup.Group("/blocks")
+	{
+		blockGroup.Get("/", middlewares.AuthRequired, controller.FindByToken)
+		blockGroup.Post("/users/:userId", middlewares.AuthRequired, controller.Create)
+		blockGroup.Delete("/users/:userId", middlewares.AuthRequired, controller.Delete)
+	}
+}
+
+func SetupNotificationRoutes(app *fiber.App, controller controllers.NotificationController) {
+	apiGroup := app.Group("/api")
+	notificationGroup := apiGroup.Group("/notifications")
+	{
+		notificationGroup.Get("/", middlewares.AuthRequired, controller.FindByToken)
+		notificationGroup.Get("/unread_count", middlewares.AuthRequired, controller.CountUnread)
+		notificationGroup.Put("/read", middlewares.AuthRequired, controller.MarkAllRead)
+		notificationGroup.Get("/preferences", middlewares.AuthRequired, controller.FindPreferences)
+		notificationGroup.Put("/preferences/:type", middlewares.AuthRequired, controller.UpdatePreference)
+		notificationGroup.Put("/:notificationId/read", middlewares.AuthRequired, controller.MarkRead)
+		notificationGroup.Put("/:notificationId/unread", middlewares.AuthRequired, controller.MarkUnread)
+	}
+}
+
+func SetupRealtimeRoutes(app *fiber.App, controller controllers.RealtimeController) {
+	apiGroup := app.Group("/api")
+	realtimeGroup := apiGroup.Group("/realtime")
+	{
+		realtimeGroup.Get("/events", middlewares.AuthRequired, controller.Events)
+		realtimeGroup.Get("/ws", middlewares.AuthRequired, controller.WebSocket)
+	}
+}
+
+func SetupWebhookRoutes(app *fiber.App, controller controllers.WebhookController) {
+	apiGroup := app.Group("/api")
+	webhookGroup := apiGroup.Group("/webhooks")
+	{
+		webhookGroup.Get("/", middlewares.AdminOnly, controller.FindAll)
+		webhookGroup.Post("/", middlewares.AdminOnly, controller.Create)
+		webhookGroup.Get("/:webhookId", middlewares.AdminOnly, controller.FindByID)
+		webhookGroup.Put("/:webhookId", middlewares.AdminOnly, controller.Update)
+		webhookGroup.Delete("/:webhookId", middlewares.AdminOnly, controller.Delete)
+		webhookGroup.Get("/:webhookId/deliveries", middlewares.AdminOnly, controller.FindDeliveries)
+		webhookGroup.Get("/:webhookId/deliveries/:deliveryId", middlewares.AdminOnly, controller.FindDelivery)
+		webhookGroup.Post("/:webhookId/deliveries/:deliveryId/redeliver", middlewares.AdminOnly, controller.Redeliver)
+	}
+}
+
+func SetupBookmarkRoutes(app *fiber.App, controller controllers.BookmarkController) {
+	apiGroup := app.Group("/api")
+	bookmarkGroup := apiGroup.Group("/bookmarks")
+	{
+		bookmarkGroup.Get("/", middlewares.AuthRequired, controller.FindByToken)
+		bookmarkGroup.Post("/articles/:articleId", middlewares.AuthRequired, controller.Create)
+		bookmarkGroup.Delete("/articles/:articleId", middlewares.AuthRequired, controller.Delete)
+	}
+}
+
+func SetupReadingListRoutes(app *fiber.App, controller controllers.ReadingListController) {
+	apiGroup := app.Group("/api")
+	readingListGroup := apiGroup.Group("/reading_lists")
+	{
+		readingListGroup.Get("/", middlewares.AuthRequired, controller.FindByToken)
+		readingListGroup.Post("/", middlewares.AuthRequired, controller.Create)
+		readingListGroup.Get("/users/:userId", middlewares.AuthRequired, controller.FindByUserID)
+		readingListGroup.Get("/:readingListId", middlewares.AuthRequired, controller.FindByID)
+		readingListGroup.Put("/:readingListId", middlewares.AuthRequired, controller.Update)
+		readingListGroup.Delete("/:readingListId", middlewares.AuthRequired, controller.Delete)
+		readingListGroup.Post("/:readingListId/items", middlewares.AuthRequired, controller.CreateItem)
+		readingListGroup.Put("/:readingListId/items", middlewares.AuthRequired, controller.Reorder)
+		readingListGroup.Put("/:readingListId/items/:articleId", middlewares.AuthRequired, controller.UpdateItem)
+		readingListGroup.Delete("/:readingListId/items/:articleId", middlewares.AuthRequired, controller.DeleteItem)
+	}
 }
 
 func SetupCommentRoutes(app *fiber.App, controller controllers.CommentController) {
@@ -86,6 +193,54 @@ func SetupCommentRoutes(app *fiber.App, controller controllers.CommentController
 	{
 		likeGroup.Get("/articles/:articleId", middlewares.AuthRequired, controller.FindByArticleId)
 		likeGroup.Post("/articles/:articleId", middlewares.UserOnly, controller.Create)
+		likeGroup.Patch("/:commentId", middlewares.UserOnly, controller.Update)
 		likeGroup.Delete("/:commentId", middlewares.UserOnly, controller.Delete)
+		likeGroup.Get("/:commentId/revisions", middlewares.AdminOnly, controller.FindRevisionsById)
+	}
+}
+
+func SetupModerationRoutes(app *fiber.App, controller controllers.ModerationController) {
+	apiGroup := app.Group("/api")
+	apiGroup.Post("/reports", middlewares.AuthRequired, controller.CreateReport)
+
+	moderationGroup := apiGroup.Group("/moderation")
+	{
+		moderationGroup.Get("/queue", middlewares.AdminOnly, controller.FindQueue)
+		moderationGroup.Get("/actions", middlewares.AdminOnly, controller.FindActions)
+		moderationGroup.Post("/:targetType/:targetId/actions", middlewares.AdminOnly, controller.CreateAction)
+	}
+}
+
+func SetupTagRoutes(app *fiber.App, controller controllers.TagController) {
+	apiGroup := app.Group("/api")
+	tagGroup := apiGroup.Group("/tags")
+	{
+		tagGroup.Get("/", middlewares.AuthRequired, controller.FindAll)
+		tagGroup.Get("/:slug/articles", middlewares.AuthRequired, controller.FindArticlesBySlug)
+		tagGroup.Put("/:tagId", middlewares.AdminOnly, controller.Update)
+		tagGroup.Post("/:tagId/merge", middlewares.AdminOnly, controller.Merge)
+		tagGroup.Delete("/:tagId", middlewares.AdminOnly, controller.Delete)
+	}
+}
+
+func SetupCategoryRoutes(app *fiber.App, controller controllers.CategoryController) {
+	apiGroup := app.Group("/api")
+	categoryGroup := apiGroup.Group("/categories")
+	{
+		categoryGroup.Get("/", middlewares.AuthRequired, controller.FindAll)
+		categoryGroup.Get("/:categoryId/articles", middlewares.AuthRequired, controller.FindArticlesByID)
+		categoryGroup.Post("/", middlewares.AdminOnly, controller.Create)
+		categoryGroup.Put("/:categoryId", middlewares.AdminOnly, controller.Update)
+		categoryGroup.Delete("/:categoryId", middlewares.AdminOnly, controller.Delete)
+	}
+}
+
+// SetupFeedRoutes serves public feeds for feed readers, which cannot log in.
+func SetupFeedRoutes(app *fiber.App, controller controllers.FeedController) {
+	feedGroup := app.Group("/feeds")
+	{
+		feedGroup.Get("/articles.:format", controller.FindArticles)
+		feedGroup.Get("/authors/:userId/articles.:format", controller.FindArticlesByAuthor)
+		feedGroup.Get("/tags/:slug/articles.:format", controller.FindArticlesByTag)
 	}
 }
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"time"
+	"uaspw2/config"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+const (
+	analyticsDayLayout   = "2006-01-02"
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+)
+
+type ArticleAnalyticsService interface {
+	RecordView(ctx context.Context, request request.ArticleViewCreateRequest)
+	Rollup(ctx context.Context)
+	FindByArticleID(ctx context.Context, request request.ArticleAnalyticsRequest) response.ArticleAnalyticsResponse
+	FindByUserID(ctx context.Context, request request.ArticleAnalyticsRequest) []response.ArticleAnalyticsResponse
+}
+
+type ArticleAnalyticsServiceImpl struct {
+	repositories.ArticleAnalyticsRepository
+	ArticleRepository repositories.ArticleRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewArticleAnalyticsService(articleAnalyticsRepository repositories.ArticleAnalyticsRepository, articleRepository repositories.ArticleRepository, db *sql.DB, validate *validator.Validate) ArticleAnalyticsService {
+	return &ArticleAnalyticsServiceImpl{
+		ArticleAnalyticsRepository: articleAnalyticsRepository,
+		ArticleRepository:          articleRepository,
+		DB:                         db,
+		Validate:                   validate,
+	}
+}
+
+// RecordView stores a read of an article. Authors reading their own article
+// and repeated reads within config.ArticleViewWindow are not counted.
+func (service *ArticleAnalyticsServiceImpl) RecordView(ctx context.Context, request request.ArticleViewCreateRequest) {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	if request.UserId == request.AuthorId {
+		return
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.ArticleAnalyticsRepository.CreateView(ctx, tx, entity.ArticleView{
+		ArticleId: request.ArticleId,
+		UserId:    request.UserId,
+	}, config.ArticleViewWindow)
+}
+
+// Rollup recomputes the daily stats of yesterday and today, so events
+// recorded around midnight still end up in the right day. Days follow the
+// database clock, as the event timestamps do.
+func (service *ArticleAnalyticsServiceImpl) Rollup(ctx context.Context) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	today := parseAnalyticsDay(service.ArticleAnalyticsRepository.CurrentDay(ctx, tx))
+	service.ArticleAnalyticsRepository.RollupDay(ctx, tx, today.AddDate(0, 0, -1).Format(analyticsDayLayout))
+	service.ArticleAnalyticsRepository.RollupDay(ctx, tx, today.Format(analyticsDayLayout))
+}
+
+// RunArticleAnalyticsRollup aggregates article views, likes and comments into
+// daily stats every interval. A failed run is logged and retried on the next tick.
+func RunArticleAnalyticsRollup(service ArticleAnalyticsService, interval time.Duration) {
+	RunPeriodically("article analytics rollup", interval, service.Rollup)
+}
+
+func (service *ArticleAnalyticsServiceImpl) FindByArticleID(ctx context.Context, request request.ArticleAnalyticsRequest) response.ArticleAnalyticsResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	from, to := analyticsRange(service.ArticleAnalyticsRepository.CurrentDay(ctx, tx), request.From, request.To)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, request.ArticleId)
+	helper.PanicIfNotFound(err, "article not found")
+	if article.UserId != request.UserId {
+		panic(exception.NewInvalidCredentialsError("you cannot view analytics of another user article"))
+	}
+
+	stats, err := service.ArticleAnalyticsRepository.FindStatsByArticleID(ctx, tx, article.Id, from.Format(analyticsDayLayout), to.Format(analyticsDayLayout))
+	helper.PanicIfNotFound(err, "article not found")
+	daily := service.ArticleAnalyticsRepository.FindDailyByArticleID(ctx, tx, article.Id, from.Format(analyticsDayLayout), to.Format(analyticsDayLayout))
+
+	analyticsResponse := helper.ToArticleAnalyticsResponse(stats, from.Format(analyticsDayLayout), to.Format(analyticsDayLayout))
+	analyticsResponse.Daily = fillDailyStats(daily, from, to)
+	return analyticsResponse
+}
+
+func (service *ArticleAnalyticsServiceImpl) FindByUserID(ctx context.Context, request request.ArticleAnalyticsRequest) []response.ArticleAnalyticsResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	from, to := analyticsRange(service.ArticleAnalyticsRepository.CurrentDay(ctx, tx), request.From, request.To)
+
+	stats := service.ArticleAnalyticsRepository.FindStatsByUserID(ctx, tx, request.UserId, from.Format(analyticsDayLayout), to.Format(analyticsDayLayout))
+	return helper.ToArticleAnalyticsResponses(stats, from.Format(analyticsDayLayout), to.Format(analyticsDayLayout))
+}
+
+// analyticsRange parses the requested days, defaulting to the 30 days up to
+// today.
+func analyticsRange(today string, fromParam string, toParam string) (time.Time, time.Time) {
+	to := parseAnalyticsDay(today)
+	if toParam != "" {
+		to = parseAnalyticsDay(toParam)
+	}
+	from := to.AddDate(0, 0, 1-analyticsDefaultDays)
+	if fromParam != "" {
+		from = parseAnalyticsDay(fromParam)
+	}
+
+	if from.After(to) {
+		panic(exception.NewInvalidParameter("from must not be after to"))
+	}
+	if to.Sub(from) >= analyticsMaxDays*24*time.Hour {
+		panic(exception.NewInvalidParameter("date range is limited to 366 days"))
+	}
+	return from, to
+}
+
+// parseAnalyticsDay reads a YYYY-MM-DD day. Days are only used for calendar
+// arithmetic, so they are kept in UTC where every day has 24 hours.
+func parseAnalyticsDay(day string) time.Time {
+	parsed, err := time.Parse(analyticsDayLayout, day)
+	helper.PanicIfErr(err)
+	return parsed
+}
+
+// fillDailyStats returns one entry per day from..to, with zeros for days
+// without any activity.
+func fillDailyStats(daily []entity.ArticleStatsDaily, from time.Time, to time.Time) []response.ArticleStatsDailyResponse {
+	byDay := map[string]entity.ArticleStatsDaily{}
+	for _, stat := range daily {
+		byDay[stat.Day] = stat
+	}
+
+	var dailyResponses []response.ArticleStatsDailyResponse
+	last := to.Format(analyticsDayLayout)
+	for day := from; ; day = day.AddDate(0, 0, 1) {
+		key := day.Format(analyticsDayLayout)
+		stat := byDay[key]
+		stat.Day = key
+		dailyResponses = append(dailyResponses, helper.ToArticleStatsDailyResponse(stat))
+		if key == last {
+			break
+		}
+	}
+	return dailyResponses
+}
@@ -1,51 +1,114 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2/log"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+	"uaspw2/config"
+	"uaspw2/contentfilter"
 	"uaspw2/exception"
 	"uaspw2/helper"
 	"uaspw2/models/entity"
 	"uaspw2/models/web/request"
 	"uaspw2/models/web/response"
 	"uaspw2/repositories"
+	"uaspw2/storage"
 )
 
 type ArticleService interface {
-	Create(ctx context.Context, request request.ArticleCreateRequest, mediaRequests []request.ArticleMediaCreateRequest) response.ArticleResponse
+	Create(ctx context.Context, request request.ArticleCreateRequest) response.ArticleResponse
+	CreateMedia(ctx context.Context, request request.ArticleMediaCreateRequest) response.ArticleMediaResponse
+	DeleteMedia(ctx context.Context, articleId int, mediaId int, userId int)
 	Update(ctx context.Context, request request.ArticleUpdateRequest) response.ArticleResponse
+	Patch(ctx context.Context, request request.ArticlePatchRequest) response.ArticleResponse
 	Delete(ctx context.Context, articleId int)
+	DeleteInTx(ctx context.Context, tx *sql.Tx, articleId int) entity.Article
+	DeleteMediaFiles(ctx context.Context, media []entity.ArticleMedia)
 	FindByID(ctx context.Context, articleId int) response.ArticleResponse
+	FindBySlug(ctx context.Context, slug string) response.ArticleResponse
 	FindAllPublished(ctx context.Context) []response.ArticleResponse
+	FindFeed(ctx context.Context, request request.ArticleFeedRequest) response.ArticleFeedResponse
+	FindTimeline(ctx context.Context, request request.TimelineRequest) response.TimelineResponse
+	RefreshScores(ctx context.Context)
 	FindAllPublishedByUserID(ctx context.Context, userId int) []response.ArticleResponse
 	FindAllUnpublished(ctx context.Context) []response.ArticleResponse
 	FindAllUnpublishedByUserID(ctx context.Context, userId int) []response.ArticleResponse
-	UpdateStatusArticleByID(ctx context.Context, articleId int, status bool)
+	UpdateStatusArticleByID(ctx context.Context, articleId int, status bool, actorId int)
 }
 
 type ArticleServiceImpl struct {
 	repositories.ArticleRepository
+	TagRepository        repositories.TagRepository
+	CategoryRepository   repositories.CategoryRepository
+	ModerationRepository repositories.ModerationRepository
+	ContentFilter        contentfilter.Filter
+	MentionTracker       *MentionTracker
+	Notifier             *Notifier
+	WebhookDispatcher    *WebhookDispatcher
 	*sql.DB
 	*validator.Validate
+	Storage storage.Storage
 }
 
-func NewArticleService(articleRepository repositories.ArticleRepository, db *sql.DB, validate *validator.Validate) ArticleService {
+func NewArticleService(articleRepository repositories.ArticleRepository, tagRepository repositories.TagRepository, categoryRepository repositories.CategoryRepository, moderationRepository repositories.ModerationRepository, contentFilter contentfilter.Filter, mentionTracker *MentionTracker, notifier *Notifier, webhookDispatcher *WebhookDispatcher, db *sql.DB, validate *validator.Validate, storage storage.Storage) ArticleService {
 	return &ArticleServiceImpl{
-		ArticleRepository: articleRepository,
-		DB:                db,
-		Validate:          validate,
+		ArticleRepository:    articleRepository,
+		TagRepository:        tagRepository,
+		CategoryRepository:   categoryRepository,
+		ModerationRepository: moderationRepository,
+		ContentFilter:        contentFilter,
+		MentionTracker:       mentionTracker,
+		Notifier:             notifier,
+		WebhookDispatcher:    webhookDispatcher,
+		DB:                   db,
+		Validate:             validate,
+		Storage:              storage,
 	}
 }
 
-func (service *ArticleServiceImpl) UpdateStatusArticleByID(ctx context.Context, articleId int, status bool) {
+// UpdateStatusArticleByID publishes or unpublishes an article on behalf of
+// actorId and tells the author when the status actually changed.
+func (service *ArticleServiceImpl) UpdateStatusArticleByID(ctx context.Context, articleId int, status bool, actorId int) {
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
+	helper.PanicIfNotFound(err, "article not found")
 	service.ArticleRepository.UpdatePublishStatus(ctx, tx, articleId, status)
+
+	if status {
+		service.MentionTracker.Notify(ctx, tx, "article", article.Id, article.Id, article.UserId)
+	}
+	if status && !article.IsPublished {
+		service.WebhookDispatcher.Dispatch(ctx, tx, "article.published", service.toArticleResponse(service.reloadArticle(ctx, tx, article.Id)))
+		service.Notifier.NotifyFollowers(ctx, tx, article)
+	}
+	if article.IsPublished != status {
+		notificationType := "article_unpublished"
+		if status {
+			notificationType = "article_published"
+		}
+		service.Notifier.Notify(ctx, tx, entity.Notification{
+			UserId:     article.UserId,
+			ActorId:    actorId,
+			Type:       notificationType,
+			TargetType: "article",
+			TargetId:   article.Id,
+			ArticleId:  article.Id,
+		})
+	}
 }
 
-func (service *ArticleServiceImpl) Create(ctx context.Context, request request.ArticleCreateRequest, mediaRequests []request.ArticleMediaCreateRequest) response.ArticleResponse {
+func (service *ArticleServiceImpl) Create(ctx context.Context, request request.ArticleCreateRequest) response.ArticleResponse {
 	err := service.Validate.Struct(request)
 	helper.PanicIfErr(err)
 
@@ -61,21 +124,170 @@ func (service *ArticleServiceImpl) Create(ctx context.Context, request request.A
 		IsPublished: request.IsPublished,
 	}
 
-	data := service.ArticleRepository.Create(ctx, tx, req)
-	article, err := service.ArticleRepository.FindByID(ctx, tx, data.Id)
+	result := service.screenArticle(ctx, tx, req, false)
+	if result.Verdict == contentfilter.Hold {
+		req.IsPublished = false
+	}
+
+	var data entity.Article
+	helper.RetryOnDuplicateKey(slugAttempts, "could not find a free slug, try again", func(attempt int) {
+		req.Slug = service.uniqueSlug(ctx, tx, request.Title, 0, attempt)
+		data = service.ArticleRepository.Create(ctx, tx, req)
+	})
+	service.assignTaxonomy(ctx, tx, data.Id, request.Tags, request.CategoryIds)
+	if result.Verdict == contentfilter.Hold {
+		holdForModeration(ctx, tx, service.ModerationRepository, "article", data.Id, result)
+	}
+	service.MentionTracker.Sync(ctx, tx, "article", data.Id, data.Id, req.UserId, req.Description+"\n"+req.Content, req.IsPublished)
+
+	articleResponse := service.toArticleResponse(service.reloadArticle(ctx, tx, data.Id))
+	if req.IsPublished {
+		service.WebhookDispatcher.Dispatch(ctx, tx, "article.published", articleResponse)
+		service.Notifier.NotifyFollowers(ctx, tx, data)
+	}
+	return articleResponse
+}
+
+// assignTaxonomy replaces the tags and categories of an article. A nil slice
+// leaves the current assignment untouched, an empty slice clears it.
+func (service *ArticleServiceImpl) assignTaxonomy(ctx context.Context, tx *sql.Tx, articleId int, tagNames []string, categoryIds []int) {
+	if tagNames != nil {
+		service.TagRepository.DeleteFromArticle(ctx, tx, articleId)
+		for _, tag := range findOrCreateTags(ctx, tx, service.TagRepository, tagNames) {
+			service.TagRepository.AddToArticle(ctx, tx, articleId, tag.Id)
+		}
+	}
+
+	if categoryIds != nil {
+		service.CategoryRepository.DeleteFromArticle(ctx, tx, articleId)
+		for _, categoryId := range categoryIds {
+			_, err := service.CategoryRepository.FindByID(ctx, tx, categoryId)
+			helper.PanicIfNotFound(err, "category not found")
+			service.CategoryRepository.AddToArticle(ctx, tx, articleId, categoryId)
+		}
+	}
+}
+
+func (service *ArticleServiceImpl) loadTaxonomy(ctx context.Context, tx *sql.Tx, article *entity.Article) {
+	article.Tags = service.TagRepository.FindByArticleID(ctx, tx, article.Id)
+	article.Categories = service.CategoryRepository.FindByArticleID(ctx, tx, article.Id)
+	*article = loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, []entity.Article{*article})[0]
+}
+
+// loadDetails fills in the reaction and comment counts of articles and the
+// mentions content_html links to, with one query for each whatever the number
+// of articles.
+func loadDetails(ctx context.Context, tx *sql.Tx, articleRepository repositories.ArticleRepository, mentionRepository repositories.MentionRepository, articles []entity.Article) []entity.Article {
+	var articleIds []int
+	for _, article := range articles {
+		articleIds = append(articleIds, article.Id)
+	}
+	reactions := articleRepository.CountReactionsByArticleIDs(ctx, tx, articleIds)
+	comments := articleRepository.CountCommentsByArticleIDs(ctx, tx, articleIds)
+	mentions := mentionRepository.FindByTargets(ctx, tx, "article", articleIds)
+	for i := range articles {
+		articles[i].Reactions = reactions[articles[i].Id]
+		articles[i].CommentCount = comments[articles[i].Id]
+		articles[i].Mentions = mentions[articles[i].Id]
+	}
+	return articles
+}
 
+func (service *ArticleServiceImpl) CreateMedia(ctx context.Context, request request.ArticleMediaCreateRequest) response.ArticleMediaResponse {
+	err := service.Validate.Struct(request)
 	helper.PanicIfErr(err)
 
-	for _, media := range mediaRequests {
-		mediaReq := entity.ArticleMedia{
-			ArticleId: data.Id,
-			Type:      media.Type,
-			Path:      media.Path,
+	article := service.findArticle(ctx, request.ArticleId)
+	if article.UserId != request.UserId {
+		panic(exception.NewInvalidCredentialsError("you cannot add media to another user article"))
+	}
+
+	media := service.storeMedia(ctx, article.Id, request.File)
+
+	// the stored file must not outlive a failed insert
+	defer func() {
+		if err := recover(); err != nil {
+			service.DeleteMediaFiles(ctx, []entity.ArticleMedia{media})
+			panic(err)
 		}
-		service.ArticleRepository.CreateMedia(ctx, tx, mediaReq)
+	}()
+
+	media = service.createMedia(ctx, media)
+
+	return service.toArticleMediaResponse(media)
+}
+
+// storeMedia checks the uploaded file and puts it into storage. The returned
+// media is not saved yet.
+func (service *ArticleServiceImpl) storeMedia(ctx context.Context, articleId int, header *multipart.FileHeader) entity.ArticleMedia {
+	file, err := header.Open()
+	helper.PanicIfErr(err)
+	defer file.Close()
+
+	detected, err := helper.DetectArticleMedia(file, header.Size)
+	if err != nil {
+		panic(exception.NewInvalidParameter(err.Error()))
 	}
 
-	return helper.ToArticleResponse(article)
+	key := fmt.Sprintf("article_medias/%d/%d%s", articleId, time.Now().UnixNano(), detected.Extension)
+	err = service.Storage.Put(ctx, key, io.NewSectionReader(file, 0, header.Size), header.Size, detected.ContentType)
+	helper.PanicIfErr(err)
+
+	return entity.ArticleMedia{
+		ArticleId: articleId,
+		Type:      detected.Type,
+		Path:      key,
+	}
+}
+
+// DeleteMediaFiles removes media files from storage. Failures are only
+// logged: the database no longer points at these files.
+func (service *ArticleServiceImpl) DeleteMediaFiles(ctx context.Context, media []entity.ArticleMedia) {
+	for _, m := range media {
+		if err := service.Storage.Delete(ctx, m.Path); err != nil {
+			log.Warnf("failed to remove article media %s: %v", m.Path, err)
+		}
+	}
+}
+
+func (service *ArticleServiceImpl) createMedia(ctx context.Context, media entity.ArticleMedia) entity.ArticleMedia {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	media = service.ArticleRepository.CreateMedia(ctx, tx, media)
+	service.ArticleRepository.IncrementVersion(ctx, tx, media.ArticleId)
+	media, err = service.ArticleRepository.FindMediaByID(ctx, tx, media.Id)
+	helper.PanicIfNotFound(err, "article media not found")
+
+	return media
+}
+
+func (service *ArticleServiceImpl) DeleteMedia(ctx context.Context, articleId int, mediaId int, userId int) {
+	media := service.deleteMedia(ctx, articleId, mediaId, userId)
+	service.DeleteMediaFiles(ctx, []entity.ArticleMedia{media})
+}
+
+func (service *ArticleServiceImpl) deleteMedia(ctx context.Context, articleId int, mediaId int, userId int) entity.ArticleMedia {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
+	helper.PanicIfNotFound(err, "article not found")
+
+	if article.UserId != userId {
+		panic(exception.NewInvalidCredentialsError("you cannot delete another user article media"))
+	}
+
+	media, err := service.ArticleRepository.FindMediaByID(ctx, tx, mediaId)
+	if err != nil || media.ArticleId != article.Id {
+		panic(exception.NewNotFoundError("article media not found"))
+	}
+
+	service.ArticleRepository.DeleteMedia(ctx, tx, media.Id)
+	service.ArticleRepository.IncrementVersion(ctx, tx, article.Id)
+	return media
 }
 
 func (service *ArticleServiceImpl) Update(ctx context.Context, request request.ArticleUpdateRequest) response.ArticleResponse {
@@ -86,50 +298,329 @@ func (service *ArticleServiceImpl) Update(ctx context.Context, request request.A
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
-	req := entity.Article{
-		Id:          request.Id,
-		UserId:      request.UserId,
-		Title:       request.Title,
-		Description: request.Description,
-		IsPublished: false,
+	article, err := service.ArticleRepository.FindByID(ctx, tx, request.Id)
+	helper.PanicIfNotFound(err, "article not found")
+
+	if article.UserId != request.UserId {
+		panic(exception.NewInvalidCredentialsError("unauthorized"))
 	}
+	checkArticleVersion(article, request.Version)
 
-	article, err := service.ArticleRepository.FindByID(ctx, tx, req.Id)
-	helper.PanicIfNotFound(err, "article not found")
+	article.Title = request.Title
+	article.Description = request.Description
+	article.Content = request.Content
+	service.saveArticle(ctx, tx, article)
+	service.assignTaxonomy(ctx, tx, article.Id, request.Tags, request.CategoryIds)
 
-	if article.UserId != req.UserId {
+	return service.toArticleResponse(service.reloadArticle(ctx, tx, article.Id))
+}
+
+// Patch applies a JSON Merge Patch (RFC 7396) to the editable fields of an
+// article, see request.ArticleDocument. Media missing from the patched
+// media_ids are removed and uploaded files are added in the same operation.
+func (service *ArticleServiceImpl) Patch(ctx context.Context, request request.ArticlePatchRequest) response.ArticleResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	article := service.findArticle(ctx, request.Id)
+	if article.UserId != request.UserId {
 		panic(exception.NewInvalidCredentialsError("unauthorized"))
 	}
+	// checked again under the transaction, this only avoids a useless upload
+	checkArticleVersion(article, request.Version)
+
+	var added []entity.ArticleMedia
+	defer func() {
+		if err := recover(); err != nil {
+			service.DeleteMediaFiles(ctx, added)
+			panic(err)
+		}
+	}()
+	for _, file := range request.Media {
+		added = append(added, service.storeMedia(ctx, article.Id, file))
+	}
+
+	article, removed := service.patchArticle(ctx, request, added)
+	service.DeleteMediaFiles(ctx, removed)
+
+	return service.toArticleResponse(article)
+}
+
+func (service *ArticleServiceImpl) patchArticle(ctx context.Context, patchRequest request.ArticlePatchRequest, added []entity.ArticleMedia) (entity.Article, []entity.ArticleMedia) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, patchRequest.Id)
+	helper.PanicIfNotFound(err, "article not found")
+	checkArticleVersion(article, patchRequest.Version)
+	service.loadTaxonomy(ctx, tx, &article)
+
+	document := toArticleDocument(article)
+	applyMergePatch(&document, patchRequest.Patch)
+	err = service.Validate.Struct(document)
+	helper.PanicIfErr(err)
+
+	keep := map[int]bool{}
+	for _, mediaId := range document.MediaIds {
+		keep[mediaId] = true
+	}
+	var removed []entity.ArticleMedia
+	for _, media := range article.Media {
+		if keep[media.Id] {
+			delete(keep, media.Id)
+			continue
+		}
+		service.ArticleRepository.DeleteMedia(ctx, tx, media.Id)
+		removed = append(removed, media)
+	}
+	if len(keep) > 0 {
+		panic(exception.NewInvalidParameter("media_ids may only contain media of this article"))
+	}
+	for _, media := range added {
+		service.ArticleRepository.CreateMedia(ctx, tx, media)
+	}
 
-	articleResponse := service.ArticleRepository.Update(ctx, tx, req)
+	article.Title = document.Title
+	article.Description = document.Description
+	article.Content = document.Content
+	service.saveArticle(ctx, tx, article)
+	// a null or empty list clears the assignment
+	service.assignTaxonomy(ctx, tx, article.Id, append([]string{}, document.Tags...), append([]int{}, document.CategoryIds...))
 
-	articleResponse.CreatedAt = article.CreatedAt
-	articleResponse.UpdatedAt = article.UpdatedAt
+	return service.reloadArticle(ctx, tx, article.Id), removed
+}
+
+// toArticleDocument returns the editable representation a merge patch is applied to.
+func toArticleDocument(article entity.Article) request.ArticleDocument {
+	document := request.ArticleDocument{
+		Title:       article.Title,
+		Description: article.Description,
+		Content:     article.Content,
+		Tags:        []string{},
+		CategoryIds: []int{},
+		MediaIds:    []int{},
+	}
+	for _, tag := range article.Tags {
+		document.Tags = append(document.Tags, tag.Name)
+	}
+	for _, category := range article.Categories {
+		document.CategoryIds = append(document.CategoryIds, category.Id)
+	}
+	for _, media := range article.Media {
+		document.MediaIds = append(document.MediaIds, media.Id)
+	}
+	return document
+}
+
+func applyMergePatch(document *request.ArticleDocument, patch []byte) {
+	var target any
+	current, err := json.Marshal(document)
+	helper.PanicIfErr(err)
+	err = json.Unmarshal(current, &target)
+	helper.PanicIfErr(err)
+
+	var patchValue any
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		panic(exception.NewInvalidParameter("invalid merge patch (malformed JSON)"))
+	}
+	if _, ok := patchValue.(map[string]any); !ok {
+		panic(exception.NewInvalidParameter("invalid merge patch (must be a JSON object)"))
+	}
+
+	merged, err := json.Marshal(helper.MergePatch(target, patchValue))
+	helper.PanicIfErr(err)
+
+	*document = request.ArticleDocument{}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(document); err != nil {
+		panic(exception.NewInvalidParameter("invalid merge patch: " + err.Error()))
+	}
+}
+
+// checkArticleVersion compares the version read from If-Match with the stored
+// one. Version 0 stands for "If-Match: *" and matches any version.
+func checkArticleVersion(article entity.Article, version int) {
+	if version != 0 && article.Version != version {
+		panic(exception.NewPreconditionFailedError("article has been modified, reload it and try again"))
+	}
+}
+
+// saveArticle writes the editable fields and moves the slug along with the
+// title. The publish status is left alone, it has its own endpoints, unless
+// the content filter holds the new text for moderation.
+func (service *ArticleServiceImpl) saveArticle(ctx context.Context, tx *sql.Tx, article entity.Article) {
+	result := service.screenArticle(ctx, tx, article, true)
+
+	oldSlug := article.Slug
+	moveSlug := !slugMatchesTitle(article.Slug, article.Title)
+	helper.RetryOnDuplicateKey(slugAttempts, "could not find a free slug, try again", func(attempt int) {
+		if moveSlug {
+			article.Slug = service.uniqueSlug(ctx, tx, article.Title, article.Id, attempt)
+		}
+		_, err := service.ArticleRepository.Update(ctx, tx, article)
+		if err != nil {
+			panic(exception.NewPreconditionFailedError(err.Error()))
+		}
+	})
+	if moveSlug {
+		// keep the old permalink working
+		service.ArticleRepository.DeleteSlugRedirect(ctx, tx, article.Slug)
+		service.ArticleRepository.CreateSlugRedirect(ctx, tx, article.Id, oldSlug)
+	}
 
-	return helper.ToArticleResponse(articleResponse)
+	if result.Verdict == contentfilter.Hold {
+		if article.IsPublished {
+			service.ArticleRepository.UpdatePublishStatus(ctx, tx, article.Id, false)
+		}
+		holdForModeration(ctx, tx, service.ModerationRepository, "article", article.Id, result)
+	}
+	service.MentionTracker.Sync(ctx, tx, "article", article.Id, article.Id, article.UserId, article.Description+"\n"+article.Content, article.IsPublished && result.Verdict != contentfilter.Hold)
+}
+
+func (service *ArticleServiceImpl) screenArticle(ctx context.Context, tx *sql.Tx, article entity.Article, isEdit bool) contentfilter.Result {
+	return screenContent(ctx, tx, service.ContentFilter, contentfilter.Content{
+		UserId: article.UserId,
+		Kind:   "article",
+		Text:   article.Title + "\n" + article.Description + "\n" + article.Content,
+		IsEdit: isEdit,
+	})
+}
+
+func (service *ArticleServiceImpl) reloadArticle(ctx context.Context, tx *sql.Tx, articleId int) entity.Article {
+	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
+	helper.PanicIfNotFound(err, "article not found")
+	service.loadTaxonomy(ctx, tx, &article)
+	return article
 }
 
 func (service *ArticleServiceImpl) Delete(ctx context.Context, articleId int) {
+	article := service.deleteArticle(ctx, articleId)
+
+	// article_medias rows are removed by ON DELETE CASCADE, the files are not
+	service.DeleteMediaFiles(ctx, article.Media)
+}
+
+func (service *ArticleServiceImpl) deleteArticle(ctx context.Context, articleId int) entity.Article {
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
+	return service.DeleteInTx(ctx, tx, articleId)
+}
+
+// DeleteInTx deletes an article as part of the caller's transaction. Its
+// media files stay in storage; pass the returned article's Media to
+// DeleteMediaFiles once tx has committed.
+func (service *ArticleServiceImpl) DeleteInTx(ctx context.Context, tx *sql.Tx, articleId int) entity.Article {
 	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
 	if err != nil {
 		panic(exception.NewNotFoundError(err.Error()))
 	}
+	service.loadTaxonomy(ctx, tx, &article)
+	service.WebhookDispatcher.Dispatch(ctx, tx, "article.deleted", service.toArticleResponse(article))
+
 	service.ArticleRepository.Delete(ctx, tx, article.Id)
+	service.MentionTracker.Forget(ctx, tx, "article", article.Id)
+	return article
 }
 
 func (service *ArticleServiceImpl) FindByID(ctx context.Context, articleId int) response.ArticleResponse {
+	return service.toArticleResponse(service.findArticle(ctx, articleId))
+}
+
+// FindBySlug also resolves old slugs; the returned article then carries its
+// current slug, which the caller can use to redirect.
+func (service *ArticleServiceImpl) FindBySlug(ctx context.Context, slug string) response.ArticleResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	article, err := service.ArticleRepository.FindBySlug(ctx, tx, slug)
+	if err != nil {
+		articleId, err := service.ArticleRepository.FindIDBySlugRedirect(ctx, tx, slug)
+		helper.PanicIfNotFound(err, "article not found")
+
+		article, err = service.ArticleRepository.FindByID(ctx, tx, articleId)
+		helper.PanicIfNotFound(err, "article not found")
+	}
+	service.loadTaxonomy(ctx, tx, &article)
+
+	return service.toArticleResponse(article)
+}
+
+// slugAttempts bounds how often a slug is regenerated after another request
+// took it between the check in uniqueSlug and the write.
+const slugAttempts = 5
+
+// uniqueSlug derives a slug from title, appending -2, -3, ... until it is not
+// used by any other article, including old slugs kept for redirects. The
+// transaction snapshot does not show slugs committed since it started, so
+// after a collision the first skip candidates are passed over.
+func (service *ArticleServiceImpl) uniqueSlug(ctx context.Context, tx *sql.Tx, title string, articleId int, skip int) string {
+	base := articleSlugBase(title)
+	slug := base
+	if skip > 0 {
+		slug = fmt.Sprintf("%s-%d", base, skip+1)
+	}
+	for n := skip + 2; service.ArticleRepository.IsSlugTaken(ctx, tx, slug, articleId); n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug
+}
+
+func articleSlugBase(title string) string {
+	base := helper.TruncateSlug(helper.Slugify(title), 240)
+	if base == "" {
+		return "article"
+	}
+	return base
+}
+
+// slugMatchesTitle reports whether slug was generated from title, with or
+// without a de-duplication suffix, so unrelated edits keep the permalink.
+func slugMatchesTitle(slug string, title string) bool {
+	base := articleSlugBase(title)
+	if slug == base {
+		return true
+	}
+	suffix, found := strings.CutPrefix(slug, base+"-")
+	if !found || suffix == "" {
+		return false
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (service *ArticleServiceImpl) findArticle(ctx context.Context, articleId int) entity.Article {
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
 	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
 	helper.PanicIfNotFound(err, "article not found")
+	service.loadTaxonomy(ctx, tx, &article)
+
+	return article
+}
+
+func (service *ArticleServiceImpl) toArticleResponse(article entity.Article) response.ArticleResponse {
+	articleResponse := helper.ToArticleResponse(article)
+	for i := range articleResponse.Media {
+		articleResponse.Media[i].Url = service.Storage.URL(articleResponse.Media[i].Path)
+	}
+	return articleResponse
+}
 
-	return helper.ToArticleResponse(article)
+func (service *ArticleServiceImpl) toArticleMediaResponse(media entity.ArticleMedia) response.ArticleMediaResponse {
+	mediaResponse := helper.ToArticleMediaResponse(media)
+	mediaResponse.Url = service.Storage.URL(media.Path)
+	return mediaResponse
 }
 
 func (service *ArticleServiceImpl) FindAllPublished(ctx context.Context) []response.ArticleResponse {
@@ -139,7 +630,66 @@ func (service *ArticleServiceImpl) FindAllPublished(ctx context.Context) []respo
 
 	articles := service.ArticleRepository.FindAllByPublishStatus(ctx, tx, true)
 
-	return helper.ToArticleResponses(articles)
+	return helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, articles))
+}
+
+func (service *ArticleServiceImpl) FindFeed(ctx context.Context, request request.ArticleFeedRequest) response.ArticleFeedResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	// one extra row tells whether there is a next page
+	articles := service.ArticleRepository.FindAllPublishedRanked(ctx, tx, request.Sort, request.Period, request.PerPage+1, (request.Page-1)*request.PerPage)
+	hasMore := len(articles) > request.PerPage
+	if hasMore {
+		articles = articles[:request.PerPage]
+	}
+
+	feedResponse := response.ArticleFeedResponse{
+		Sort:     request.Sort,
+		Page:     request.Page,
+		PerPage:  request.PerPage,
+		HasMore:  hasMore,
+		Articles: helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, articles)),
+	}
+	if request.Sort == "top" {
+		feedResponse.Period = request.Period
+	}
+	return feedResponse
+}
+
+func (service *ArticleServiceImpl) FindTimeline(ctx context.Context, request request.TimelineRequest) response.TimelineResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	beforeCreatedAt, beforeId := helper.DecodeCursor(request.Cursor)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	// one extra row tells whether there is a next page
+	articles := service.ArticleRepository.FindTimeline(ctx, tx, request.UserId, beforeCreatedAt, beforeId, request.Limit+1)
+
+	timelineResponse := response.TimelineResponse{}
+	if len(articles) > request.Limit {
+		articles = articles[:request.Limit]
+		last := articles[len(articles)-1]
+		timelineResponse.NextCursor = helper.EncodeCursor(last.CreatedAt, last.Id)
+	}
+	timelineResponse.Articles = helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, articles))
+	return timelineResponse
+}
+
+// RefreshScores recomputes the ranking used by the trending and top feeds.
+func (service *ArticleServiceImpl) RefreshScores(ctx context.Context) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.ArticleRepository.RefreshScores(ctx, tx, config.ArticleTrendingHalfLife)
 }
 
 func (service *ArticleServiceImpl) FindAllPublishedByUserID(ctx context.Context, userId int) []response.ArticleResponse {
@@ -149,7 +699,7 @@ func (service *ArticleServiceImpl) FindAllPublishedByUserID(ctx context.Context,
 
 	articles := service.ArticleRepository.FindAllByPublishStatusAndUserID(ctx, tx, true, userId)
 
-	return helper.ToArticleResponses(articles)
+	return helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, articles))
 }
 
 func (service *ArticleServiceImpl) FindAllUnpublished(ctx context.Context) []response.ArticleResponse {
@@ -159,7 +709,7 @@ func (service *ArticleServiceImpl) FindAllUnpublished(ctx context.Context) []res
 
 	articles := service.ArticleRepository.FindAllByPublishStatus(ctx, tx, false)
 
-	return helper.ToArticleResponses(articles)
+	return helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, articles))
 }
 
 func (service *ArticleServiceImpl) FindAllUnpublishedByUserID(ctx context.Context, userId int) []response.ArticleResponse {
@@ -169,5 +719,5 @@ func (service *ArticleServiceImpl) FindAllUnpublishedByUserID(ctx context.Contex
 
 	articles := service.ArticleRepository.FindAllByPublishStatusAndUserID(ctx, tx, false, userId)
 
-	return helper.ToArticleResponses(articles)
+	return helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionTracker.MentionRepository, articles))
 }
@@ -21,16 +21,18 @@ type AuthService interface {
 }
 
 type AuthServicesImpl struct {
-	AuthRepository repositories.AuthRepository
-	DB             *sql.DB
-	Validate       *validator.Validate
+	AuthRepository    repositories.AuthRepository
+	WebhookDispatcher *WebhookDispatcher
+	DB                *sql.DB
+	Validate          *validator.Validate
 }
 
-func NewAuthenticationServices(authRepository repositories.AuthRepository, db *sql.DB, validate *validator.Validate) AuthService {
+func NewAuthenticationServices(authRepository repositories.AuthRepository, webhookDispatcher *WebhookDispatcher, db *sql.DB, validate *validator.Validate) AuthService {
 	return &AuthServicesImpl{
-		AuthRepository: authRepository,
-		DB:             db,
-		Validate:       validate,
+		AuthRepository:    authRepository,
+		WebhookDispatcher: webhookDispatcher,
+		DB:                db,
+		Validate:          validate,
 	}
 }
 
@@ -50,6 +52,10 @@ func (service *AuthServicesImpl) Login(ctx context.Context, request request.Logi
 	}
 
 	if helper.CheckPasswordHash(request.Password, user.Password) {
+		if user.IsSuspended {
+			panic(exception.NewInvalidCredentialsError("your account is suspended"))
+		}
+
 		//generate token
 		claims := config.UserClaims{
 			Id:       user.Id,
@@ -95,12 +101,14 @@ func (service *AuthServicesImpl) RegisterUser(ctx context.Context, request reque
 	service.AuthRepository.CreateUserProfileOnRegisterUser(ctx, tx, req.Id, request.FullName)
 	defaultPhotoProfile := entity.UserProfilePhoto{
 		UserId: req.Id,
-		Path:   "default_profile_photo.svg",
+		Path:   DefaultProfilePhoto,
 	}
 
 	service.AuthRepository.CreateUserPhotoProfileOnRegisterUser(ctx, tx, defaultPhotoProfile)
 
 	userResponse, _ := service.AuthRepository.GetUserByUsername(ctx, tx, req.Username)
+	registered := helper.ToUserWithProfileResponse(userResponse)
+	service.WebhookDispatcher.Dispatch(ctx, tx, "user.registered", registered)
 
-	return helper.ToUserWithProfileResponse(userResponse)
+	return registered
 }
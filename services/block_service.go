@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type BlockService interface {
+	Create(ctx context.Context, request request.BlockRequest) response.BlockResponse
+	Delete(ctx context.Context, request request.BlockRequest)
+	FindBlockedUsers(ctx context.Context, userId int) []response.BlockedUserResponse
+}
+
+type BlockServiceImpl struct {
+	repositories.BlockRepository
+	UserRepository repositories.UserRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewBlockService(blockRepository repositories.BlockRepository, userRepository repositories.UserRepository, db *sql.DB, validate *validator.Validate) BlockService {
+	return &BlockServiceImpl{
+		BlockRepository: blockRepository,
+		UserRepository:  userRepository,
+		DB:              db,
+		Validate:        validate,
+	}
+}
+
+func (service *BlockServiceImpl) Create(ctx context.Context, request request.BlockRequest) response.BlockResponse {
+	if request.BlockerId == request.BlockedId {
+		panic(exception.NewInvalidParameter("you cannot block yourself"))
+	}
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.UserRepository.FindByID(ctx, tx, request.BlockedId)
+	helper.PanicIfNotFound(err, "user not found")
+
+	if _, err := service.BlockRepository.FindByID(ctx, tx, request.BlockerId, request.BlockedId); err == nil {
+		panic(exception.NewInvalidParameter("you already blocked this user"))
+	}
+
+	service.BlockRepository.Create(ctx, tx, entity.Block{
+		BlockerId: request.BlockerId,
+		BlockedId: request.BlockedId,
+	})
+	block, err := service.BlockRepository.FindByID(ctx, tx, request.BlockerId, request.BlockedId)
+	helper.PanicIfNotFound(err, "block not found")
+
+	return helper.ToBlockResponse(block)
+}
+
+func (service *BlockServiceImpl) Delete(ctx context.Context, request request.BlockRequest) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.BlockRepository.FindByID(ctx, tx, request.BlockerId, request.BlockedId)
+	helper.PanicIfNotFound(err, "block not found")
+
+	service.BlockRepository.Delete(ctx, tx, request.BlockerId, request.BlockedId)
+}
+
+func (service *BlockServiceImpl) FindBlockedUsers(ctx context.Context, userId int) []response.BlockedUserResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	users := service.BlockRepository.FindBlockedUsers(ctx, tx, userId)
+	return helper.ToBlockedUserResponses(users)
+}
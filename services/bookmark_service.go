@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type BookmarkService interface {
+	Create(ctx context.Context, request request.BookmarkRequest) response.BookmarkResponse
+	Delete(ctx context.Context, request request.BookmarkRequest)
+	FindArticlesByUserID(ctx context.Context, userId int) []response.ArticleResponse
+	MarkBookmarked(ctx context.Context, userId int, articles []response.ArticleResponse)
+}
+
+type BookmarkServiceImpl struct {
+	repositories.BookmarkRepository
+	ArticleRepository repositories.ArticleRepository
+	MentionRepository repositories.MentionRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewBookmarkService(bookmarkRepository repositories.BookmarkRepository, articleRepository repositories.ArticleRepository, mentionRepository repositories.MentionRepository, db *sql.DB, validate *validator.Validate) BookmarkService {
+	return &BookmarkServiceImpl{
+		BookmarkRepository: bookmarkRepository,
+		ArticleRepository:  articleRepository,
+		MentionRepository:  mentionRepository,
+		DB:                 db,
+		Validate:           validate,
+	}
+}
+
+func (service *BookmarkServiceImpl) Create(ctx context.Context, request request.BookmarkRequest) response.BookmarkResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, request.ArticleId)
+	if err != nil || !article.IsPublished {
+		panic(exception.NewNotFoundError("article not found"))
+	}
+
+	if _, err := service.BookmarkRepository.FindByUserAndArticle(ctx, tx, request.UserId, request.ArticleId); err == nil {
+		panic(exception.NewInvalidParameter("article is already bookmarked"))
+	}
+
+	service.BookmarkRepository.Create(ctx, tx, entity.Bookmark{
+		UserId:    request.UserId,
+		ArticleId: request.ArticleId,
+	})
+	bookmark, err := service.BookmarkRepository.FindByUserAndArticle(ctx, tx, request.UserId, request.ArticleId)
+	helper.PanicIfNotFound(err, "bookmark not found")
+
+	return helper.ToBookmarkResponse(bookmark)
+}
+
+func (service *BookmarkServiceImpl) Delete(ctx context.Context, request request.BookmarkRequest) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.BookmarkRepository.FindByUserAndArticle(ctx, tx, request.UserId, request.ArticleId)
+	helper.PanicIfNotFound(err, "bookmark not found")
+
+	service.BookmarkRepository.Delete(ctx, tx, request.UserId, request.ArticleId)
+}
+
+func (service *BookmarkServiceImpl) FindArticlesByUserID(ctx context.Context, userId int) []response.ArticleResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	articles := helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionRepository, service.BookmarkRepository.FindArticlesByUserID(ctx, tx, userId)))
+	for i := range articles {
+		articles[i].IsBookmarked = true
+	}
+	return articles
+}
+
+// MarkBookmarked sets IsBookmarked on the articles userId has bookmarked.
+func (service *BookmarkServiceImpl) MarkBookmarked(ctx context.Context, userId int, articles []response.ArticleResponse) {
+	if len(articles) == 0 {
+		return
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	var articleIds []int
+	for _, article := range articles {
+		articleIds = append(articleIds, article.Id)
+	}
+	bookmarked := service.BookmarkRepository.FindBookmarkedArticleIDs(ctx, tx, userId, articleIds)
+	for i := range articles {
+		articles[i].IsBookmarked = bookmarked[articles[i].Id]
+	}
+}
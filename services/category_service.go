@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, request request.CategoryCreateRequest) response.CategoryResponse
+	Update(ctx context.Context, request request.CategoryUpdateRequest) response.CategoryResponse
+	Delete(ctx context.Context, categoryId int)
+	FindAll(ctx context.Context) []response.CategoryResponse
+	FindArticlesByID(ctx context.Context, request request.CategoryArticlesRequest) response.CategoryArticlesResponse
+}
+
+type CategoryServiceImpl struct {
+	repositories.CategoryRepository
+	ArticleRepository repositories.ArticleRepository
+	MentionRepository repositories.MentionRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewCategoryService(categoryRepository repositories.CategoryRepository, articleRepository repositories.ArticleRepository, mentionRepository repositories.MentionRepository, db *sql.DB, validate *validator.Validate) CategoryService {
+	return &CategoryServiceImpl{
+		CategoryRepository: categoryRepository,
+		ArticleRepository:  articleRepository,
+		MentionRepository:  mentionRepository,
+		DB:                 db,
+		Validate:           validate,
+	}
+}
+
+func (service *CategoryServiceImpl) Create(ctx context.Context, request request.CategoryCreateRequest) response.CategoryResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	if request.ParentId != 0 {
+		_, err := service.CategoryRepository.FindByID(ctx, tx, request.ParentId)
+		helper.PanicIfNotFound(err, "parent category not found")
+	}
+
+	var category entity.Category
+	helper.RetryOnDuplicateKey(1, "category "+request.Name+" already exists", func(int) {
+		category = service.CategoryRepository.Create(ctx, tx, entity.Category{
+			ParentId: request.ParentId,
+			Name:     request.Name,
+			Slug:     service.categorySlug(ctx, tx, request.Name, 0),
+		})
+	})
+
+	category, err = service.CategoryRepository.FindByID(ctx, tx, category.Id)
+	helper.PanicIfErr(err)
+
+	return helper.ToCategoryResponse(category)
+}
+
+func (service *CategoryServiceImpl) Update(ctx context.Context, request request.CategoryUpdateRequest) response.CategoryResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	category, err := service.CategoryRepository.FindByID(ctx, tx, request.Id)
+	helper.PanicIfNotFound(err, "category not found")
+
+	// walk up from the new parent to make sure the category does not become its own ancestor
+	for parentId := request.ParentId; parentId != 0; {
+		if parentId == category.Id {
+			panic(exception.NewInvalidParameter("a category cannot be moved under itself"))
+		}
+		parent, err := service.CategoryRepository.FindByID(ctx, tx, parentId)
+		helper.PanicIfNotFound(err, "parent category not found")
+		parentId = parent.ParentId
+	}
+
+	category.ParentId = request.ParentId
+	category.Name = request.Name
+	category.Slug = service.categorySlug(ctx, tx, request.Name, category.Id)
+	helper.RetryOnDuplicateKey(1, "category "+request.Name+" already exists", func(int) {
+		service.CategoryRepository.Update(ctx, tx, category)
+	})
+
+	category, err = service.CategoryRepository.FindByID(ctx, tx, category.Id)
+	helper.PanicIfErr(err)
+
+	return helper.ToCategoryResponse(category)
+}
+
+func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	category, err := service.CategoryRepository.FindByID(ctx, tx, categoryId)
+	helper.PanicIfNotFound(err, "category not found")
+
+	service.CategoryRepository.Delete(ctx, tx, category.Id)
+}
+
+// FindAll returns the category tree. Each article_count includes the
+// articles of all subcategories.
+func (service *CategoryServiceImpl) FindAll(ctx context.Context) []response.CategoryResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	categories := service.CategoryRepository.FindAll(ctx, tx)
+	return buildCategoryTree(categories, 0)
+}
+
+func (service *CategoryServiceImpl) FindArticlesByID(ctx context.Context, request request.CategoryArticlesRequest) response.CategoryArticlesResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	beforeCreatedAt, beforeId := helper.DecodeCursor(request.Cursor)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	categories := service.CategoryRepository.FindAll(ctx, tx)
+
+	var category *response.CategoryResponse
+	for _, root := range buildCategoryTree(categories, 0) {
+		if category = findCategory(root, request.CategoryId); category != nil {
+			break
+		}
+	}
+	if category == nil {
+		panic(exception.NewNotFoundError("category not found"))
+	}
+
+	// one extra row tells whether there is a next page
+	articles := service.ArticleRepository.FindAllPublishedByCategoryIDs(ctx, tx, categoryIds(*category), beforeCreatedAt, beforeId, request.Limit+1)
+
+	categoryArticlesResponse := response.CategoryArticlesResponse{Category: *category}
+	if len(articles) > request.Limit {
+		articles = articles[:request.Limit]
+		last := articles[len(articles)-1]
+		categoryArticlesResponse.NextCursor = helper.EncodeCursor(last.CreatedAt, last.Id)
+	}
+	categoryArticlesResponse.Articles = helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionRepository, articles))
+	return categoryArticlesResponse
+}
+
+func (service *CategoryServiceImpl) categorySlug(ctx context.Context, tx *sql.Tx, name string, categoryId int) string {
+	slug := helper.TruncateSlug(helper.Slugify(name), 128)
+	if slug == "" {
+		panic(exception.NewInvalidParameter("category name must contain letters or digits"))
+	}
+	if category, err := service.CategoryRepository.FindBySlug(ctx, tx, slug); err == nil && category.Id != categoryId {
+		panic(exception.NewInvalidParameter("category " + category.Name + " already exists"))
+	}
+	return slug
+}
+
+func buildCategoryTree(categories []entity.Category, parentId int) []response.CategoryResponse {
+	var tree []response.CategoryResponse
+	for _, category := range categories {
+		if category.ParentId != parentId {
+			continue
+		}
+		node := helper.ToCategoryResponse(category)
+		node.Children = buildCategoryTree(categories, category.Id)
+		for _, child := range node.Children {
+			node.ArticleCount += child.ArticleCount
+		}
+		tree = append(tree, node)
+	}
+	return tree
+}
+
+func findCategory(node response.CategoryResponse, categoryId int) *response.CategoryResponse {
+	if node.Id == categoryId {
+		return &node
+	}
+	for _, child := range node.Children {
+		if found := findCategory(child, categoryId); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func categoryIds(node response.CategoryResponse) []int {
+	ids := []int{node.Id}
+	for _, child := range node.Children {
+		ids = append(ids, categoryIds(child)...)
+	}
+	return ids
+}
@@ -3,33 +3,53 @@ package services
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/go-playground/validator/v10"
-	"github.com/gofiber/fiber/v2/log"
+	"time"
+	"uaspw2/config"
+	"uaspw2/contentfilter"
 	"uaspw2/exception"
 	"uaspw2/helper"
 	"uaspw2/models/entity"
 	"uaspw2/models/web/request"
 	"uaspw2/models/web/response"
+	"uaspw2/realtime"
 	"uaspw2/repositories"
 )
 
 type CommentService interface {
 	Create(ctx context.Context, request request.CommentRequest) response.CommentResponse
-	FindByArticleID(ctx context.Context, articleId int) []response.CommentResponse
+	Update(ctx context.Context, request request.CommentUpdateRequest) response.CommentResponse
+	FindByArticleID(ctx context.Context, articleId int, includeHidden bool) []response.CommentResponse
+	FindRevisionsByID(ctx context.Context, commentId int) []response.CommentRevisionResponse
 	Delete(ctx context.Context, commentId int, userId int)
 }
 
 type CommentServiceImpl struct {
 	repositories.CommentRepository
+	ArticleRepository    repositories.ArticleRepository
+	ModerationRepository repositories.ModerationRepository
+	ContentFilter        contentfilter.Filter
+	MentionTracker       *MentionTracker
+	Notifier             *Notifier
+	Publisher            realtime.Publisher
+	WebhookDispatcher    *WebhookDispatcher
 	*sql.DB
 	*validator.Validate
 }
 
-func NewCommentService(commentRepository repositories.CommentRepository, db *sql.DB, validate *validator.Validate) *CommentServiceImpl {
+func NewCommentService(commentRepository repositories.CommentRepository, articleRepository repositories.ArticleRepository, moderationRepository repositories.ModerationRepository, contentFilter contentfilter.Filter, mentionTracker *MentionTracker, notifier *Notifier, publisher realtime.Publisher, webhookDispatcher *WebhookDispatcher, db *sql.DB, validate *validator.Validate) *CommentServiceImpl {
 	return &CommentServiceImpl{
-		CommentRepository: commentRepository,
-		DB:                db,
-		Validate:          validate,
+		CommentRepository:    commentRepository,
+		ArticleRepository:    articleRepository,
+		ModerationRepository: moderationRepository,
+		ContentFilter:        contentFilter,
+		MentionTracker:       mentionTracker,
+		Notifier:             notifier,
+		Publisher:            publisher,
+		WebhookDispatcher:    webhookDispatcher,
+		DB:                   db,
+		Validate:             validate,
 	}
 }
 
@@ -47,23 +67,126 @@ func (controller *CommentServiceImpl) Create(ctx context.Context, request reques
 		Comment:   request.Comment,
 	}
 
+	if request.ParentId != 0 {
+		parent, err := controller.CommentRepository.FindByID(ctx, tx, request.ParentId)
+		if err != nil || parent.ArticleId != request.ArticleId || parent.IsHidden {
+			panic(exception.NewNotFoundError("parent comment not found"))
+		}
+		if parent.IsDeleted {
+			panic(exception.NewInvalidParameter("you cannot reply to a deleted comment"))
+		}
+		if parent.Depth+1 > config.CommentMaxDepth {
+			panic(exception.NewInvalidParameter(fmt.Sprintf("replies cannot be nested more than %d levels deep", config.CommentMaxDepth)))
+		}
+		req.ParentId = parent.Id
+		req.Depth = parent.Depth + 1
+	}
+
+	result := screenContent(ctx, tx, controller.ContentFilter, contentfilter.Content{
+		UserId: request.UserId,
+		Kind:   "comment",
+		Text:   request.Comment,
+	})
+	req.IsHidden = result.Verdict == contentfilter.Hold
+
 	comment := controller.CommentRepository.Create(ctx, tx, req)
-	comment, err = controller.CommentRepository.FindByID(ctx, tx, comment.Id)
-	helper.PanicIfNotFound(err, "comment not found")
+	if req.IsHidden {
+		holdForModeration(ctx, tx, controller.ModerationRepository, "comment", comment.Id, result)
+	}
+	controller.MentionTracker.Sync(ctx, tx, "comment", comment.Id, comment.ArticleId, comment.UserId, comment.Comment, !req.IsHidden)
+	if !req.IsHidden {
+		notifyComment(ctx, tx, controller.Notifier, controller.ArticleRepository, controller.CommentRepository, comment)
+	}
+
+	commentResponse := helper.ToCommentResponse(controller.findComment(ctx, tx, comment.Id))
+	if !req.IsHidden {
+		controller.announce(ctx, tx, commentResponse)
+	}
+
+	return commentResponse
+
+}
+
+// Update replaces the text of a comment and keeps the previous text as a
+// revision. Only the author may edit, and only within config.CommentEditWindow.
+func (controller *CommentServiceImpl) Update(ctx context.Context, request request.CommentUpdateRequest) response.CommentResponse {
+	err := controller.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := controller.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	comment, err := controller.CommentRepository.FindByID(ctx, tx, request.Id)
+	if err != nil || comment.IsDeleted {
+		panic(exception.NewNotFoundError("comment not found"))
+	}
+
+	if comment.UserId != request.UserId {
+		panic(exception.NewInvalidCredentialsError("you cannot edit another user comment"))
+	}
+	if time.Since(parseDbTime(comment.CreatedAt)) > config.CommentEditWindow {
+		panic(exception.NewInvalidParameter("comment can no longer be edited"))
+	}
+
+	if comment.Comment != request.Comment {
+		result := screenContent(ctx, tx, controller.ContentFilter, contentfilter.Content{
+			UserId: request.UserId,
+			Kind:   "comment",
+			Text:   request.Comment,
+			IsEdit: true,
+		})
+		if result.Verdict == contentfilter.Hold && !comment.IsHidden {
+			controller.CommentRepository.SetHidden(ctx, tx, comment.Id, true)
+			holdForModeration(ctx, tx, controller.ModerationRepository, "comment", comment.Id, result)
+			comment.IsHidden = true
+		}
+
+		controller.CommentRepository.CreateRevision(ctx, tx, entity.CommentRevision{
+			CommentId: comment.Id,
+			Comment:   comment.Comment,
+		})
+		comment.Comment = request.Comment
+		controller.CommentRepository.Update(ctx, tx, comment)
+		controller.MentionTracker.Sync(ctx, tx, "comment", comment.Id, comment.ArticleId, comment.UserId, comment.Comment, !comment.IsHidden)
+	}
+
+	comment = controller.findComment(ctx, tx, comment.Id)
 
 	return helper.ToCommentResponse(comment)
+}
+
+// FindByArticleID returns the top-level comments of an article with their
+// replies nested under them. Replies under a hidden comment are hidden with it.
+func (controller *CommentServiceImpl) FindByArticleID(ctx context.Context, articleId int, includeHidden bool) []response.CommentResponse {
+	tx, err := controller.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	comments := controller.CommentRepository.FindByArticleID(ctx, tx, articleId, includeHidden)
+
+	var commentIds []int
+	for _, comment := range comments {
+		commentIds = append(commentIds, comment.Id)
+	}
+	mentions := controller.MentionTracker.MentionRepository.FindByTargets(ctx, tx, "comment", commentIds)
+	for i := range comments {
+		comments[i].Mentions = mentions[comments[i].Id]
+	}
 
+	return buildCommentTree(comments, 0)
 }
 
-func (controller *CommentServiceImpl) FindByArticleID(ctx context.Context, articleId int) []response.CommentResponse {
+func (controller *CommentServiceImpl) FindRevisionsByID(ctx context.Context, commentId int) []response.CommentRevisionResponse {
 	tx, err := controller.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
-	comments := controller.CommentRepository.FindByArticleID(ctx, tx, articleId)
-	log.Info(comments)
+	_, err = controller.CommentRepository.FindByID(ctx, tx, commentId)
+	helper.PanicIfNotFound(err, "comment not found")
 
-	return helper.ToCommentResponses(comments)
+	revisions := controller.CommentRepository.FindRevisionsByCommentID(ctx, tx, commentId)
+	return helper.ToCommentRevisionResponses(revisions)
 }
 
 func (controller *CommentServiceImpl) Delete(ctx context.Context, commentId int, userId int) {
@@ -72,12 +195,118 @@ func (controller *CommentServiceImpl) Delete(ctx context.Context, commentId int,
 	defer helper.CommitOrRollback(tx)
 
 	comment, err := controller.CommentRepository.FindByID(ctx, tx, commentId)
-	helper.PanicIfNotFound(err, "comment not found")
+	if err != nil || comment.IsDeleted {
+		panic(exception.NewNotFoundError("comment not found"))
+	}
 
 	if comment.UserId != userId {
 		panic(exception.NewInvalidCredentialsError("you cannot delete another user comment"))
 	}
 
-	controller.CommentRepository.Delete(ctx, tx, comment.Id, userId)
+	controller.deleteComment(ctx, tx, comment)
+}
+
+// deleteComment removes a comment for its author or a moderator. A comment
+// with replies is kept as a "[deleted]" placeholder so the thread below it
+// survives.
+func (controller *CommentServiceImpl) deleteComment(ctx context.Context, tx *sql.Tx, comment entity.Comment) {
+	controller.MentionTracker.Forget(ctx, tx, "comment", comment.Id)
+	if comment.ReplyCount > 0 {
+		controller.CommentRepository.SoftDelete(ctx, tx, comment.Id)
+		return
+	}
+	controller.CommentRepository.Delete(ctx, tx, comment.Id, comment.UserId)
+
+	// Placeholders left without replies are no longer needed.
+	for parentId := comment.ParentId; parentId != 0; {
+		parent, err := controller.CommentRepository.FindByID(ctx, tx, parentId)
+		if err != nil || !parent.IsDeleted || controller.CommentRepository.CountReplies(ctx, tx, parent.Id) > 0 {
+			break
+		}
+		controller.CommentRepository.Delete(ctx, tx, parent.Id, parent.UserId)
+		parentId = parent.ParentId
+	}
+}
 
+func (controller *CommentServiceImpl) findComment(ctx context.Context, tx *sql.Tx, commentId int) entity.Comment {
+	comment, err := controller.CommentRepository.FindByID(ctx, tx, commentId)
+	helper.PanicIfNotFound(err, "comment not found")
+	comment.Mentions = controller.MentionTracker.MentionRepository.FindByTarget(ctx, tx, "comment", comment.Id)
+	return comment
+}
+
+// announce pushes a newly visible comment to the clients following its
+// article and to the webhooks subscribed to "comment.created".
+func (controller *CommentServiceImpl) announce(ctx context.Context, tx *sql.Tx, comment response.CommentResponse) {
+	publishAfterCommit(ctx, tx, controller.Publisher, realtime.ArticleTopic(comment.ArticleId), "comment.created", comment)
+	controller.WebhookDispatcher.Dispatch(ctx, tx, "comment.created", comment)
+}
+
+// notifyComment tells the article author about a new comment and the parent
+// comment author about a reply. An article author replied to gets only the
+// reply.
+func notifyComment(ctx context.Context, tx *sql.Tx, notifier *Notifier, articleRepository repositories.ArticleRepository, commentRepository repositories.CommentRepository, comment entity.Comment) {
+	article, err := articleRepository.FindByID(ctx, tx, comment.ArticleId)
+	if err != nil {
+		return
+	}
+
+	parentAuthorId := 0
+	if comment.ParentId != 0 {
+		parent, err := commentRepository.FindByID(ctx, tx, comment.ParentId)
+		if err == nil && !parent.IsDeleted {
+			parentAuthorId = parent.UserId
+			notifier.Notify(ctx, tx, entity.Notification{
+				UserId:     parent.UserId,
+				ActorId:    comment.UserId,
+				Type:       "reply",
+				TargetType: "comment",
+				TargetId:   comment.Id,
+				ArticleId:  article.Id,
+			})
+		}
+	}
+
+	if article.UserId != parentAuthorId {
+		notifier.Notify(ctx, tx, entity.Notification{
+			UserId:     article.UserId,
+			ActorId:    comment.UserId,
+			Type:       "comment",
+			TargetType: "comment",
+			TargetId:   comment.Id,
+			ArticleId:  article.Id,
+		})
+	}
+}
+
+func buildCommentTree(comments []entity.Comment, parentId int) []response.CommentResponse {
+	var tree []response.CommentResponse
+	for _, comment := range comments {
+		if comment.ParentId != parentId {
+			continue
+		}
+		node := helper.ToCommentResponse(comment)
+		node.Replies = buildCommentTree(comments, comment.Id)
+		if parentId == 0 && comment.Depth > 0 {
+			node = underDeletedParents(node)
+		}
+		tree = append(tree, node)
+	}
+	return tree
+}
+
+// underDeletedParents nests a reply whose parent row is gone, e.g. deleted
+// along with its author, under "[deleted]" placeholders down to its depth.
+func underDeletedParents(node response.CommentResponse) response.CommentResponse {
+	for depth := node.Depth - 1; depth >= 0; depth-- {
+		placeholder := helper.ToCommentResponse(entity.Comment{
+			ArticleId:  node.ArticleId,
+			Depth:      depth,
+			IsDeleted:  true,
+			ReplyCount: 1,
+		})
+		placeholder.Replies = []response.CommentResponse{node}
+		node = placeholder
+	}
+	return node
 }
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"uaspw2/contentfilter"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/repositories"
+)
+
+// screenContent runs text through the content filter before it is stored and
+// refuses it when the verdict is Reject. On Hold the caller stores the content
+// out of sight and calls holdForModeration. The filter records the content
+// only once tx commits, so failed requests do not count against the rate limit.
+func screenContent(ctx context.Context, tx *sql.Tx, filter contentfilter.Filter, content contentfilter.Content) contentfilter.Result {
+	result := filter.Check(ctx, content)
+	if result.Verdict == contentfilter.Reject {
+		panic(exception.NewInvalidParameter(content.Kind + " rejected: " + strings.Join(result.Reasons, ", ")))
+	}
+	helper.AfterCommit(tx, func() {
+		contentfilter.Record(filter, content)
+	})
+	return result
+}
+
+// holdForModeration puts held content in the moderation queue as a report
+// without a reporter.
+func holdForModeration(ctx context.Context, tx *sql.Tx, moderationRepository repositories.ModerationRepository, targetType string, targetId int, result contentfilter.Result) {
+	moderationRepository.CreateReport(ctx, tx, entity.Report{
+		TargetType: targetType,
+		TargetId:   targetId,
+		Reason:     "filter",
+		Details:    strings.Join(result.Reasons, ", "),
+	})
+}
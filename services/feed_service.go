@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"time"
+	"uaspw2/config"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/repositories"
+)
+
+const feedItemLimit = 50
+
+type FeedService interface {
+	FindArticles(ctx context.Context, request request.ArticleFeedSourceRequest, feedUrl string) helper.Feed
+}
+
+type FeedServiceImpl struct {
+	ArticleRepository     repositories.ArticleRepository
+	TagRepository         repositories.TagRepository
+	UserProfileRepository repositories.UserProfileRepository
+	MentionRepository     repositories.MentionRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewFeedService(articleRepository repositories.ArticleRepository, tagRepository repositories.TagRepository, userProfileRepository repositories.UserProfileRepository, mentionRepository repositories.MentionRepository, db *sql.DB, validate *validator.Validate) FeedService {
+	return &FeedServiceImpl{
+		ArticleRepository:     articleRepository,
+		TagRepository:         tagRepository,
+		UserProfileRepository: userProfileRepository,
+		MentionRepository:     mentionRepository,
+		DB:                    db,
+		Validate:              validate,
+	}
+}
+
+// FindArticles builds the feed of the latest published articles, site-wide or
+// for one author or tag. feedUrl is the address the feed is served from.
+func (service *FeedServiceImpl) FindArticles(ctx context.Context, request request.ArticleFeedSourceRequest, feedUrl string) helper.Feed {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	feed := helper.Feed{
+		Title:       config.FeedTitle,
+		Description: "Latest articles on " + config.FeedTitle,
+		Link:        config.FeedSiteUrl,
+		FeedUrl:     feedUrl,
+	}
+
+	var tagId int
+	if request.TagSlug != "" {
+		tag, err := service.TagRepository.FindBySlug(ctx, tx, request.TagSlug)
+		helper.PanicIfNotFound(err, "tag not found")
+		tagId = tag.Id
+		feed.Title = fmt.Sprintf("%s: %s", config.FeedTitle, tag.Name)
+		feed.Description = fmt.Sprintf("Latest articles tagged %s on %s", tag.Name, config.FeedTitle)
+	}
+	if request.UserId != 0 {
+		profile, err := service.UserProfileRepository.FindByUserID(ctx, tx, request.UserId)
+		helper.PanicIfNotFound(err, "user not found")
+		feed.Title = fmt.Sprintf("%s: %s", config.FeedTitle, profile.FullName)
+		feed.Description = fmt.Sprintf("Latest articles by %s on %s", profile.FullName, config.FeedTitle)
+	}
+
+	articles := service.ArticleRepository.FindLatestPublished(ctx, tx, request.UserId, tagId, feedItemLimit)
+	var articleIds []int
+	for _, article := range articles {
+		articleIds = append(articleIds, article.Id)
+	}
+	mentions := service.MentionRepository.FindByTargets(ctx, tx, "article", articleIds)
+	for _, article := range articles {
+		article.Tags = service.TagRepository.FindByArticleID(ctx, tx, article.Id)
+		article.Mentions = mentions[article.Id]
+		item := toFeedItem(article)
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed
+}
+
+func toFeedItem(article entity.Article) helper.FeedItem {
+	item := helper.FeedItem{
+		Id:        fmt.Sprintf("%s/articles/%d", config.FeedBaseUrl, article.Id),
+		Title:     article.Title,
+		Link:      fmt.Sprintf("%s/articles/%s", config.FeedSiteUrl, article.Slug),
+		Author:    article.Author,
+		Summary:   article.Description,
+		Published: parseDbTime(article.CreatedAt),
+		Updated:   parseDbTime(article.UpdatedAt),
+	}
+	if config.FeedContent == "full" {
+		item.Content = helper.RenderArticle(article).Html
+	}
+	for _, tag := range article.Tags {
+		item.Tags = append(item.Tags, tag.Name)
+	}
+	return item
+}
+
+// parseDbTime reads a TIMESTAMP column scanned into a string.
+func parseDbTime(value string) time.Time {
+	parsed, err := time.ParseInLocation(time.DateTime, value, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type FollowService interface {
+	Create(ctx context.Context, request request.FollowRequest) response.FollowResponse
+	Delete(ctx context.Context, request request.FollowRequest)
+	FindFollowers(ctx context.Context, userId int) []response.FollowUserResponse
+	FindFollowing(ctx context.Context, userId int) []response.FollowUserResponse
+}
+
+type FollowServiceImpl struct {
+	repositories.FollowRepository
+	UserRepository repositories.UserRepository
+	Notifier       *Notifier
+	*sql.DB
+	*validator.Validate
+}
+
+func NewFollowService(followRepository repositories.FollowRepository, userRepository repositories.UserRepository, notifier *Notifier, db *sql.DB, validate *validator.Validate) FollowService {
+	return &FollowServiceImpl{
+		FollowRepository: followRepository,
+		UserRepository:   userRepository,
+		Notifier:         notifier,
+		DB:               db,
+		Validate:         validate,
+	}
+}
+
+func (service *FollowServiceImpl) Create(ctx context.Context, request request.FollowRequest) response.FollowResponse {
+	if request.FollowerId == request.FolloweeId {
+		panic(exception.NewInvalidParameter("you cannot follow yourself"))
+	}
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.UserRepository.FindByID(ctx, tx, request.FolloweeId)
+	helper.PanicIfNotFound(err, "user not found")
+
+	if _, err := service.FollowRepository.FindByFollowerAndFollowee(ctx, tx, request.FollowerId, request.FolloweeId); err == nil {
+		panic(exception.NewInvalidParameter("you already follow this user"))
+	}
+
+	service.FollowRepository.Create(ctx, tx, entity.Follow{
+		FollowerId: request.FollowerId,
+		FolloweeId: request.FolloweeId,
+	})
+	follow, err := service.FollowRepository.FindByFollowerAndFollowee(ctx, tx, request.FollowerId, request.FolloweeId)
+	helper.PanicIfNotFound(err, "follow not found")
+
+	service.Notifier.Notify(ctx, tx, entity.Notification{
+		UserId:     request.FolloweeId,
+		ActorId:    request.FollowerId,
+		Type:       "follow",
+		TargetType: "user",
+		TargetId:   request.FollowerId,
+	})
+
+	return helper.ToFollowResponse(follow)
+}
+
+func (service *FollowServiceImpl) Delete(ctx context.Context, request request.FollowRequest) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.FollowRepository.FindByFollowerAndFollowee(ctx, tx, request.FollowerId, request.FolloweeId)
+	helper.PanicIfNotFound(err, "follow not found")
+
+	service.FollowRepository.Delete(ctx, tx, request.FollowerId, request.FolloweeId)
+}
+
+func (service *FollowServiceImpl) FindFollowers(ctx context.Context, userId int) []response.FollowUserResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.UserRepository.FindByID(ctx, tx, userId)
+	helper.PanicIfNotFound(err, "user not found")
+
+	users := service.FollowRepository.FindFollowers(ctx, tx, userId)
+	return helper.ToFollowUserResponses(users)
+}
+
+func (service *FollowServiceImpl) FindFollowing(ctx context.Context, userId int) []response.FollowUserResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.UserRepository.FindByID(ctx, tx, userId)
+	helper.PanicIfNotFound(err, "user not found")
+
+	users := service.FollowRepository.FindFollowing(ctx, tx, userId)
+	return helper.ToFollowUserResponses(users)
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2/log"
+	"time"
+)
+
+// RunPeriodically runs job now and then every interval, for background work
+// such as stats rollups. Services panic on errors, so a failed run is logged
+// and retried on the next tick. It never returns.
+func RunPeriodically(name string, interval time.Duration, job func(ctx context.Context)) {
+	run := func() {
+		defer func() {
+			if err := recover(); err != nil {
+				log.Errorf("%s failed: %v", name, err)
+			}
+		}()
+		job(context.Background())
+	}
+
+	run()
+	for range time.Tick(interval) {
+		run()
+	}
+}
@@ -4,32 +4,48 @@ import (
 	"context"
 	"database/sql"
 	"github.com/go-playground/validator/v10"
-	"github.com/gofiber/fiber/v2/log"
+	"uaspw2/exception"
 	"uaspw2/helper"
 	"uaspw2/models/entity"
 	"uaspw2/models/web/request"
 	"uaspw2/models/web/response"
+	"uaspw2/realtime"
 	"uaspw2/repositories"
+	"uaspw2/storage"
 )
 
+// LikeService manages reactions to articles. Likes are kept as the "like"
+// reaction so the like endpoints work as before.
 type LikeService interface {
 	Create(ctx context.Context, request request.LikeRequest) response.LikeResponse
 	Delete(ctx context.Context, request request.LikeRequest)
-	FindByArticleID(ctx context.Context, articleId int) []response.LikeResponse
-	FindByUserID(ctx context.Context, userId int) []response.LikeResponse
+	FindByArticleID(ctx context.Context, request request.LikeListRequest) response.LikeListResponse
+	FindByUserID(ctx context.Context, request request.LikeListRequest) response.LikeListResponse
+	CreateReaction(ctx context.Context, request request.ReactionRequest) response.LikeResponse
+	DeleteReaction(ctx context.Context, request request.ReactionRequest)
+	FindReactions(ctx context.Context, articleId int, userId int) response.ArticleReactionsResponse
+	MarkLiked(ctx context.Context, userId int, articles []response.ArticleResponse)
 }
 
 type LikeServiceImpl struct {
 	repositories.LikeRepository
+	ArticleRepository repositories.ArticleRepository
+	Notifier          *Notifier
+	Publisher         realtime.Publisher
 	*sql.DB
 	*validator.Validate
+	Storage storage.Storage
 }
 
-func NewLikeService(likeRepository repositories.LikeRepository, db *sql.DB, validate *validator.Validate) LikeService {
+func NewLikeService(likeRepository repositories.LikeRepository, articleRepository repositories.ArticleRepository, notifier *Notifier, publisher realtime.Publisher, db *sql.DB, validate *validator.Validate, storage storage.Storage) LikeService {
 	return &LikeServiceImpl{
-		LikeRepository: likeRepository,
-		DB:             db,
-		Validate:       validate,
+		LikeRepository:    likeRepository,
+		ArticleRepository: articleRepository,
+		Notifier:          notifier,
+		Publisher:         publisher,
+		DB:                db,
+		Validate:          validate,
+		Storage:           storage,
 	}
 }
 
@@ -37,48 +53,190 @@ func (service *LikeServiceImpl) Create(ctx context.Context, request request.Like
 	err := service.Validate.Struct(request)
 	helper.PanicIfErr(err)
 
+	return service.CreateReaction(ctx, toLikeReaction(request))
+}
+
+func (service *LikeServiceImpl) Delete(ctx context.Context, request request.LikeRequest) {
+	service.DeleteReaction(ctx, toLikeReaction(request))
+}
+
+func toLikeReaction(like request.LikeRequest) request.ReactionRequest {
+	return request.ReactionRequest{
+		UserId:    like.UserId,
+		ArticleId: like.ArticleId,
+		Reaction:  "like",
+	}
+}
+
+func (service *LikeServiceImpl) CreateReaction(ctx context.Context, request request.ReactionRequest) response.LikeResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
+	article, err := service.ArticleRepository.FindByID(ctx, tx, request.ArticleId)
+	helper.PanicIfNotFound(err, "article not found")
+
+	if _, err := service.LikeRepository.FindByArticleAndUser(ctx, tx, request.ArticleId, request.UserId, request.Reaction); err == nil {
+		panic(exception.NewConflictError("reaction already exists"))
+	}
+
 	req := entity.Like{
 		UserId:    request.UserId,
 		ArticleId: request.ArticleId,
+		Reaction:  request.Reaction,
 	}
 
-	like := service.LikeRepository.Create(ctx, tx, req)
-	log.Info(like)
-	like, err = service.LikeRepository.FindByArticleAndUser(ctx, tx, request.ArticleId, request.UserId)
+	// a concurrent request for the same reaction may insert it after the
+	// check above
+	helper.RetryOnDuplicateKey(1, "reaction already exists", func(attempt int) {
+		service.LikeRepository.Create(ctx, tx, req)
+	})
+	like, err := service.LikeRepository.FindByArticleAndUser(ctx, tx, request.ArticleId, request.UserId, request.Reaction)
 	helper.PanicIfNotFound(err, "like not found")
 
+	notificationType := "reaction"
+	if request.Reaction == "like" {
+		notificationType = "like"
+	}
+	service.Notifier.Notify(ctx, tx, entity.Notification{
+		UserId:     article.UserId,
+		ActorId:    request.UserId,
+		Type:       notificationType,
+		TargetType: "article",
+		TargetId:   article.Id,
+		ArticleId:  article.Id,
+	})
+	service.publishCounts(ctx, tx, article.Id, request.Reaction)
+
 	return helper.ToLikeResponse(like)
 }
 
-func (service *LikeServiceImpl) Delete(ctx context.Context, request request.LikeRequest) {
+func (service *LikeServiceImpl) DeleteReaction(ctx context.Context, request request.ReactionRequest) {
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
-	_, err = service.LikeRepository.FindByArticleAndUser(ctx, tx, request.ArticleId, request.UserId)
-	helper.PanicIfNotFound(err, "like not found")
+	_, err = service.LikeRepository.FindByArticleAndUser(ctx, tx, request.ArticleId, request.UserId, request.Reaction)
+	if err != nil {
+		if request.Reaction == "like" {
+			panic(exception.NewNotFoundError("like not found"))
+		}
+		panic(exception.NewNotFoundError("reaction not found"))
+	}
+
+	service.LikeRepository.Delete(ctx, tx, request.ArticleId, request.UserId, request.Reaction)
+	service.publishCounts(ctx, tx, request.ArticleId, request.Reaction)
+}
+
+// FindReactions counts the reactions to an article and lists the ones userId
+// left on it.
+func (service *LikeServiceImpl) FindReactions(ctx context.Context, articleId int, userId int) response.ArticleReactionsResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
+	helper.PanicIfNotFound(err, "article not found")
+
+	return response.ArticleReactionsResponse{
+		ArticleId: article.Id,
+		Reactions: service.countReactions(ctx, tx, article.Id),
+		Mine:      service.LikeRepository.FindReactionsByArticleAndUser(ctx, tx, article.Id, userId),
+	}
+}
+
+// MarkLiked sets LikedByMe on the articles userId has liked.
+func (service *LikeServiceImpl) MarkLiked(ctx context.Context, userId int, articles []response.ArticleResponse) {
+	if len(articles) == 0 {
+		return
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	var articleIds []int
+	for _, article := range articles {
+		articleIds = append(articleIds, article.Id)
+	}
+	liked := service.LikeRepository.FindLikedArticleIDs(ctx, tx, userId, articleIds)
+	for i := range articles {
+		articles[i].LikedByMe = liked[articles[i].Id]
+	}
+}
+
+// publishCounts pushes the new reaction counts to the clients following the
+// article, and the like count too when the change was to a like.
+func (service *LikeServiceImpl) publishCounts(ctx context.Context, tx *sql.Tx, articleId int, reaction string) {
+	if reaction == "like" {
+		publishAfterCommit(ctx, tx, service.Publisher, realtime.ArticleTopic(articleId), "like.count", response.LikeCountResponse{
+			ArticleId: articleId,
+			LikeCount: service.LikeRepository.CountByArticleID(ctx, tx, articleId, "like"),
+		})
+	}
+
+	publishAfterCommit(ctx, tx, service.Publisher, realtime.ArticleTopic(articleId), "reaction.count", response.ReactionCountResponse{
+		ArticleId: articleId,
+		Reactions: service.countReactions(ctx, tx, articleId),
+	})
+}
 
-	service.LikeRepository.Delete(ctx, tx, request.ArticleId, request.UserId)
+func (service *LikeServiceImpl) countReactions(ctx context.Context, tx *sql.Tx, articleId int) map[string]int {
+	reactions := service.ArticleRepository.CountReactionsByArticleIDs(ctx, tx, []int{articleId})[articleId]
+	if reactions == nil {
+		reactions = map[string]int{}
+	}
+	return reactions
 }
 
-func (service *LikeServiceImpl) FindByArticleID(ctx context.Context, articleId int) []response.LikeResponse {
+func (service *LikeServiceImpl) FindByArticleID(ctx context.Context, request request.LikeListRequest) response.LikeListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	beforeCreatedAt, beforeId := helper.DecodeCursor(request.Cursor)
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
-	likes := service.LikeRepository.FindByArticleId(ctx, tx, articleId)
-	return helper.ToLikeResponses(likes)
+	article, err := service.ArticleRepository.FindByID(ctx, tx, request.ArticleId)
+	if err != nil || !article.IsPublished {
+		panic(exception.NewNotFoundError("article not found"))
+	}
+
+	// one extra row tells whether there is a next page
+	likes := service.LikeRepository.FindByArticleId(ctx, tx, request.ArticleId, "like", beforeCreatedAt, beforeId, request.Limit+1)
+	return service.toLikeListResponse(likes, request.Limit)
 }
 
-func (service *LikeServiceImpl) FindByUserID(ctx context.Context, userId int) []response.LikeResponse {
+func (service *LikeServiceImpl) FindByUserID(ctx context.Context, request request.LikeListRequest) response.LikeListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	beforeCreatedAt, beforeId := helper.DecodeCursor(request.Cursor)
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
-	likes := service.LikeRepository.FindByUserId(ctx, tx, userId)
-	return helper.ToLikeResponses(likes)
+	// one extra row tells whether there is a next page
+	likes := service.LikeRepository.FindByUserId(ctx, tx, request.UserId, "like", beforeCreatedAt, beforeId, request.Limit+1)
+	return service.toLikeListResponse(likes, request.Limit)
+}
+
+func (service *LikeServiceImpl) toLikeListResponse(likes []entity.Like, limit int) response.LikeListResponse {
+	listResponse := response.LikeListResponse{}
+	if len(likes) > limit {
+		likes = likes[:limit]
+		last := likes[len(likes)-1]
+		listResponse.NextCursor = helper.EncodeCursor(last.CreatedAt, last.Id)
+	}
+	listResponse.Likes = helper.ToLikeResponses(likes)
+	for _, like := range listResponse.Likes {
+		if like.User != nil {
+			like.User.PhotoUrl = profilePhotoUrl(service.Storage, like.User.PhotoPath)
+		}
+	}
+	return listResponse
 }
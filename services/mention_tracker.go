@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/repositories"
+)
+
+// maxMentions caps how many users one article or comment can mention.
+const maxMentions = 20
+
+// MentionTracker keeps the mention records of articles and comments in step
+// with their text and notifies each mentioned user once the content is
+// visible. It works inside the caller's transaction.
+type MentionTracker struct {
+	MentionRepository repositories.MentionRepository
+	Notifier          *Notifier
+}
+
+func NewMentionTracker(mentionRepository repositories.MentionRepository, notifier *Notifier) *MentionTracker {
+	return &MentionTracker{
+		MentionRepository: mentionRepository,
+		Notifier:          notifier,
+	}
+}
+
+// Sync records the users mentioned in text by authorId and forgets the ones
+// no longer mentioned. When visible is false, e.g. for a draft article, the
+// notifications wait for a later Notify.
+func (tracker *MentionTracker) Sync(ctx context.Context, tx *sql.Tx, targetType string, targetId int, articleId int, authorId int, text string, visible bool) {
+	usernames := helper.ParseMentions(text)
+	if len(usernames) > maxMentions {
+		usernames = usernames[:maxMentions]
+	}
+
+	mentioned := map[int]bool{}
+	for _, user := range tracker.MentionRepository.FindUsersByUsernames(ctx, tx, usernames) {
+		mentioned[user.Id] = true
+		tracker.MentionRepository.Create(ctx, tx, entity.Mention{
+			TargetType: targetType,
+			TargetId:   targetId,
+			UserId:     user.Id,
+		})
+	}
+	for _, mention := range tracker.MentionRepository.FindByTarget(ctx, tx, targetType, targetId) {
+		if !mentioned[mention.UserId] {
+			tracker.MentionRepository.Delete(ctx, tx, mention)
+		}
+	}
+
+	if visible {
+		tracker.Notify(ctx, tx, targetType, targetId, articleId, authorId)
+	}
+}
+
+// Notify sends the pending mention notifications of a target.
+func (tracker *MentionTracker) Notify(ctx context.Context, tx *sql.Tx, targetType string, targetId int, articleId int, authorId int) {
+	for _, mention := range tracker.MentionRepository.FindPending(ctx, tx, targetType, targetId) {
+		tracker.MentionRepository.MarkNotified(ctx, tx, mention)
+		tracker.Notifier.Notify(ctx, tx, entity.Notification{
+			UserId:     mention.UserId,
+			ActorId:    authorId,
+			Type:       "mention",
+			TargetType: targetType,
+			TargetId:   targetId,
+			ArticleId:  articleId,
+		})
+	}
+}
+
+func (tracker *MentionTracker) Forget(ctx context.Context, tx *sql.Tx, targetType string, targetId int) {
+	tracker.MentionRepository.DeleteByTarget(ctx, tx, targetType, targetId)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"sort"
+	"strconv"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type ModerationService interface {
+	CreateReport(ctx context.Context, request request.ReportCreateRequest) response.ReportResponse
+	FindQueue(ctx context.Context) []response.ReportGroupResponse
+	CreateAction(ctx context.Context, request request.ModerationActionRequest) response.ModerationActionResponse
+	FindActions(ctx context.Context, userId int) []response.ModerationActionResponse
+}
+
+type ModerationServiceImpl struct {
+	repositories.ModerationRepository
+	ArticleRepository repositories.ArticleRepository
+	CommentRepository repositories.CommentRepository
+	UserRepository    repositories.UserRepository
+	ArticleService    ArticleService
+	CommentService    *CommentServiceImpl
+	MentionTracker    *MentionTracker
+	Notifier          *Notifier
+	*sql.DB
+	*validator.Validate
+}
+
+func NewModerationService(moderationRepository repositories.ModerationRepository, articleRepository repositories.ArticleRepository, commentRepository repositories.CommentRepository, userRepository repositories.UserRepository, articleService ArticleService, commentService *CommentServiceImpl, mentionTracker *MentionTracker, notifier *Notifier, db *sql.DB, validate *validator.Validate) ModerationService {
+	return &ModerationServiceImpl{
+		ModerationRepository: moderationRepository,
+		ArticleRepository:    articleRepository,
+		CommentRepository:    commentRepository,
+		UserRepository:       userRepository,
+		ArticleService:       articleService,
+		CommentService:       commentService,
+		MentionTracker:       mentionTracker,
+		Notifier:             notifier,
+		DB:                   db,
+		Validate:             validate,
+	}
+}
+
+func (service *ModerationServiceImpl) CreateReport(ctx context.Context, request request.ReportCreateRequest) response.ReportResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	authorId, found := service.findTargetAuthor(ctx, tx, request.TargetType, request.TargetId)
+	if !found {
+		panic(exception.NewNotFoundError(request.TargetType + " not found"))
+	}
+	if authorId == request.ReporterId {
+		panic(exception.NewInvalidParameter("you cannot report your own " + request.TargetType))
+	}
+	if service.ModerationRepository.HasOpenReport(ctx, tx, request.ReporterId, request.TargetType, request.TargetId) {
+		panic(exception.NewInvalidParameter("you have already reported this " + request.TargetType))
+	}
+
+	report := service.ModerationRepository.CreateReport(ctx, tx, entity.Report{
+		ReporterId: request.ReporterId,
+		TargetType: request.TargetType,
+		TargetId:   request.TargetId,
+		Reason:     request.Reason,
+		Details:    request.Details,
+	})
+
+	return helper.ToReportResponse(report)
+}
+
+// FindQueue returns the open reports grouped by the reported article or
+// comment, most reported first.
+func (service *ModerationServiceImpl) FindQueue(ctx context.Context) []response.ReportGroupResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	var queue []response.ReportGroupResponse
+	groups := map[string]int{}
+	for _, report := range service.ModerationRepository.FindOpenReports(ctx, tx) {
+		key := report.TargetType + ":" + strconv.Itoa(report.TargetId)
+		index, ok := groups[key]
+		if !ok {
+			index = len(queue)
+			groups[key] = index
+			queue = append(queue, response.ReportGroupResponse{
+				TargetType:      report.TargetType,
+				TargetId:        report.TargetId,
+				Reasons:         map[string]int{},
+				FirstReportedAt: report.CreatedAt,
+			})
+		}
+		group := &queue[index]
+		group.ReportCount++
+		group.Reasons[report.Reason]++
+		group.LastReportedAt = report.CreatedAt
+		group.Reports = append(group.Reports, helper.ToReportResponse(report))
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].ReportCount > queue[j].ReportCount
+	})
+	return queue
+}
+
+// CreateAction applies a moderator decision to a reported article or comment,
+// resolves its open reports and records the action.
+func (service *ModerationServiceImpl) CreateAction(ctx context.Context, request request.ModerationActionRequest) response.ModerationActionResponse {
+	action, deleted := service.createAction(ctx, request)
+
+	// media files are only removed once the deletion is committed
+	service.ArticleService.DeleteMediaFiles(ctx, deleted.Media)
+
+	return helper.ToModerationActionResponse(action)
+}
+
+// createAction applies the action in one transaction and returns the article
+// it deleted, if any.
+func (service *ModerationServiceImpl) createAction(ctx context.Context, request request.ModerationActionRequest) (entity.ModerationAction, entity.Article) {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	reports := service.ModerationRepository.FindOpenReportsByTarget(ctx, tx, request.TargetType, request.TargetId)
+	if len(reports) == 0 {
+		panic(exception.NewNotFoundError("no open reports for this " + request.TargetType))
+	}
+
+	authorId, found := service.findTargetAuthor(ctx, tx, request.TargetType, request.TargetId)
+	if !found && request.Action != "dismiss" {
+		panic(exception.NewNotFoundError(request.TargetType + " not found"))
+	}
+
+	var deleted entity.Article
+	switch request.Action {
+	case "dismiss":
+		// a comment held by the content filter is released; a held article
+		// goes back to the usual publish workflow
+		if found && request.TargetType == "comment" && heldByFilter(reports) {
+			service.CommentRepository.SetHidden(ctx, tx, request.TargetId, false)
+			comment, err := service.CommentRepository.FindByID(ctx, tx, request.TargetId)
+			helper.PanicIfNotFound(err, "comment not found")
+			service.MentionTracker.Notify(ctx, tx, "comment", comment.Id, comment.ArticleId, comment.UserId)
+			notifyComment(ctx, tx, service.Notifier, service.ArticleRepository, service.CommentRepository, comment)
+			service.CommentService.announce(ctx, tx, helper.ToCommentResponse(service.CommentService.findComment(ctx, tx, comment.Id)))
+		}
+	case "hide":
+		if request.TargetType == "article" {
+			service.ArticleRepository.UpdatePublishStatus(ctx, tx, request.TargetId, false)
+		} else {
+			service.CommentRepository.SetHidden(ctx, tx, request.TargetId, true)
+		}
+	case "delete":
+		if request.TargetType == "article" {
+			deleted = service.ArticleService.DeleteInTx(ctx, tx, request.TargetId)
+		} else {
+			comment, err := service.CommentRepository.FindByID(ctx, tx, request.TargetId)
+			helper.PanicIfNotFound(err, "comment not found")
+			service.CommentService.deleteComment(ctx, tx, comment)
+		}
+	case "suspend":
+		service.UserRepository.Suspend(ctx, tx, authorId, request.SuspendDays)
+	}
+
+	status := "actioned"
+	if request.Action == "dismiss" {
+		status = "dismissed"
+	}
+	service.ModerationRepository.ResolveReports(ctx, tx, request.TargetType, request.TargetId, status)
+
+	action := service.ModerationRepository.CreateAction(ctx, tx, entity.ModerationAction{
+		ModeratorId: request.ModeratorId,
+		TargetType:  request.TargetType,
+		TargetId:    request.TargetId,
+		UserId:      authorId,
+		Action:      request.Action,
+		Note:        request.Note,
+		SuspendDays: request.SuspendDays,
+	})
+	return action, deleted
+}
+
+func (service *ModerationServiceImpl) FindActions(ctx context.Context, userId int) []response.ModerationActionResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	actions := service.ModerationRepository.FindActions(ctx, tx, userId)
+	return helper.ToModerationActionResponses(actions)
+}
+
+func heldByFilter(reports []entity.Report) bool {
+	for _, report := range reports {
+		if report.Reason == "filter" {
+			return true
+		}
+	}
+	return false
+}
+
+// findTargetAuthor returns the id of the user who wrote the reported article
+// or comment. Deleted comments count as gone.
+func (service *ModerationServiceImpl) findTargetAuthor(ctx context.Context, tx *sql.Tx, targetType string, targetId int) (int, bool) {
+	if targetType == "article" {
+		article, err := service.ArticleRepository.FindByID(ctx, tx, targetId)
+		return article.UserId, err == nil
+	}
+	comment, err := service.CommentRepository.FindByID(ctx, tx, targetId)
+	return comment.UserId, err == nil && !comment.IsDeleted
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type NotificationService interface {
+	FindByUserID(ctx context.Context, request request.NotificationListRequest) response.NotificationListResponse
+	MarkRead(ctx context.Context, request request.NotificationReadRequest) response.NotificationResponse
+	MarkAllRead(ctx context.Context, userId int)
+	CountUnread(ctx context.Context, userId int) response.NotificationUnreadCountResponse
+	FindPreferences(ctx context.Context, userId int) []response.NotificationPreferenceResponse
+	UpdatePreference(ctx context.Context, request request.NotificationPreferenceRequest) []response.NotificationPreferenceResponse
+}
+
+type NotificationServiceImpl struct {
+	repositories.NotificationRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewNotificationService(notificationRepository repositories.NotificationRepository, db *sql.DB, validate *validator.Validate) NotificationService {
+	return &NotificationServiceImpl{
+		NotificationRepository: notificationRepository,
+		DB:                     db,
+		Validate:               validate,
+	}
+}
+
+func (service *NotificationServiceImpl) FindByUserID(ctx context.Context, request request.NotificationListRequest) response.NotificationListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	beforeCreatedAt, beforeId := helper.DecodeCursor(request.Cursor)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	// one extra row tells whether there is a next page
+	notifications := service.NotificationRepository.FindByUserID(ctx, tx, request.UserId, request.UnreadOnly, beforeCreatedAt, beforeId, request.Limit+1)
+
+	listResponse := response.NotificationListResponse{}
+	if len(notifications) > request.Limit {
+		notifications = notifications[:request.Limit]
+		last := notifications[len(notifications)-1]
+		listResponse.NextCursor = helper.EncodeCursor(last.CreatedAt, last.Id)
+	}
+	listResponse.Notifications = helper.ToNotificationResponses(notifications)
+	return listResponse
+}
+
+func (service *NotificationServiceImpl) MarkRead(ctx context.Context, request request.NotificationReadRequest) response.NotificationResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	notification, err := service.NotificationRepository.FindByID(ctx, tx, request.Id)
+	if err != nil || notification.UserId != request.UserId {
+		panic(exception.NewNotFoundError("notification not found"))
+	}
+
+	service.NotificationRepository.MarkRead(ctx, tx, notification.Id, request.IsRead)
+	notification, err = service.NotificationRepository.FindByID(ctx, tx, notification.Id)
+	helper.PanicIfNotFound(err, "notification not found")
+
+	return helper.ToNotificationResponse(notification)
+}
+
+func (service *NotificationServiceImpl) MarkAllRead(ctx context.Context, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.NotificationRepository.MarkAllRead(ctx, tx, userId)
+}
+
+func (service *NotificationServiceImpl) CountUnread(ctx context.Context, userId int) response.NotificationUnreadCountResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	counts := service.NotificationRepository.CountUnread(ctx, tx, userId)
+
+	countResponse := response.NotificationUnreadCountResponse{ByType: counts}
+	for _, count := range counts {
+		countResponse.Total += count
+	}
+	return countResponse
+}
+
+// FindPreferences returns one preference per notification type, including
+// the types the user never changed.
+func (service *NotificationServiceImpl) FindPreferences(ctx context.Context, userId int) []response.NotificationPreferenceResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	return service.findPreferences(ctx, tx, userId)
+}
+
+func (service *NotificationServiceImpl) UpdatePreference(ctx context.Context, request request.NotificationPreferenceRequest) []response.NotificationPreferenceResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.NotificationRepository.SavePreference(ctx, tx, entity.NotificationPreference{
+		UserId:  request.UserId,
+		Type:    request.Type,
+		IsMuted: request.IsMuted,
+	})
+	return service.findPreferences(ctx, tx, request.UserId)
+}
+
+func (service *NotificationServiceImpl) findPreferences(ctx context.Context, tx *sql.Tx, userId int) []response.NotificationPreferenceResponse {
+	muted := map[string]bool{}
+	for _, preference := range service.NotificationRepository.FindPreferences(ctx, tx, userId) {
+		muted[preference.Type] = preference.IsMuted
+	}
+
+	var preferenceResponses []response.NotificationPreferenceResponse
+	for _, notificationType := range notificationTypes {
+		preferenceResponses = append(preferenceResponses, response.NotificationPreferenceResponse{
+			Type:    notificationType,
+			IsMuted: muted[notificationType],
+		})
+	}
+	return preferenceResponses
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"uaspw2/config"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/realtime"
+	"uaspw2/repositories"
+)
+
+// notificationTypes lists every notification type a user can mute.
+var notificationTypes = []string{"mention", "like", "reaction", "comment", "reply", "follow", "article_published", "article_unpublished", "new_article"}
+
+// dedupedNotificationTypes maps the types that are dropped when the same actor
+// already raised one of the listed types about the same article within
+// config.NotificationDedupeWindow. Liking and unliking over and over, adding
+// several reactions, or republishing, leaves the recipient one notification.
+var dedupedNotificationTypes = map[string][]string{
+	"like":        {"like", "reaction"},
+	"reaction":    {"like", "reaction"},
+	"new_article": {"new_article"},
+}
+
+// Notifier stores notifications raised by other services inside the caller's
+// transaction and pushes them to the recipient's open streams once it commits.
+// Users are not notified of their own actions, of actions by users they
+// blocked, nor of types they muted.
+type Notifier struct {
+	NotificationRepository repositories.NotificationRepository
+	BlockRepository        repositories.BlockRepository
+	FollowRepository       repositories.FollowRepository
+	Publisher              realtime.Publisher
+}
+
+func NewNotifier(notificationRepository repositories.NotificationRepository, blockRepository repositories.BlockRepository, followRepository repositories.FollowRepository, publisher realtime.Publisher) *Notifier {
+	return &Notifier{
+		NotificationRepository: notificationRepository,
+		BlockRepository:        blockRepository,
+		FollowRepository:       followRepository,
+		Publisher:              publisher,
+	}
+}
+
+func (notifier *Notifier) Notify(ctx context.Context, tx *sql.Tx, notification entity.Notification) {
+	if notification.UserId == 0 || notification.UserId == notification.ActorId {
+		return
+	}
+	if notification.ActorId != 0 && notifier.BlockRepository.IsBlocked(ctx, tx, notification.UserId, notification.ActorId) {
+		return
+	}
+	if notifier.NotificationRepository.IsMuted(ctx, tx, notification.UserId, notification.Type) {
+		return
+	}
+	if types := dedupedNotificationTypes[notification.Type]; types != nil && notifier.NotificationRepository.HasRecent(ctx, tx, notification, types, config.NotificationDedupeWindow) {
+		return
+	}
+	notification = notifier.NotificationRepository.Create(ctx, tx, notification)
+
+	notification, err := notifier.NotificationRepository.FindByID(ctx, tx, notification.Id)
+	helper.PanicIfNotFound(err, "notification not found")
+	publishAfterCommit(ctx, tx, notifier.Publisher, realtime.UserTopic(notification.UserId), "notification.created", helper.ToNotificationResponse(notification))
+}
+
+// NotifyFollowers tells the followers of the author that an article was
+// published.
+func (notifier *Notifier) NotifyFollowers(ctx context.Context, tx *sql.Tx, article entity.Article) {
+	for _, followerId := range notifier.FollowRepository.FindFollowerIDs(ctx, tx, article.UserId) {
+		notifier.Notify(ctx, tx, entity.Notification{
+			UserId:     followerId,
+			ActorId:    article.UserId,
+			Type:       "new_article",
+			TargetType: "article",
+			TargetId:   article.Id,
+			ArticleId:  article.Id,
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type ReadingListService interface {
+	Create(ctx context.Context, request request.ReadingListCreateRequest) response.ReadingListResponse
+	Update(ctx context.Context, request request.ReadingListUpdateRequest) response.ReadingListResponse
+	Delete(ctx context.Context, readingListId int, userId int)
+	FindByID(ctx context.Context, readingListId int, userId int) response.ReadingListResponse
+	FindAllByUserID(ctx context.Context, userId int, viewerId int) []response.ReadingListResponse
+	CreateItem(ctx context.Context, request request.ReadingListItemRequest) response.ReadingListResponse
+	UpdateItem(ctx context.Context, request request.ReadingListItemRequest) response.ReadingListResponse
+	DeleteItem(ctx context.Context, readingListId int, articleId int, userId int)
+	Reorder(ctx context.Context, request request.ReadingListReorderRequest) response.ReadingListResponse
+}
+
+type ReadingListServiceImpl struct {
+	repositories.ReadingListRepository
+	ArticleRepository repositories.ArticleRepository
+	MentionRepository repositories.MentionRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewReadingListService(readingListRepository repositories.ReadingListRepository, articleRepository repositories.ArticleRepository, mentionRepository repositories.MentionRepository, db *sql.DB, validate *validator.Validate) ReadingListService {
+	return &ReadingListServiceImpl{
+		ReadingListRepository: readingListRepository,
+		ArticleRepository:     articleRepository,
+		MentionRepository:     mentionRepository,
+		DB:                    db,
+		Validate:              validate,
+	}
+}
+
+func (service *ReadingListServiceImpl) Create(ctx context.Context, request request.ReadingListCreateRequest) response.ReadingListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.ReadingListRepository.Create(ctx, tx, entity.ReadingList{
+		UserId:      request.UserId,
+		Name:        request.Name,
+		Description: request.Description,
+		IsPublic:    request.IsPublic,
+	})
+
+	return service.loadReadingList(ctx, tx, readingList.Id)
+}
+
+func (service *ReadingListServiceImpl) Update(ctx context.Context, request request.ReadingListUpdateRequest) response.ReadingListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.findOwnReadingList(ctx, tx, request.Id, request.UserId)
+	readingList.Name = request.Name
+	readingList.Description = request.Description
+	readingList.IsPublic = request.IsPublic
+	service.ReadingListRepository.Update(ctx, tx, readingList)
+
+	return service.loadReadingList(ctx, tx, readingList.Id)
+}
+
+func (service *ReadingListServiceImpl) Delete(ctx context.Context, readingListId int, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.findOwnReadingList(ctx, tx, readingListId, userId)
+	service.ReadingListRepository.Delete(ctx, tx, readingList.Id)
+}
+
+// FindByID returns a reading list with its items. Private lists are only
+// visible to their owner; to anyone else they do not exist.
+func (service *ReadingListServiceImpl) FindByID(ctx context.Context, readingListId int, userId int) response.ReadingListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList, err := service.ReadingListRepository.FindByID(ctx, tx, readingListId)
+	if err != nil || (!readingList.IsPublic && readingList.UserId != userId) {
+		panic(exception.NewNotFoundError("reading list not found"))
+	}
+
+	return service.loadReadingList(ctx, tx, readingList.Id)
+}
+
+// FindAllByUserID lists the reading lists of userId without their items,
+// leaving out private lists unless viewerId is the owner.
+func (service *ReadingListServiceImpl) FindAllByUserID(ctx context.Context, userId int, viewerId int) []response.ReadingListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingLists := service.ReadingListRepository.FindAllByUserID(ctx, tx, userId, userId != viewerId)
+	return helper.ToReadingListResponses(readingLists)
+}
+
+func (service *ReadingListServiceImpl) CreateItem(ctx context.Context, request request.ReadingListItemRequest) response.ReadingListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.findOwnReadingList(ctx, tx, request.ReadingListId, request.UserId)
+
+	article, err := service.ArticleRepository.FindByID(ctx, tx, request.ArticleId)
+	if err != nil || !article.IsPublished {
+		panic(exception.NewNotFoundError("article not found"))
+	}
+	if _, err := service.ReadingListRepository.FindItem(ctx, tx, readingList.Id, article.Id); err == nil {
+		panic(exception.NewInvalidParameter("article is already in this reading list"))
+	}
+
+	service.ReadingListRepository.CreateItem(ctx, tx, entity.ReadingListItem{
+		ReadingListId: readingList.Id,
+		Position:      service.ReadingListRepository.NextItemPosition(ctx, tx, readingList.Id),
+		Note:          request.Note,
+		Article:       article,
+	})
+
+	return service.loadReadingList(ctx, tx, readingList.Id)
+}
+
+func (service *ReadingListServiceImpl) UpdateItem(ctx context.Context, request request.ReadingListItemRequest) response.ReadingListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.findOwnReadingList(ctx, tx, request.ReadingListId, request.UserId)
+
+	item, err := service.ReadingListRepository.FindItem(ctx, tx, readingList.Id, request.ArticleId)
+	helper.PanicIfNotFound(err, "reading list item not found")
+	item.Note = request.Note
+	service.ReadingListRepository.UpdateItem(ctx, tx, item)
+
+	return service.loadReadingList(ctx, tx, readingList.Id)
+}
+
+func (service *ReadingListServiceImpl) DeleteItem(ctx context.Context, readingListId int, articleId int, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.findOwnReadingList(ctx, tx, readingListId, userId)
+
+	_, err = service.ReadingListRepository.FindItem(ctx, tx, readingList.Id, articleId)
+	helper.PanicIfNotFound(err, "reading list item not found")
+	service.ReadingListRepository.DeleteItem(ctx, tx, readingList.Id, articleId)
+}
+
+// Reorder puts the items in the order of request.ArticleIds, which must name
+// every visible item once. Items hidden because their article was unpublished
+// keep their relative order after the others.
+func (service *ReadingListServiceImpl) Reorder(ctx context.Context, request request.ReadingListReorderRequest) response.ReadingListResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	readingList := service.findOwnReadingList(ctx, tx, request.ReadingListId, request.UserId)
+
+	visible := map[int]bool{}
+	for _, item := range service.ReadingListRepository.FindItems(ctx, tx, readingList.Id) {
+		visible[item.Article.Id] = true
+	}
+	ordered := map[int]bool{}
+	for _, articleId := range request.ArticleIds {
+		if !visible[articleId] || ordered[articleId] {
+			panic(exception.NewInvalidParameter("article_ids must list every article of the reading list once"))
+		}
+		ordered[articleId] = true
+	}
+	if len(ordered) != len(visible) {
+		panic(exception.NewInvalidParameter("article_ids must list every article of the reading list once"))
+	}
+
+	articleIds := request.ArticleIds
+	for _, articleId := range service.ReadingListRepository.FindItemArticleIDs(ctx, tx, readingList.Id) {
+		if !ordered[articleId] {
+			articleIds = append(articleIds, articleId)
+		}
+	}
+	for i, articleId := range articleIds {
+		item, err := service.ReadingListRepository.FindItem(ctx, tx, readingList.Id, articleId)
+		helper.PanicIfNotFound(err, "reading list item not found")
+		item.Position = i + 1
+		service.ReadingListRepository.UpdateItem(ctx, tx, item)
+	}
+
+	return service.loadReadingList(ctx, tx, readingList.Id)
+}
+
+func (service *ReadingListServiceImpl) findOwnReadingList(ctx context.Context, tx *sql.Tx, readingListId int, userId int) entity.ReadingList {
+	readingList, err := service.ReadingListRepository.FindByID(ctx, tx, readingListId)
+	helper.PanicIfNotFound(err, "reading list not found")
+	if readingList.UserId != userId {
+		panic(exception.NewInvalidCredentialsError("you cannot change another user reading list"))
+	}
+	return readingList
+}
+
+func (service *ReadingListServiceImpl) loadReadingList(ctx context.Context, tx *sql.Tx, readingListId int) response.ReadingListResponse {
+	readingList, err := service.ReadingListRepository.FindByID(ctx, tx, readingListId)
+	helper.PanicIfNotFound(err, "reading list not found")
+	readingList.Items = service.ReadingListRepository.FindItems(ctx, tx, readingList.Id)
+
+	var articles []entity.Article
+	for _, item := range readingList.Items {
+		articles = append(articles, item.Article)
+	}
+	for i, article := range loadDetails(ctx, tx, service.ArticleRepository, service.MentionRepository, articles) {
+		readingList.Items[i].Article = article
+	}
+	return helper.ToReadingListResponse(readingList)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/config"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/web/request"
+	"uaspw2/realtime"
+	"uaspw2/repositories"
+)
+
+// RealtimeService opens the event streams of connected clients. A user always
+// receives their own notifications and may follow the comments and like
+// counts of the articles they can read.
+type RealtimeService interface {
+	Subscribe(ctx context.Context, request request.RealtimeSubscribeRequest) *realtime.Subscription
+	FollowArticle(ctx context.Context, subscription *realtime.Subscription, userId int, articleId int)
+	UnfollowArticle(subscription *realtime.Subscription, articleId int)
+}
+
+type RealtimeServiceImpl struct {
+	ArticleRepository repositories.ArticleRepository
+	Hub               *realtime.Hub
+	*sql.DB
+	*validator.Validate
+}
+
+func NewRealtimeService(articleRepository repositories.ArticleRepository, hub *realtime.Hub, db *sql.DB, validate *validator.Validate) RealtimeService {
+	return &RealtimeServiceImpl{
+		ArticleRepository: articleRepository,
+		Hub:               hub,
+		DB:                db,
+		Validate:          validate,
+	}
+}
+
+func (service *RealtimeServiceImpl) Subscribe(ctx context.Context, request request.RealtimeSubscribeRequest) *realtime.Subscription {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	if len(request.ArticleIds) > config.RealtimeMaxArticles {
+		panic(exception.NewInvalidParameter(fmt.Sprintf("you can follow at most %d articles at once", config.RealtimeMaxArticles)))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	topics := []string{realtime.UserTopic(request.UserId)}
+	for _, articleId := range request.ArticleIds {
+		topics = append(topics, service.articleTopic(ctx, tx, request.UserId, articleId))
+	}
+	return service.Hub.Subscribe(topics...)
+}
+
+// FollowArticle adds an article to an open subscription.
+func (service *RealtimeServiceImpl) FollowArticle(ctx context.Context, subscription *realtime.Subscription, userId int, articleId int) {
+	// the user topic is not an article
+	if subscription.Count() > config.RealtimeMaxArticles {
+		panic(exception.NewInvalidParameter(fmt.Sprintf("you can follow at most %d articles at once", config.RealtimeMaxArticles)))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	subscription.Add(service.articleTopic(ctx, tx, userId, articleId))
+}
+
+func (service *RealtimeServiceImpl) UnfollowArticle(subscription *realtime.Subscription, articleId int) {
+	subscription.Remove(realtime.ArticleTopic(articleId))
+}
+
+// articleTopic returns the topic of an article the user may read: a
+// published one, or a draft of their own.
+func (service *RealtimeServiceImpl) articleTopic(ctx context.Context, tx *sql.Tx, userId int, articleId int) string {
+	article, err := service.ArticleRepository.FindByID(ctx, tx, articleId)
+	if err != nil || (!article.IsPublished && article.UserId != userId) {
+		panic(exception.NewNotFoundError("article not found"))
+	}
+	return realtime.ArticleTopic(article.Id)
+}
+
+// publishAfterCommit pushes an event once tx commits, so clients never see
+// data that is rolled back afterwards.
+func publishAfterCommit(ctx context.Context, tx *sql.Tx, publisher realtime.Publisher, topic string, eventType string, data any) {
+	helper.AfterCommit(tx, func() {
+		publisher.Publish(ctx, topic, eventType, data)
+	})
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+type TagService interface {
+	FindAll(ctx context.Context, query string) []response.TagResponse
+	FindArticlesBySlug(ctx context.Context, request request.TagArticlesRequest) response.TagArticlesResponse
+	Update(ctx context.Context, request request.TagUpdateRequest) response.TagResponse
+	Merge(ctx context.Context, request request.TagMergeRequest) response.TagResponse
+	Delete(ctx context.Context, tagId int)
+}
+
+type TagServiceImpl struct {
+	repositories.TagRepository
+	ArticleRepository repositories.ArticleRepository
+	MentionRepository repositories.MentionRepository
+	*sql.DB
+	*validator.Validate
+}
+
+func NewTagService(tagRepository repositories.TagRepository, articleRepository repositories.ArticleRepository, mentionRepository repositories.MentionRepository, db *sql.DB, validate *validator.Validate) TagService {
+	return &TagServiceImpl{
+		TagRepository:     tagRepository,
+		ArticleRepository: articleRepository,
+		MentionRepository: mentionRepository,
+		DB:                db,
+		Validate:          validate,
+	}
+}
+
+// FindAll doubles as tag autocomplete: query is matched against the start of the slug.
+func (service *TagServiceImpl) FindAll(ctx context.Context, query string) []response.TagResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	limit := 100
+	if query != "" {
+		limit = 10
+	}
+
+	tags := service.TagRepository.FindAll(ctx, tx, helper.Slugify(query), limit)
+	return helper.ToTagResponses(tags)
+}
+
+func (service *TagServiceImpl) FindArticlesBySlug(ctx context.Context, request request.TagArticlesRequest) response.TagArticlesResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+	beforeCreatedAt, beforeId := helper.DecodeCursor(request.Cursor)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	tag, err := service.TagRepository.FindBySlug(ctx, tx, request.Slug)
+	helper.PanicIfNotFound(err, "tag not found")
+
+	// one extra row tells whether there is a next page
+	articles := service.ArticleRepository.FindAllPublishedByTagID(ctx, tx, tag.Id, beforeCreatedAt, beforeId, request.Limit+1)
+
+	tagArticlesResponse := response.TagArticlesResponse{Tag: helper.ToTagResponse(tag)}
+	if len(articles) > request.Limit {
+		articles = articles[:request.Limit]
+		last := articles[len(articles)-1]
+		tagArticlesResponse.NextCursor = helper.EncodeCursor(last.CreatedAt, last.Id)
+	}
+	tagArticlesResponse.Articles = helper.ToArticleResponses(loadDetails(ctx, tx, service.ArticleRepository, service.MentionRepository, articles))
+	return tagArticlesResponse
+}
+
+func (service *TagServiceImpl) Update(ctx context.Context, request request.TagUpdateRequest) response.TagResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	tag, err := service.TagRepository.FindByID(ctx, tx, request.Id)
+	helper.PanicIfNotFound(err, "tag not found")
+
+	slug := helper.TruncateSlug(helper.Slugify(request.Name), 64)
+	if slug == "" {
+		panic(exception.NewInvalidParameter("tag name must contain letters or digits"))
+	}
+	if existing, err := service.TagRepository.FindBySlug(ctx, tx, slug); err == nil && existing.Id != tag.Id {
+		panic(exception.NewInvalidParameter("tag " + existing.Name + " already exists, merge the tags instead"))
+	}
+
+	tag.Name = request.Name
+	tag.Slug = slug
+	helper.RetryOnDuplicateKey(1, "tag already exists, merge the tags instead", func(int) {
+		service.TagRepository.Update(ctx, tx, tag)
+	})
+
+	tag, err = service.TagRepository.FindByID(ctx, tx, tag.Id)
+	helper.PanicIfErr(err)
+
+	return helper.ToTagResponse(tag)
+}
+
+// Merge moves every article of the source tag to the target tag and removes the source.
+func (service *TagServiceImpl) Merge(ctx context.Context, request request.TagMergeRequest) response.TagResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	source, err := service.TagRepository.FindByID(ctx, tx, request.SourceId)
+	helper.PanicIfNotFound(err, "source tag not found")
+
+	target, err := service.TagRepository.FindByID(ctx, tx, request.TargetId)
+	helper.PanicIfNotFound(err, "target tag not found")
+
+	service.TagRepository.MoveArticles(ctx, tx, source.Id, target.Id)
+	service.TagRepository.Delete(ctx, tx, source.Id)
+
+	target, err = service.TagRepository.FindByID(ctx, tx, target.Id)
+	helper.PanicIfErr(err)
+
+	return helper.ToTagResponse(target)
+}
+
+func (service *TagServiceImpl) Delete(ctx context.Context, tagId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	tag, err := service.TagRepository.FindByID(ctx, tx, tagId)
+	helper.PanicIfNotFound(err, "tag not found")
+
+	service.TagRepository.Delete(ctx, tx, tag.Id)
+}
+
+// findOrCreateTags resolves free-form tag names to tags, creating missing ones.
+// Names that only differ in case or punctuation map to the same tag.
+func findOrCreateTags(ctx context.Context, tx *sql.Tx, tagRepository repositories.TagRepository, names []string) []entity.Tag {
+	var tags []entity.Tag
+	seen := map[string]bool{}
+	for _, name := range names {
+		slug := helper.TruncateSlug(helper.Slugify(name), 64)
+		if slug == "" {
+			panic(exception.NewInvalidParameter("tag name must contain letters or digits"))
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		tag, err := tagRepository.FindBySlug(ctx, tx, slug)
+		if err != nil {
+			helper.RetryOnDuplicateKey(2, "could not create tag "+name+", try again", func(attempt int) {
+				if attempt > 0 {
+					// a concurrent request created the tag first
+					tag, err = tagRepository.LockBySlug(ctx, tx, slug)
+					helper.PanicIfErr(err)
+					return
+				}
+				tag = tagRepository.Create(ctx, tx, entity.Tag{Name: name, Slug: slug})
+			})
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -1,17 +1,25 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2/log"
+	"time"
 	"uaspw2/exception"
 	"uaspw2/helper"
 	"uaspw2/models/entity"
 	"uaspw2/models/web/request"
 	"uaspw2/models/web/response"
 	"uaspw2/repositories"
+	"uaspw2/storage"
 )
 
+// DefaultProfilePhoto is served from ./public and never lives in the storage backend.
+const DefaultProfilePhoto = "default_profile_photo.svg"
+
 type UserProfilePhotoService interface {
 	UpdateByUserId(ctx context.Context, request request.UserProfilePhotoRequest) response.UserProfilePhotoResponse
 	FindByUserId(ctx context.Context, userId int) response.UserProfilePhotoResponse
@@ -21,13 +29,15 @@ type UserProfilePhotoServiceImpl struct {
 	UserProfilePhotoRepository repositories.UserProfilePhotoRepository
 	DB                         *sql.DB
 	Validate                   *validator.Validate
+	Storage                    storage.Storage
 }
 
-func NewUserProfilePhotoService(userProfilePhotoRepository repositories.UserProfilePhotoRepository, DB *sql.DB, validate *validator.Validate) UserProfilePhotoService {
+func NewUserProfilePhotoService(userProfilePhotoRepository repositories.UserProfilePhotoRepository, DB *sql.DB, validate *validator.Validate, storage storage.Storage) UserProfilePhotoService {
 	return &UserProfilePhotoServiceImpl{
 		UserProfilePhotoRepository: userProfilePhotoRepository,
 		DB:                         DB,
 		Validate:                   validate,
+		Storage:                    storage,
 	}
 }
 
@@ -35,17 +45,123 @@ func (service *UserProfilePhotoServiceImpl) UpdateByUserId(ctx context.Context,
 	err := service.Validate.Struct(request)
 	helper.PanicIfErr(err)
 
+	file, err := request.File.Open()
+	helper.PanicIfErr(err)
+	defer file.Close()
+
+	prefix := fmt.Sprintf("profile_photos/%d_%d", request.UserId, time.Now().UnixNano())
+	var photo entity.UserProfilePhoto
+
+	contentType, err := helper.DetectProfilePhoto(file, request.File)
+	if err != nil {
+		panic(exception.NewInvalidParameter(err.Error()))
+	}
+
+	if contentType == "image/svg+xml" {
+		// vector images are not resized, only stripped of scripts and external references
+		data, err := helper.SanitizeSvg(file)
+		if err != nil {
+			panic(exception.NewInvalidParameter(err.Error()))
+		}
+		photo.Path = prefix + ".svg"
+		err = service.Storage.Put(ctx, photo.Path, bytes.NewReader(data), int64(len(data)), contentType)
+		helper.PanicIfErr(err)
+	} else {
+		variants, err := helper.ProcessProfilePhoto(file)
+		if err != nil {
+			panic(exception.NewInvalidParameter(err.Error()))
+		}
+		photo.Variants = service.putVariants(ctx, request.UserId, prefix, variants)
+		photo.Path = photo.Variants[len(helper.ProfilePhotoSizes)-1].Path
+	}
+
+	// the new files must not outlive a failed update
+	defer func() {
+		if err := recover(); err != nil {
+			service.deleteFiles(ctx, photo)
+			panic(err)
+		}
+	}()
+
+	oldPhoto, result := service.replacePhoto(ctx, request.UserId, photo)
+
+	if oldPhoto.Path != DefaultProfilePhoto {
+		service.deleteFiles(ctx, oldPhoto)
+	}
+
+	return service.toUserProfilePhotoResponse(result)
+}
+
+func (service *UserProfilePhotoServiceImpl) putVariants(ctx context.Context, userId int, prefix string, variants []helper.ImageVariant) []entity.UserProfilePhotoVariant {
+	var stored []entity.UserProfilePhotoVariant
+
+	defer func() {
+		if err := recover(); err != nil {
+			service.deleteFiles(ctx, entity.UserProfilePhoto{Variants: stored})
+			panic(err)
+		}
+	}()
+
+	for _, variant := range variants {
+		key := prefix + "_" + variant.Name + variant.Extension
+		err := service.Storage.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType)
+		helper.PanicIfErr(err)
+
+		stored = append(stored, entity.UserProfilePhotoVariant{
+			UserId:      userId,
+			Name:        variant.Name,
+			Path:        key,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			ContentType: variant.ContentType,
+		})
+	}
+	return stored
+}
+
+func (service *UserProfilePhotoServiceImpl) replacePhoto(ctx context.Context, userId int, photo entity.UserProfilePhoto) (entity.UserProfilePhoto, entity.UserProfilePhoto) {
 	tx, err := service.DB.Begin()
 	helper.PanicIfErr(err)
 	defer helper.CommitOrRollback(tx)
 
-	userProfilePhoto := entity.UserProfilePhoto{
-		UserId: request.UserId,
-		Path:   request.Path,
+	oldPhoto, err := service.UserProfilePhotoRepository.FindByUserID(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	oldPhoto.Variants = service.UserProfilePhotoRepository.FindVariantsByUserID(ctx, tx, userId)
+
+	service.UserProfilePhotoRepository.Update(ctx, tx, entity.UserProfilePhoto{
+		UserId: userId,
+		Path:   photo.Path,
+	})
+	service.UserProfilePhotoRepository.DeleteVariantsByUserID(ctx, tx, userId)
+	for _, variant := range photo.Variants {
+		service.UserProfilePhotoRepository.CreateVariant(ctx, tx, variant)
+	}
+
+	result, err := service.UserProfilePhotoRepository.FindByUserID(ctx, tx, userId)
+	helper.PanicIfErr(err)
+	result.Variants = service.UserProfilePhotoRepository.FindVariantsByUserID(ctx, tx, userId)
+
+	return oldPhoto, result
+}
+
+// deleteFiles removes the photo and all of its variants from storage.
+// Failures are only logged: the database no longer points at these files.
+func (service *UserProfilePhotoServiceImpl) deleteFiles(ctx context.Context, photo entity.UserProfilePhoto) {
+	keys := map[string]bool{}
+	if photo.Path != "" {
+		keys[photo.Path] = true
+	}
+	for _, variant := range photo.Variants {
+		keys[variant.Path] = true
 	}
 
-	result := service.UserProfilePhotoRepository.Update(ctx, tx, userProfilePhoto)
-	return helper.ToUserPhotoProfileResponse(result)
+	for key := range keys {
+		if err := service.Storage.Delete(ctx, key); err != nil {
+			log.Warnf("failed to remove profile photo %s: %v", key, err)
+		}
+	}
 }
 
 func (service *UserProfilePhotoServiceImpl) FindByUserId(ctx context.Context, userId int) response.UserProfilePhotoResponse {
@@ -58,5 +174,28 @@ func (service *UserProfilePhotoServiceImpl) FindByUserId(ctx context.Context, us
 	if err != nil {
 		panic(exception.NewNotFoundError(err.Error()))
 	}
-	return helper.ToUserPhotoProfileResponse(result)
+	result.Variants = service.UserProfilePhotoRepository.FindVariantsByUserID(ctx, tx, userId)
+
+	return service.toUserProfilePhotoResponse(result)
+}
+
+func (service *UserProfilePhotoServiceImpl) toUserProfilePhotoResponse(photo entity.UserProfilePhoto) response.UserProfilePhotoResponse {
+	photoResponse := helper.ToUserPhotoProfileResponse(photo)
+	photoResponse.Url = profilePhotoUrl(service.Storage, photo.Path)
+	for i := range photoResponse.Variants {
+		photoResponse.Variants[i].Url = service.Storage.URL(photoResponse.Variants[i].Path)
+	}
+	return photoResponse
+}
+
+// profilePhotoUrl returns where clients load the profile photo stored at path.
+func profilePhotoUrl(fileStorage storage.Storage, path string) string {
+	switch path {
+	case "":
+		return ""
+	case DefaultProfilePhoto:
+		return "/profile_photos/" + DefaultProfilePhoto
+	default:
+		return fileStorage.URL(path)
+	}
 }
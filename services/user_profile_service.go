@@ -43,13 +43,20 @@ func (service *UserProfileServiceImpl) Update(ctx context.Context, request reque
 	if err != nil {
 		panic(exception.NewNotFoundError(err.Error()))
 	}
+	// version 0 stands for "If-Match: *"
+	if request.Version != 0 && userProfile.Version != request.Version {
+		panic(exception.NewPreconditionFailedError("user profile has been modified, reload it and try again"))
+	}
 	userProfile.FullName = request.FullName
 	userProfile.Address = request.Address
 	userProfile.Gender = request.Gender
 	userProfile.PhoneNumber = request.PhoneNumber
 	userProfile.BirthDate = request.BirthDate
 
-	userProfile = service.UserProfileRepository.Update(ctx, tx, userProfile)
+	userProfile, err = service.UserProfileRepository.Update(ctx, tx, userProfile)
+	if err != nil {
+		panic(exception.NewPreconditionFailedError(err.Error()))
+	}
 
 	return helper.ToUserProfileResponse(userProfile)
 }
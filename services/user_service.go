@@ -17,6 +17,7 @@ type UserService interface {
 	Delete(ctx context.Context, id int)
 	FindByID(ctx context.Context, id int) response.UserResponse
 	FindAll(ctx context.Context) []response.UserResponse
+	IsSuspended(ctx context.Context, id int) bool
 }
 
 type UserServiceImpl struct {
@@ -95,3 +96,13 @@ func (service *UserServiceImpl) FindAll(ctx context.Context) []response.UserResp
 	users := service.UserRepository.FindAll(ctx, tx)
 	return helper.ToUserResponses(users)
 }
+
+// IsSuspended reports whether the user is suspended right now. The auth
+// middlewares ask on every request, so a suspension also ends open sessions.
+func (service *UserServiceImpl) IsSuspended(ctx context.Context, id int) bool {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	return service.UserRepository.IsSuspended(ctx, tx, id)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+)
+
+// WebhookDispatcher queues a delivery for every active webhook subscribed to
+// an event. It works inside the caller's transaction, so a delivery exists
+// exactly when the change it reports was committed; WebhookService sends it.
+type WebhookDispatcher struct {
+	WebhookRepository repositories.WebhookRepository
+}
+
+func NewWebhookDispatcher(webhookRepository repositories.WebhookRepository) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		WebhookRepository: webhookRepository,
+	}
+}
+
+func (dispatcher *WebhookDispatcher) Dispatch(ctx context.Context, tx *sql.Tx, event string, data any) {
+	webhooks := dispatcher.WebhookRepository.FindActiveByEvent(ctx, tx, event)
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(response.WebhookPayload{
+		Event:      event,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339),
+		Data:       data,
+	})
+	helper.PanicIfErr(err)
+
+	for _, webhook := range webhooks {
+		dispatcher.WebhookRepository.CreateDelivery(ctx, tx, entity.WebhookDelivery{
+			WebhookId: webhook.Id,
+			Event:     event,
+			Payload:   string(payload),
+		})
+	}
+}
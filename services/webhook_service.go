@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2/log"
+	"sync"
+	"uaspw2/config"
+	"uaspw2/exception"
+	"uaspw2/helper"
+	"uaspw2/models/entity"
+	"uaspw2/models/web/request"
+	"uaspw2/models/web/response"
+	"uaspw2/repositories"
+	"uaspw2/webhook"
+)
+
+// maxDeliveryLog is how many of the latest deliveries a webhook log shows.
+const maxDeliveryLog = 100
+
+type WebhookService interface {
+	Create(ctx context.Context, request request.WebhookCreateRequest) response.WebhookResponse
+	Update(ctx context.Context, request request.WebhookUpdateRequest) response.WebhookResponse
+	Delete(ctx context.Context, webhookId int)
+	FindAll(ctx context.Context) []response.WebhookResponse
+	FindByID(ctx context.Context, webhookId int) response.WebhookResponse
+	FindDeliveries(ctx context.Context, webhookId int) []response.WebhookDeliveryResponse
+	FindDelivery(ctx context.Context, webhookId int, deliveryId int) response.WebhookDeliveryResponse
+	Redeliver(ctx context.Context, webhookId int, deliveryId int) response.WebhookDeliveryResponse
+	DeliverDue(ctx context.Context)
+}
+
+type WebhookServiceImpl struct {
+	repositories.WebhookRepository
+	Client *webhook.Client
+	*sql.DB
+	*validator.Validate
+}
+
+func NewWebhookService(webhookRepository repositories.WebhookRepository, client *webhook.Client, db *sql.DB, validate *validator.Validate) WebhookService {
+	return &WebhookServiceImpl{
+		WebhookRepository: webhookRepository,
+		Client:            client,
+		DB:                db,
+		Validate:          validate,
+	}
+}
+
+// Create registers a webhook. The secret, generated when none is given, is
+// only returned here and by an Update that changes it.
+func (service *WebhookServiceImpl) Create(ctx context.Context, request request.WebhookCreateRequest) response.WebhookResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	secret := request.Secret
+	if secret == "" {
+		secret = webhook.NewSecret()
+	}
+	created := service.WebhookRepository.Create(ctx, tx, entity.Webhook{
+		Url:       request.Url,
+		Secret:    secret,
+		Events:    request.Events,
+		IsActive:  true,
+		CreatedBy: request.CreatedBy,
+	})
+
+	webhookResponse := helper.ToWebhookResponse(service.findWebhook(ctx, tx, created.Id))
+	webhookResponse.Secret = secret
+	return webhookResponse
+}
+
+func (service *WebhookServiceImpl) Update(ctx context.Context, request request.WebhookUpdateRequest) response.WebhookResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfErr(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	existing := service.findWebhook(ctx, tx, request.Id)
+	existing.Url = request.Url
+	existing.Events = request.Events
+	existing.IsActive = request.IsActive
+	if request.Secret != "" {
+		existing.Secret = request.Secret
+	}
+	service.WebhookRepository.Update(ctx, tx, existing)
+
+	webhookResponse := helper.ToWebhookResponse(service.findWebhook(ctx, tx, existing.Id))
+	webhookResponse.Secret = request.Secret
+	return webhookResponse
+}
+
+func (service *WebhookServiceImpl) Delete(ctx context.Context, webhookId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	existing := service.findWebhook(ctx, tx, webhookId)
+	service.WebhookRepository.Delete(ctx, tx, existing.Id)
+}
+
+func (service *WebhookServiceImpl) FindAll(ctx context.Context) []response.WebhookResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	webhooks := service.WebhookRepository.FindAll(ctx, tx)
+	return helper.ToWebhookResponses(webhooks)
+}
+
+func (service *WebhookServiceImpl) FindByID(ctx context.Context, webhookId int) response.WebhookResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	return helper.ToWebhookResponse(service.findWebhook(ctx, tx, webhookId))
+}
+
+func (service *WebhookServiceImpl) FindDeliveries(ctx context.Context, webhookId int) []response.WebhookDeliveryResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	existing := service.findWebhook(ctx, tx, webhookId)
+	deliveries := service.WebhookRepository.FindDeliveriesByWebhookID(ctx, tx, existing.Id, maxDeliveryLog)
+	return helper.ToWebhookDeliveryResponses(deliveries)
+}
+
+// FindDelivery returns a delivery with the log of its attempts.
+func (service *WebhookServiceImpl) FindDelivery(ctx context.Context, webhookId int, deliveryId int) response.WebhookDeliveryResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	delivery := service.findDelivery(ctx, tx, webhookId, deliveryId)
+	return helper.ToWebhookDeliveryResponse(delivery)
+}
+
+// Redeliver queues a delivery again, whatever its status, with a fresh retry
+// budget. It is sent on the next run of DeliverDue.
+func (service *WebhookServiceImpl) Redeliver(ctx context.Context, webhookId int, deliveryId int) response.WebhookDeliveryResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	delivery := service.findDelivery(ctx, tx, webhookId, deliveryId)
+	if delivery.Status == "pending" && delivery.AttemptCount == 0 {
+		panic(exception.NewInvalidParameter("delivery has not been attempted yet"))
+	}
+	service.WebhookRepository.ResetDelivery(ctx, tx, delivery.Id)
+
+	return helper.ToWebhookDeliveryResponse(service.findDelivery(ctx, tx, webhookId, deliveryId))
+}
+
+// DeliverDue sends one batch of due deliveries concurrently and records the
+// outcome of each attempt. A failed delivery is retried with exponential
+// backoff until config.WebhookMaxAttempts is reached.
+func (service *WebhookServiceImpl) DeliverDue(ctx context.Context) {
+	deliveries := service.claimDue(ctx)
+
+	var wait sync.WaitGroup
+	for _, delivery := range deliveries {
+		wait.Add(1)
+		go func(delivery entity.WebhookDelivery) {
+			defer wait.Done()
+			// RunPeriodically only recovers its own goroutine
+			defer func() {
+				if err := recover(); err != nil {
+					log.Errorf("webhook delivery %d failed: %v", delivery.Id, err)
+				}
+			}()
+			service.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wait.Wait()
+}
+
+func (service *WebhookServiceImpl) claimDue(ctx context.Context) []entity.WebhookDelivery {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	// the lease outlasts the request timeout, so a delivery is only claimed
+	// again when its sender is gone
+	return service.WebhookRepository.ClaimDueDeliveries(ctx, tx, config.WebhookBatchSize, 2*config.WebhookTimeout)
+}
+
+func (service *WebhookServiceImpl) deliver(ctx context.Context, delivery entity.WebhookDelivery) {
+	target, ok := service.findTarget(ctx, delivery.WebhookId)
+	if !ok {
+		return
+	}
+
+	result := service.Client.Send(ctx, webhook.Request{
+		Url:        target.Url,
+		Secret:     target.Secret,
+		Event:      delivery.Event,
+		DeliveryId: delivery.Id,
+		Payload:    []byte(delivery.Payload),
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	attempt := entity.WebhookDeliveryAttempt{
+		DeliveryId:   delivery.Id,
+		ResponseCode: result.StatusCode,
+		ResponseBody: result.Body,
+		DurationMs:   int(result.Duration.Milliseconds()),
+	}
+	if result.Err != nil {
+		attempt.Error = result.Err.Error()
+		if len(attempt.Error) > 512 {
+			attempt.Error = attempt.Error[:512]
+		}
+	}
+	service.WebhookRepository.CreateAttempt(ctx, tx, attempt)
+
+	delivery.AttemptCount++
+	delivery.ResponseCode = result.StatusCode
+	retryIn := webhook.Backoff(delivery.AttemptCount, config.WebhookRetryBase, config.WebhookRetryMax)
+	switch {
+	case result.Succeeded():
+		delivery.Status = "succeeded"
+		retryIn = 0
+	case delivery.AttemptCount >= config.WebhookMaxAttempts:
+		delivery.Status = "failed"
+		retryIn = 0
+	default:
+		delivery.Status = "pending"
+	}
+	service.WebhookRepository.FinishAttempt(ctx, tx, delivery, retryIn)
+}
+
+// findTarget reads the webhook a claimed delivery goes to. It may have been
+// deleted since, which also deletes the delivery.
+func (service *WebhookServiceImpl) findTarget(ctx context.Context, webhookId int) (entity.Webhook, bool) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfErr(err)
+	defer helper.CommitOrRollback(tx)
+
+	target, err := service.WebhookRepository.FindByID(ctx, tx, webhookId)
+	return target, err == nil
+}
+
+func (service *WebhookServiceImpl) findWebhook(ctx context.Context, tx *sql.Tx, webhookId int) entity.Webhook {
+	existing, err := service.WebhookRepository.FindByID(ctx, tx, webhookId)
+	helper.PanicIfNotFound(err, "webhook not found")
+	return existing
+}
+
+func (service *WebhookServiceImpl) findDelivery(ctx context.Context, tx *sql.Tx, webhookId int, deliveryId int) entity.WebhookDelivery {
+	delivery, err := service.WebhookRepository.FindDeliveryByID(ctx, tx, deliveryId)
+	if err != nil || delivery.WebhookId != webhookId {
+		panic(exception.NewNotFoundError("delivery not found"))
+	}
+	delivery.Attempts = service.WebhookRepository.FindAttemptsByDeliveryID(ctx, tx, delivery.Id)
+	return delivery
+}
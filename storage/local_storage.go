@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LocalStorage struct {
+	BaseDir string
+	BaseUrl string
+}
+
+func NewLocalStorage(baseDir string, baseUrl string) Storage {
+	return &LocalStorage{
+		BaseDir: baseDir,
+		BaseUrl: strings.TrimRight(baseUrl, "/"),
+	}
+}
+
+func (storage *LocalStorage) Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(storage.BaseDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return err
+	}
+	return file.Close()
+}
+
+func (storage *LocalStorage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(storage.BaseDir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (storage *LocalStorage) URL(key string) string {
+	return storage.BaseUrl + "/" + strings.TrimPrefix(key, "/")
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config describes any S3-compatible endpoint (AWS S3, MinIO, ...).
+// MinIO and most self-hosted servers need UsePathStyle. With SignUrls the
+// bucket can stay private and URL hands out presigned GET links instead.
+type S3Config struct {
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	PublicUrl    string
+	UsePathStyle bool
+	SignUrls     bool
+	UrlExpiry    time.Duration
+}
+
+type S3Storage struct {
+	S3Config
+	Client *http.Client
+}
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+func NewS3Storage(config S3Config) Storage {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	config.PublicUrl = strings.TrimRight(config.PublicUrl, "/")
+	return &S3Storage{
+		S3Config: config,
+		Client:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (storage *S3Storage) Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, storage.objectUrl(key), reader)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	return storage.do(req, unsignedPayload)
+}
+
+func (storage *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, storage.objectUrl(key), nil)
+	if err != nil {
+		return err
+	}
+
+	return storage.do(req, hashHex(""))
+}
+
+func (storage *S3Storage) URL(key string) string {
+	if storage.SignUrls {
+		return storage.presign(http.MethodGet, key, storage.UrlExpiry, time.Now().UTC())
+	}
+	if storage.PublicUrl != "" {
+		return storage.PublicUrl + "/" + uriEncode(key, false)
+	}
+	return storage.objectUrl(key)
+}
+
+// presign builds a query-string authenticated URL (SigV4) valid for expires.
+func (storage *S3Storage) presign(method string, key string, expires time.Duration, now time.Time) string {
+	objectUrl, err := url.Parse(storage.objectUrl(key))
+	if err != nil {
+		return storage.objectUrl(key)
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := date + "/" + storage.Region + "/s3/aws4_request"
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", storage.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", fmt.Sprintf("%d", int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		objectUrl.EscapedPath(),
+		canonicalQuery(query),
+		"host:" + objectUrl.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	signature := hex.EncodeToString(hmacSha256(storage.signingKey(date), stringToSign))
+	return objectUrl.Scheme + "://" + objectUrl.Host + objectUrl.EscapedPath() + "?" + canonicalQuery(query) + "&X-Amz-Signature=" + signature
+}
+
+func (storage *S3Storage) objectUrl(key string) string {
+	if storage.UsePathStyle {
+		return storage.Endpoint + "/" + storage.Bucket + "/" + uriEncode(key, false)
+	}
+
+	endpoint, err := url.Parse(storage.Endpoint)
+	if err != nil {
+		return storage.Endpoint + "/" + storage.Bucket + "/" + uriEncode(key, false)
+	}
+	return endpoint.Scheme + "://" + storage.Bucket + "." + endpoint.Host + "/" + uriEncode(key, false)
+}
+
+func (storage *S3Storage) do(req *http.Request, payloadHash string) error {
+	storage.sign(req, payloadHash, time.Now().UTC())
+
+	res, err := storage.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s %s", req.Method, req.URL.Path, res.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req.
+func (storage *S3Storage) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + storage.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	signature := hex.EncodeToString(hmacSha256(storage.signingKey(date), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		storage.AccessKey, scope, strings.Join(signedHeaders, ";"), signature,
+	))
+}
+
+func (storage *S3Storage) signingKey(date string) []byte {
+	key := hmacSha256([]byte("AWS4"+storage.SecretKey), date)
+	key = hmacSha256(key, storage.Region)
+	key = hmacSha256(key, "s3")
+	return hmacSha256(key, "aws4_request")
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		vals := values[key]
+		sort.Strings(vals)
+		for _, value := range vals {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode follows the SigV4 rules: everything except unreserved characters
+// is percent-encoded, and "/" is kept as-is inside object keys.
+func uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
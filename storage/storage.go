@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"uaspw2/config"
+)
+
+type Storage interface {
+	Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL returns a public URL, or a time-limited signed URL when the
+	// backend is configured to keep objects private.
+	URL(key string) string
+}
+
+func NewStorage() Storage {
+	switch config.StorageDriver {
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:     config.S3Endpoint,
+			Region:       config.S3Region,
+			Bucket:       config.S3Bucket,
+			AccessKey:    config.S3AccessKey,
+			SecretKey:    config.S3SecretKey,
+			PublicUrl:    config.S3PublicUrl,
+			UsePathStyle: config.S3UsePathStyle,
+			SignUrls:     config.S3SignUrls,
+			UrlExpiry:    config.S3UrlExpiry,
+		})
+	default:
+		return NewLocalStorage(config.StorageLocalDir, config.StorageLocalUrl)
+	}
+}
+
+// cleanKey rejects keys that would escape the storage root, e.g. "../main.go".
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid storage key")
+	}
+	return strings.TrimPrefix(cleaned, "/"), nil
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxResponseBody is how much of a receiver's response is kept in the
+// delivery log.
+const maxResponseBody = 4096
+
+type Request struct {
+	Url        string
+	Secret     string
+	Event      string
+	DeliveryId int
+	Payload    []byte
+}
+
+type Result struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+	Err        error
+}
+
+// Succeeded tells whether the receiver accepted the delivery with a 2xx.
+func (result Result) Succeeded() bool {
+	return result.Err == nil && result.StatusCode >= 200 && result.StatusCode < 300
+}
+
+type Client struct {
+	HttpClient *http.Client
+}
+
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		HttpClient: &http.Client{
+			Timeout: timeout,
+			// a redirect is reported as is instead of re-posting the payload
+			// to wherever it points
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts the payload once. Retrying is up to the caller.
+func (client *Client) Send(ctx context.Context, request Request) Result {
+	start := time.Now()
+	result := client.send(ctx, request)
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (client *Client) send(ctx context.Context, request Request) Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.Url, bytes.NewReader(request.Payload))
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "uaspw2-webhooks")
+	req.Header.Set("X-Webhook-Event", request.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(request.DeliveryId))
+	timestamp := time.Now().Unix()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(request.Secret, timestamp, request.Payload))
+
+	resp, err := client.HttpClient.Do(req)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	// drain the rest so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	return Result{StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+// Backoff returns how long to wait before retrying after the given number of
+// failed attempts: base, then doubling up to max.
+func Backoff(attempts int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientSend(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	payload := []byte(`{"event":"article.published"}`)
+	result := NewClient(time.Second).Send(context.Background(), Request{
+		Url:        server.URL,
+		Secret:     "secret",
+		Event:      "article.published",
+		DeliveryId: 42,
+		Payload:    payload,
+	})
+
+	if !result.Succeeded() || result.StatusCode != http.StatusNoContent || result.Duration <= 0 {
+		t.Fatalf("result = %+v, want a timed 204", result)
+	}
+	if received.Method != http.MethodPost || string(body) != string(payload) {
+		t.Errorf("request = %s %q", received.Method, body)
+	}
+	headers := map[string]string{
+		"Content-Type":       "application/json",
+		"X-Webhook-Event":    "article.published",
+		"X-Webhook-Delivery": "42",
+	}
+	for name, want := range headers {
+		if got := received.Header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if !Verify("secret", body, received.Header.Get(TimestampHeader), received.Header.Get(SignatureHeader), DefaultTolerance) {
+		t.Errorf("signature %q with timestamp %q does not verify", received.Header.Get(SignatureHeader), received.Header.Get(TimestampHeader))
+	}
+}
+
+func TestClientSendStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		succeeded bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusAccepted, true},
+		{http.StatusBadRequest, false},
+		{http.StatusGone, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, false},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte("response body"))
+			}))
+			defer server.Close()
+
+			result := NewClient(time.Second).Send(context.Background(), Request{Url: server.URL, Payload: []byte("{}")})
+			if result.Err != nil || result.StatusCode != test.status || result.Body != "response body" {
+				t.Fatalf("result = %+v", result)
+			}
+			if result.Succeeded() != test.succeeded {
+				t.Errorf("succeeded = %v, want %v", result.Succeeded(), test.succeeded)
+			}
+		})
+	}
+}
+
+func TestClientSendRetryAfterServerError(t *testing.T) {
+	var attempts atomic.Int32
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get(SignatureHeader))
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+	request := Request{Url: server.URL, Secret: "secret", Event: "comment.created", DeliveryId: 1, Payload: []byte("{}")}
+	if result := client.Send(context.Background(), request); result.Succeeded() || result.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("first attempt = %+v, want a failed 503", result)
+	}
+	if result := client.Send(context.Background(), request); !result.Succeeded() {
+		t.Fatalf("retry = %+v, want success", result)
+	}
+	if attempts.Load() != 2 || signatures[1] == "" {
+		t.Errorf("server saw %d attempts with signatures %q", attempts.Load(), signatures)
+	}
+}
+
+func TestClientSendDoesNotFollowRedirects(t *testing.T) {
+	var followed atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/elsewhere", func(w http.ResponseWriter, r *http.Request) {
+		followed.Store(true)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	result := NewClient(time.Second).Send(context.Background(), Request{Url: server.URL + "/hook", Payload: []byte("{}")})
+	if result.Err != nil || result.StatusCode != http.StatusTemporaryRedirect || result.Succeeded() {
+		t.Errorf("result = %+v, want an unsuccessful 307", result)
+	}
+	if followed.Load() {
+		t.Error("the payload was posted to the redirect target")
+	}
+}
+
+func TestClientSendKeepsStartOfLongBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 3*maxResponseBody)))
+	}))
+	defer server.Close()
+
+	result := NewClient(time.Second).Send(context.Background(), Request{Url: server.URL, Payload: []byte("{}")})
+	if !result.Succeeded() || len(result.Body) != maxResponseBody {
+		t.Errorf("result = status %d, %d body bytes, want %d", result.StatusCode, len(result.Body), maxResponseBody)
+	}
+}
+
+func TestClientSendErrors(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"timeout", slow.URL},
+		{"connection refused", closed.URL},
+		{"invalid url", "://nowhere"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := NewClient(50*time.Millisecond).Send(context.Background(), Request{Url: test.url, Payload: []byte("{}")})
+			if result.Err == nil || result.Succeeded() {
+				t.Errorf("result = %+v, want an error", result)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{5, 16 * time.Minute},
+		{6, 30 * time.Minute},
+		{100, 30 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := Backoff(test.attempts, time.Minute, 30*time.Minute); got != test.want {
+			t.Errorf("Backoff(%d) = %s, want %s", test.attempts, got, test.want)
+		}
+	}
+	if got := Backoff(3, time.Hour, time.Minute); got != time.Minute {
+		t.Errorf("Backoff with base above max = %s, want the max", got)
+	}
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of TimestampHeader, a dot and the
+// request body, keyed with the webhook secret, as "sha256=<hex>".
+const SignatureHeader = "X-Webhook-Signature-256"
+
+// TimestampHeader carries the Unix time the request was signed at. Signing
+// it lets receivers refuse a captured request replayed later.
+const TimestampHeader = "X-Webhook-Timestamp"
+
+// DefaultTolerance is how far the timestamp of a request may be from the
+// receiver's clock.
+const DefaultTolerance = 5 * time.Minute
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether signature was made by Sign with the same secret and
+// timestamp, and the timestamp is within tolerance of now. It is what
+// receivers should run on every request.
+func Verify(secret string, body []byte, timestamp string, signature string, tolerance time.Duration) bool {
+	return verifyAt(time.Now(), secret, body, timestamp, signature, tolerance)
+}
+
+func verifyAt(now time.Time, secret string, body []byte, timestamp string, signature string, tolerance time.Duration) bool {
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(signedAt, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, signedAt, body)), []byte(signature))
+}
+
+// NewSecret returns a random secret for a new webhook.
+func NewSecret() string {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(secret)
+}
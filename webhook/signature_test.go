@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"event":"ping"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=4d39bd2442f073b6bc62e95d0297ce25475582a17389ab860abdc778fe1d9f77"
+	got := Sign("secret", 1700000000, []byte(`{"event":"ping"}`))
+	if got != want {
+		t.Fatalf("Sign = %q, want %q", got, want)
+	}
+	if got != Sign("secret", 1700000000, []byte(`{"event":"ping"}`)) {
+		t.Error("Sign is not deterministic")
+	}
+	if got == Sign("secret", 1700000001, []byte(`{"event":"ping"}`)) {
+		t.Error("the timestamp is not signed")
+	}
+	if got == Sign("other", 1700000000, []byte(`{"event":"ping"}`)) {
+		t.Error("the secret is not used")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"comment.created"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign("secret", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		secret    string
+		body      []byte
+		timestamp string
+		signature string
+		want      bool
+	}{
+		{"valid", now, "secret", body, timestamp, signature, true},
+		{"within tolerance", now.Add(4 * time.Minute), "secret", body, timestamp, signature, true},
+		{"clock behind within tolerance", now.Add(-4 * time.Minute), "secret", body, timestamp, signature, true},
+		{"too old", now.Add(6 * time.Minute), "secret", body, timestamp, signature, false},
+		{"from the future", now.Add(-6 * time.Minute), "secret", body, timestamp, signature, false},
+		{"wrong secret", now, "other", body, timestamp, signature, false},
+		{"changed body", now, "secret", []byte(`{"event":"comment.deleted"}`), timestamp, signature, false},
+		{"changed timestamp", now, "secret", body, strconv.FormatInt(now.Unix()+1, 10), signature, false},
+		{"malformed timestamp", now, "secret", body, "yesterday", signature, false},
+		{"missing timestamp", now, "secret", body, "", signature, false},
+		{"missing signature", now, "secret", body, timestamp, "", false},
+		{"signature without prefix", now, "secret", body, timestamp, signature[len("sha256="):], false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := verifyAt(test.now, test.secret, test.body, test.timestamp, test.signature, DefaultTolerance); got != test.want {
+				t.Errorf("verify = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVerifyUsesTheClock(t *testing.T) {
+	body := []byte("{}")
+	now := time.Now().Unix()
+	if !Verify("secret", body, strconv.FormatInt(now, 10), Sign("secret", now, body), DefaultTolerance) {
+		t.Error("a fresh signature was refused")
+	}
+	old := now - int64(time.Hour.Seconds())
+	if Verify("secret", body, strconv.FormatInt(old, 10), Sign("secret", old, body), DefaultTolerance) {
+		t.Error("an hour old signature was accepted")
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		secret := NewSecret()
+		if len(secret) != 48 {
+			t.Fatalf("secret %q is %d characters, want 48", secret, len(secret))
+		}
+		if seen[secret] {
+			t.Fatalf("secret %q repeated", secret)
+		}
+		seen[secret] = true
+	}
+}